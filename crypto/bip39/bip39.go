@@ -0,0 +1,152 @@
+// Package bip39 implements mnemonic recovery phrases as described in
+// https://github.com/bitcoin/bips/blob/master/bip-0039.mediawiki using the English wordlist.
+//
+// A mnemonic encodes entropy along with a checksum as a sequence of words that is easy for a
+// person to write down. The mnemonic (and an optional passphrase) is then stretched into a seed
+// from which keys can be derived (see [github.com/tbd54566975/web5-go/crypto/hd]).
+package bip39
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tbd54566975/web5-go/crypto"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+//go:embed english.txt
+var english string
+
+// Wordlist is the English wordlist from https://github.com/bitcoin/bips/blob/master/bip-0039/english.txt
+var Wordlist = strings.Fields(english)
+
+var wordIndex = func() map[string]int {
+	index := make(map[string]int, len(Wordlist))
+	for i, word := range Wordlist {
+		index[word] = i
+	}
+
+	return index
+}()
+
+// SeedSize is the size in bytes of the seed returned by [NewSeed]
+const SeedSize = 64
+
+// GenerateMnemonic generates a new mnemonic from freshly generated entropy of the given size. Valid
+// sizes are 16, 20, 24, 28 and 32 bytes which result in 12, 15, 18, 21 and 24 words respectively.
+// [crypto.Entropy128] and [crypto.Entropy256] are the most common choices.
+func GenerateMnemonic(size crypto.EntropySize) (string, error) {
+	if err := validateEntropySize(int(size)); err != nil {
+		return "", err
+	}
+
+	entropy, err := crypto.GenerateEntropy(size)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate entropy: %w", err)
+	}
+
+	return NewMnemonic(entropy)
+}
+
+// NewMnemonic encodes the given entropy as a mnemonic
+func NewMnemonic(entropy []byte) (string, error) {
+	if err := validateEntropySize(len(entropy)); err != nil {
+		return "", err
+	}
+
+	checksumBits := len(entropy) / 4
+	checksum := sha256.Sum256(entropy)
+
+	// entropy followed by the first checksumBits of its sha256 hash, read 11 bits at a time
+	bits := append(append([]byte{}, entropy...), checksum[0])
+
+	wordCount := (len(entropy)*8 + checksumBits) / 11
+	words := make([]string, wordCount)
+	for i := range words {
+		index := 0
+		for j := 0; j < 11; j++ {
+			bit := i*11 + j
+			index = index<<1 | int(bits[bit/8]>>(7-bit%8)&1)
+		}
+
+		words[i] = Wordlist[index]
+	}
+
+	return strings.Join(words, " "), nil
+}
+
+// MnemonicToEntropy decodes the given mnemonic back into the entropy it encodes. An error is
+// returned if the mnemonic contains unknown words, has an invalid length or fails the checksum.
+func MnemonicToEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(norm.NFKD.String(mnemonic))
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, fmt.Errorf("invalid mnemonic: expected 12, 15, 18, 21 or 24 words, got %d", len(words))
+	}
+
+	totalBits := len(words) * 11
+	checksumBits := totalBits / 33
+	entropyBytes := (totalBits - checksumBits) / 8
+
+	bits := make([]byte, (totalBits+7)/8)
+	for i, word := range words {
+		index, ok := wordIndex[word]
+		if !ok {
+			return nil, fmt.Errorf("invalid mnemonic: unknown word %q", word)
+		}
+
+		for j := 0; j < 11; j++ {
+			if index>>(10-j)&1 == 1 {
+				bit := i*11 + j
+				bits[bit/8] |= 1 << (7 - bit%8)
+			}
+		}
+	}
+
+	entropy := bits[:entropyBytes]
+	checksum := sha256.Sum256(entropy)
+
+	mask := byte(0xff << (8 - checksumBits))
+	if bits[entropyBytes]&mask != checksum[0]&mask {
+		return nil, errors.New("invalid mnemonic: checksum mismatch")
+	}
+
+	return entropy, nil
+}
+
+// ValidateMnemonic returns an error if the given mnemonic is not valid
+func ValidateMnemonic(mnemonic string) error {
+	_, err := MnemonicToEntropy(mnemonic)
+	return err
+}
+
+// NewSeed validates the given mnemonic and stretches it, along with the given (possibly empty)
+// passphrase, into a 64 byte seed using PBKDF2-HMAC-SHA512 as described in
+// https://github.com/bitcoin/bips/blob/master/bip-0039.mediawiki#from-mnemonic-to-seed
+//
+// # Note
+//
+// Different passphrases result in entirely different seeds, and therefore different keys. There
+// is no way to detect an incorrect passphrase.
+func NewSeed(mnemonic string, passphrase string) ([]byte, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+
+	password := strings.Join(strings.Fields(norm.NFKD.String(mnemonic)), " ")
+	salt := "mnemonic" + norm.NFKD.String(passphrase)
+
+	return pbkdf2.Key([]byte(password), []byte(salt), 2048, SeedSize, sha512.New), nil
+}
+
+func validateEntropySize(size int) error {
+	if size < 16 || size > 32 || size%4 != 0 {
+		return fmt.Errorf("entropy must be 16, 20, 24, 28 or 32 bytes, got %d", size)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package bip39_test
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/crypto"
+	"github.com/tbd54566975/web5-go/crypto/bip39"
+)
+
+// vectors from https://github.com/trezor/python-mnemonic/blob/master/vectors.json
+var vectors = []struct {
+	entropy  string
+	mnemonic string
+	seed     string
+}{
+	{
+		entropy:  "00000000000000000000000000000000",
+		mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		seed:     "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+	},
+	{
+		entropy:  "7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		mnemonic: "legal winner thank year wave sausage worth useful legal winner thank yellow",
+		seed:     "2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+	},
+	{
+		entropy:  "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		mnemonic: "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+		seed:     "dd48c104698c30cfe2b6142103248622fb7bb0ff692eebb00089b32d22484e1613912f0a5b694407be899ffd31ed3992c456cdf60f5d4564b8ba3f05a69890ad",
+	},
+}
+
+func TestVectors(t *testing.T) {
+	for _, v := range vectors {
+		t.Run(v.entropy, func(t *testing.T) {
+			entropy, err := hex.DecodeString(v.entropy)
+			assert.NoError(t, err)
+
+			mnemonic, err := bip39.NewMnemonic(entropy)
+			assert.NoError(t, err)
+			assert.Equal(t, v.mnemonic, mnemonic)
+
+			decoded, err := bip39.MnemonicToEntropy(mnemonic)
+			assert.NoError(t, err)
+			assert.Equal(t, entropy, decoded)
+
+			seed, err := bip39.NewSeed(mnemonic, "TREZOR")
+			assert.NoError(t, err)
+			assert.Equal(t, v.seed, hex.EncodeToString(seed))
+		})
+	}
+}
+
+func TestGenerateMnemonic(t *testing.T) {
+	mnemonic, err := bip39.GenerateMnemonic(crypto.Entropy256)
+	assert.NoError(t, err)
+	assert.Equal(t, 24, len(strings.Fields(mnemonic)))
+	assert.NoError(t, bip39.ValidateMnemonic(mnemonic))
+}
+
+func TestGenerateMnemonic_InvalidSize(t *testing.T) {
+	_, err := bip39.GenerateMnemonic(crypto.Entropy112)
+	assert.Error(t, err)
+}
+
+func TestValidateMnemonic_Invalid(t *testing.T) {
+	// checksum mismatch
+	assert.Error(t, bip39.ValidateMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon"))
+	// unknown word
+	assert.Error(t, bip39.ValidateMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon web5"))
+	// invalid length
+	assert.Error(t, bip39.ValidateMnemonic("abandon abandon abandon about"))
+}
@@ -1,6 +1,9 @@
 // Package crypto provides the following functionality:
-// * Key Generation: secp256k1, ed25519
-// * Signing: secp256k1, ed25519
-// * Verification: secp256k1, ed25519
+// * Key Generation: secp256k1, secp256r1 (P-256), secp384r1 (P-384), secp521r1 (P-521), ed25519
+// * Signing: secp256k1, secp256r1 (P-256), secp384r1 (P-384), secp521r1 (P-521), ed25519
+// * Verification: secp256k1, secp256r1 (P-256), secp384r1 (P-384), secp521r1 (P-521), ed25519
+// * Key Agreement (ECDH): x25519, secp256k1, secp256r1 (P-256), secp384r1 (P-384), secp521r1 (P-521)
 // * A KeyManager abstraction that can be leveraged to manage/use keys (create, sign etc) as desired per the given use case
+// * KeyManager implementations that store keys in memory or encrypted at rest on disk
+// * Mnemonic recovery phrases (see the bip39 package) and deterministic key derivation (see the hd package)
 package crypto
@@ -0,0 +1,133 @@
+package dsa
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/tbd54566975/web5-go/crypto/dsa/eddsa"
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+// ed25519BatchSize is the number of Ed25519 signatures verified together. Larger batches are faster per
+// signature but a single invalid signature causes the whole batch to be re-verified one by one.
+const ed25519BatchSize = 64
+
+// BatchItem is a single signature to be verified by [BatchVerify]
+type BatchItem struct {
+	Payload   []byte
+	Signature []byte
+	PublicKey jwk.JWK
+}
+
+// BatchResult is the outcome of verifying a single [BatchItem]. Verified and Err have the same meaning as
+// the values returned by [Verify].
+type BatchResult struct {
+	Verified bool
+	Err      error
+}
+
+// MaxWorkers is an option that can be passed to [BatchVerify] to limit the number of signatures verified
+// concurrently. Defaults to GOMAXPROCS. It has no effect on [Verify].
+func MaxWorkers(n int) VerifyOpt {
+	return func(opts *verifyOpts) {
+		opts.maxWorkers = n
+	}
+}
+
+// BatchVerify verifies many signatures at once and returns a result for each item, in the same order.
+// Ed25519 signatures are verified using batch verification (see [eddsa.ED25519VerifyBatch]) when the
+// [PolicyLenient] policy is in effect, unless their R or public key is non-canonically encoded or of small
+// order. All other signatures are verified with [Verify] using a bounded pool of workers (see [MaxWorkers]).
+func BatchVerify(items []BatchItem, opts ...VerifyOpt) []BatchResult {
+	o := verifyOpts{policy: DefaultPolicy(), maxWorkers: runtime.GOMAXPROCS(0)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	results := make([]BatchResult, len(items))
+
+	verifyOne := func(i int) {
+		item := items[i]
+		verified, err := Verify(item.Payload, item.Signature, item.PublicKey, VerificationPolicy(o.policy))
+		results[i] = BatchResult{Verified: verified, Err: err}
+	}
+
+	var tasks []func()
+	var ed25519Batch []int
+
+	flushED25519Batch := func() {
+		batch := ed25519Batch
+		ed25519Batch = nil
+
+		tasks = append(tasks, func() {
+			payloads := make([][]byte, len(batch))
+			signatures := make([][]byte, len(batch))
+			publicKeys := make([]jwk.JWK, len(batch))
+			for j, i := range batch {
+				payloads[j] = items[i].Payload
+				signatures[j] = items[i].Signature
+				publicKeys[j] = items[i].PublicKey
+			}
+
+			if ok, _ := eddsa.ED25519VerifyBatch(payloads, signatures, publicKeys); ok {
+				for _, i := range batch {
+					results[i] = BatchResult{Verified: true}
+				}
+
+				return
+			}
+
+			// at least one signature is invalid. fall back to verifying each signature to find out which
+			for _, i := range batch {
+				verifyOne(i)
+			}
+		})
+	}
+
+	for i, item := range items {
+		if o.policy == PolicyLenient && item.PublicKey.KTY == eddsa.KeyType && item.PublicKey.CRV == eddsa.ED25519JWACurve &&
+			eddsa.ED25519CanVerifyInBatch(item.Signature, item.PublicKey) {
+			ed25519Batch = append(ed25519Batch, i)
+			if len(ed25519Batch) == ed25519BatchSize {
+				flushED25519Batch()
+			}
+
+			continue
+		}
+
+		tasks = append(tasks, func() { verifyOne(i) })
+	}
+
+	if len(ed25519Batch) > 0 {
+		flushED25519Batch()
+	}
+
+	runTasks(tasks, o.maxWorkers)
+
+	return results
+}
+
+// runTasks runs the given tasks using at most the given number of goroutines and waits for all of them to finish
+func runTasks(tasks []func(), workers int) {
+	workers = max(1, min(workers, len(tasks)))
+
+	jobs := make(chan func())
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range jobs {
+				task()
+			}
+		}()
+	}
+
+	for _, task := range tasks {
+		jobs <- task
+	}
+
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,128 @@
+package dsa
+
+import (
+	"crypto/rand"
+	"io"
+
+	"github.com/tbd54566975/web5-go/crypto/dsa/ecdsa"
+	"github.com/tbd54566975/web5-go/crypto/dsa/eddsa"
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+func init() {
+	Register(ecdsaAlgorithm(AlgorithmIDSECP256K1, ecdsa.SECP256K1JWACurve, ecdsa.SECP256K1JWA))
+	Register(ecdsaAlgorithm(AlgorithmIDSECP256R1, ecdsa.SECP256R1JWACurve, ecdsa.SECP256R1JWA))
+	Register(ecdsaAlgorithm(AlgorithmIDSECP384R1, ecdsa.SECP384R1JWACurve, ecdsa.SECP384R1JWA))
+	Register(ecdsaAlgorithm(AlgorithmIDSECP521R1, ecdsa.SECP521R1JWACurve, ecdsa.SECP521R1JWA))
+	Register(eddsaAlgorithm(AlgorithmIDED25519, eddsa.ED25519JWACurve))
+}
+
+// builtin adapts the algorithm families implemented by the ecdsa and eddsa packages to [Algorithm]
+type builtin struct {
+	id      string
+	keyType string
+	curve   string
+	jwa     string
+
+	generatePrivateKey func(algorithmID string, rand io.Reader) (jwk.JWK, error)
+	getPublicKey       func(privateKey jwk.JWK) jwk.JWK
+	sign               func(payload []byte, privateKey jwk.JWK) ([]byte, error)
+	verify             func(payload []byte, signature []byte, publicKey jwk.JWK) (bool, error)
+	signStrict         func(payload []byte, privateKey jwk.JWK) ([]byte, error)
+	verifyStrict       func(payload []byte, signature []byte, publicKey jwk.JWK) (bool, error)
+	bytesToPublicKey   func(algorithmID string, input []byte) (jwk.JWK, error)
+	publicKeyToBytes   func(publicKey jwk.JWK) ([]byte, error)
+
+	signWithPrivateKeyBytes       func(payload []byte, curve string, privateKeyBytes []byte) ([]byte, error)
+	signStrictWithPrivateKeyBytes func(payload []byte, curve string, privateKeyBytes []byte) ([]byte, error)
+}
+
+func ecdsaAlgorithm(id string, curve string, jwa string) builtin {
+	return builtin{
+		id:                 id,
+		keyType:            ecdsa.KeyType,
+		curve:              curve,
+		jwa:                jwa,
+		generatePrivateKey: ecdsa.GeneratePrivateKeyFromReader,
+		getPublicKey:       ecdsa.GetPublicKey,
+		sign:               ecdsa.Sign,
+		verify:             ecdsa.Verify,
+		signStrict:         ecdsa.SignStrict,
+		verifyStrict:       ecdsa.VerifyStrict,
+		bytesToPublicKey:   ecdsa.BytesToPublicKey,
+		publicKeyToBytes:   ecdsa.PublicKeyToBytes,
+
+		signWithPrivateKeyBytes:       ecdsa.SignWithPrivateKeyBytes,
+		signStrictWithPrivateKeyBytes: ecdsa.SignStrictWithPrivateKeyBytes,
+	}
+}
+
+func eddsaAlgorithm(id string, curve string) builtin {
+	return builtin{
+		id:                 id,
+		keyType:            eddsa.KeyType,
+		curve:              curve,
+		jwa:                eddsa.JWA,
+		generatePrivateKey: eddsa.GeneratePrivateKeyFromReader,
+		getPublicKey:       eddsa.GetPublicKey,
+		sign:               eddsa.Sign,
+		verify:             eddsa.Verify,
+		signStrict:         eddsa.SignStrict,
+		verifyStrict:       eddsa.VerifyStrict,
+		bytesToPublicKey:   eddsa.BytesToPublicKey,
+		publicKeyToBytes:   eddsa.PublicKeyToBytes,
+
+		// EdDSA signatures are deterministic and canonical, strict signing is the same as regular signing
+		signWithPrivateKeyBytes:       eddsa.SignWithPrivateKeyBytes,
+		signStrictWithPrivateKeyBytes: eddsa.SignWithPrivateKeyBytes,
+	}
+}
+
+func (b builtin) ID() string      { return b.id }
+func (b builtin) KeyType() string { return b.keyType }
+func (b builtin) Curve() string   { return b.curve }
+func (b builtin) JWA() string     { return b.jwa }
+
+func (b builtin) GeneratePrivateKey() (jwk.JWK, error) {
+	return b.generatePrivateKey(b.id, rand.Reader)
+}
+
+func (b builtin) GeneratePrivateKeyFromReader(rand io.Reader) (jwk.JWK, error) {
+	return b.generatePrivateKey(b.id, rand)
+}
+
+func (b builtin) GetPublicKey(privateKey jwk.JWK) jwk.JWK {
+	return b.getPublicKey(privateKey)
+}
+
+func (b builtin) Sign(payload []byte, privateKey jwk.JWK) ([]byte, error) {
+	return b.sign(payload, privateKey)
+}
+
+func (b builtin) Verify(payload []byte, signature []byte, publicKey jwk.JWK) (bool, error) {
+	return b.verify(payload, signature, publicKey)
+}
+
+func (b builtin) SignStrict(payload []byte, privateKey jwk.JWK) ([]byte, error) {
+	return b.signStrict(payload, privateKey)
+}
+
+func (b builtin) VerifyStrict(payload []byte, signature []byte, publicKey jwk.JWK) (bool, error) {
+	return b.verifyStrict(payload, signature, publicKey)
+}
+
+func (b builtin) SignWithPrivateKeyBytes(payload []byte, privateKeyBytes []byte) ([]byte, error) {
+	return b.signWithPrivateKeyBytes(payload, b.curve, privateKeyBytes)
+}
+
+func (b builtin) SignStrictWithPrivateKeyBytes(payload []byte, privateKeyBytes []byte) ([]byte, error) {
+	return b.signStrictWithPrivateKeyBytes(payload, b.curve, privateKeyBytes)
+}
+
+func (b builtin) BytesToPublicKey(input []byte) (jwk.JWK, error) {
+	return b.bytesToPublicKey(b.id, input)
+}
+
+func (b builtin) PublicKeyToBytes(publicKey jwk.JWK) ([]byte, error) {
+	return b.publicKeyToBytes(publicKey)
+}
@@ -1,7 +1,11 @@
 package dsa
 
 import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
 
 	"github.com/tbd54566975/web5-go/crypto/dsa/ecdsa"
 	"github.com/tbd54566975/web5-go/crypto/dsa/eddsa"
@@ -10,99 +14,211 @@ import (
 
 const (
 	AlgorithmIDSECP256K1 = ecdsa.SECP256K1AlgorithmID
+	AlgorithmIDSECP256R1 = ecdsa.SECP256R1AlgorithmID
+	AlgorithmIDSECP384R1 = ecdsa.SECP384R1AlgorithmID
+	AlgorithmIDSECP521R1 = ecdsa.SECP521R1AlgorithmID
 	AlgorithmIDED25519   = eddsa.ED25519AlgorithmID
 )
 
+// options that [GeneratePrivateKey] can take
+type generateOpts struct {
+	rand io.Reader
+}
+
+// GenerateOpt is a type that represents an option that can be passed to [GeneratePrivateKey]
+type GenerateOpt func(opts *generateOpts)
+
+// EntropySource is an option that can be passed to [GeneratePrivateKey]. It sets the source of randomness
+// used to generate the key. The same entropy always results in the same key which makes this useful for
+// reproducible test fixtures. Defaults to crypto/rand when nil or not provided. Any other source requires
+// the algorithm to implement [EntropyAlgorithm]
+func EntropySource(rand io.Reader) GenerateOpt {
+	return func(opts *generateOpts) {
+		opts.rand = rand
+	}
+}
+
 // GeneratePrivateKey generates a private key using the algorithm specified by algorithmID.
-func GeneratePrivateKey(algorithmID string) (jwk.JWK, error) {
-	if ecdsa.SupportsAlgorithmID(algorithmID) {
-		return ecdsa.GeneratePrivateKey(algorithmID)
-	} else if eddsa.SupportsAlgorithmID(algorithmID) {
-		return eddsa.GeneratePrivateKey(algorithmID)
+func GeneratePrivateKey(algorithmID string, opts ...GenerateOpt) (jwk.JWK, error) {
+	alg, ok := Lookup(algorithmID)
+	if !ok {
+		return jwk.JWK{}, fmt.Errorf("unsupported algorithm: %s", algorithmID)
 	}
 
-	return jwk.JWK{}, fmt.Errorf("unsupported algorithm: %s", algorithmID)
+	o := generateOpts{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.rand == nil || o.rand == rand.Reader {
+		return alg.GeneratePrivateKey()
+	}
+
+	entropyAlg, ok := alg.(EntropyAlgorithm)
+	if !ok {
+		return jwk.JWK{}, fmt.Errorf("entropy source not supported for algorithm: %s", alg.ID())
+	}
+
+	return entropyAlg.GeneratePrivateKeyFromReader(o.rand)
 }
 
 // GetPublicKey returns the public key corresponding to the given private key.
 func GetPublicKey(privateKey jwk.JWK) jwk.JWK {
-	switch privateKey.KTY {
-	case ecdsa.KeyType:
-		return ecdsa.GetPublicKey(privateKey)
-	case eddsa.KeyType:
-		return eddsa.GetPublicKey(privateKey)
-	default:
+	alg, err := lookupByKey(privateKey)
+	if err != nil {
 		return jwk.JWK{}
 	}
+
+	return alg.GetPublicKey(privateKey)
+}
+
+// Sign signs the payload using the given private key. The signature is produced according to the
+// [DefaultPolicy] unless a [SigningPolicy] is provided.
+func Sign(payload []byte, jwk jwk.JWK, opts ...SignOpt) ([]byte, error) {
+	alg, err := lookupByKey(jwk)
+	if err != nil {
+		return nil, err
+	}
+
+	if jwk.D == "" {
+		return nil, errors.New("d must be set")
+	}
+
+	o := signOpts{policy: DefaultPolicy()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.policy != PolicyStrict {
+		return alg.Sign(payload, jwk)
+	}
+
+	strictAlg, ok := alg.(StrictAlgorithm)
+	if !ok {
+		return nil, fmt.Errorf("strict signing not supported for algorithm: %s", alg.ID())
+	}
+
+	return strictAlg.SignStrict(payload, jwk)
+}
+
+// SignWithPrivateKeyBytes signs the payload like [Sign], using the raw private key, i.e. the decoded d of a
+// private JWK, instead of a private JWK. key identifies the algorithm and may be the public key. This lets key
+// managers that hold raw private keys sign without encoding them first. The private key bytes are neither
+// modified nor retained. For algorithms that don't implement [PrivateKeyBytesAlgorithm], a private JWK is
+// built for the duration of the operation.
+func SignWithPrivateKeyBytes(payload []byte, key jwk.JWK, privateKeyBytes []byte, opts ...SignOpt) ([]byte, error) {
+	alg, err := lookupByKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(privateKeyBytes) == 0 {
+		return nil, errors.New("d must be set")
+	}
+
+	o := signOpts{policy: DefaultPolicy()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.policy == PolicyStrict {
+		if _, ok := alg.(StrictAlgorithm); !ok {
+			return nil, fmt.Errorf("strict signing not supported for algorithm: %s", alg.ID())
+		}
+	}
+
+	bytesAlg, ok := alg.(PrivateKeyBytesAlgorithm)
+	if !ok {
+		key.D = base64.RawURLEncoding.EncodeToString(privateKeyBytes)
+		return Sign(payload, key, opts...)
+	}
+
+	if o.policy == PolicyStrict {
+		return bytesAlg.SignStrictWithPrivateKeyBytes(payload, privateKeyBytes)
+	}
+
+	return bytesAlg.SignWithPrivateKeyBytes(payload, privateKeyBytes)
 }
 
-// Sign signs the payload using the given private key.
-func Sign(payload []byte, jwk jwk.JWK) ([]byte, error) {
-	switch jwk.KTY {
-	case ecdsa.KeyType:
-		return ecdsa.Sign(payload, jwk)
-	case eddsa.KeyType:
-		return eddsa.Sign(payload, jwk)
-	default:
-		return nil, fmt.Errorf("unsupported key type: %s", jwk.KTY)
+// Verify verifies the signature of the payload using the given public key. The signature is checked
+// according to the [DefaultPolicy] unless a [VerificationPolicy] is provided.
+func Verify(payload []byte, signature []byte, jwk jwk.JWK, opts ...VerifyOpt) (bool, error) {
+	alg, err := lookupByKey(jwk)
+	if err != nil {
+		return false, err
 	}
+
+	o := verifyOpts{policy: DefaultPolicy()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.policy != PolicyStrict {
+		return alg.Verify(payload, signature, jwk)
+	}
+
+	strictAlg, ok := alg.(StrictAlgorithm)
+	if !ok {
+		return false, fmt.Errorf("strict verification not supported for algorithm: %s", alg.ID())
+	}
+
+	return strictAlg.VerifyStrict(payload, signature, jwk)
 }
 
-// Verify verifies the signature of the payload using the given public key.
-func Verify(payload []byte, signature []byte, jwk jwk.JWK) (bool, error) {
-	switch jwk.KTY {
-	case ecdsa.KeyType:
-		return ecdsa.Verify(payload, signature, jwk)
-	case eddsa.KeyType:
-		return eddsa.Verify(payload, signature, jwk)
-	default:
-		return false, fmt.Errorf("unsupported key type: %s", jwk.KTY)
+// SignRecoverable signs the payload using the given private key and returns a signature from which the
+// signer's public key can be recovered with [RecoverPublicKey]. Only secp256k1 keys are supported, producing
+// ES256K-R signatures
+func SignRecoverable(payload []byte, privateKey jwk.JWK) ([]byte, error) {
+	if privateKey.KTY != ecdsa.KeyType {
+		return nil, fmt.Errorf("public key recovery not supported for key type: %s", privateKey.KTY)
 	}
+
+	return ecdsa.SignRecoverable(payload, privateKey)
+}
+
+// RecoverPublicKey recovers the public key that produced the given ES256K-R signature over the payload.
+// This allows signatures to be verified when the signer's public key is not published, provided the
+// caller checks the recovered key (or a value derived from it, e.g. an address) against what it expects.
+func RecoverPublicKey(payload []byte, signature []byte) (jwk.JWK, error) {
+	return ecdsa.RecoverPublicKey(payload, signature)
 }
 
 // GetJWA returns the JWA (JSON Web Algorithm) algorithm corresponding to the given key.
 func GetJWA(jwk jwk.JWK) (string, error) {
-	switch jwk.KTY {
-	case ecdsa.KeyType:
-		return ecdsa.GetJWA(jwk)
-	case eddsa.KeyType:
-		return eddsa.GetJWA(jwk)
-	default:
-		return "", fmt.Errorf("unsupported key type: %s", jwk.KTY)
+	alg, err := lookupByKey(jwk)
+	if err != nil {
+		return "", err
 	}
+
+	return alg.JWA(), nil
 }
 
 // BytesToPublicKey converts the given bytes to a public key based on the algorithm specified by algorithmID.
 func BytesToPublicKey(algorithmID string, input []byte) (jwk.JWK, error) {
-	if ecdsa.SupportsAlgorithmID(algorithmID) {
-		return ecdsa.BytesToPublicKey(algorithmID, input)
-	} else if eddsa.SupportsAlgorithmID(algorithmID) {
-		return eddsa.BytesToPublicKey(algorithmID, input)
+	alg, ok := Lookup(algorithmID)
+	if !ok {
+		return jwk.JWK{}, fmt.Errorf("unsupported algorithm: %s", algorithmID)
 	}
 
-	return jwk.JWK{}, fmt.Errorf("unsupported algorithm: %s", algorithmID)
+	return alg.BytesToPublicKey(input)
 }
 
 // PublicKeyToBytes converts the provided public key to bytes
 func PublicKeyToBytes(publicKey jwk.JWK) ([]byte, error) {
-	switch publicKey.KTY {
-	case ecdsa.KeyType:
-		return ecdsa.PublicKeyToBytes(publicKey)
-	case eddsa.KeyType:
-		return eddsa.PublicKeyToBytes(publicKey)
-	default:
-		return nil, fmt.Errorf("unsupported key type: %s", publicKey.KTY)
+	alg, err := lookupByKey(publicKey)
+	if err != nil {
+		return nil, err
 	}
+
+	return alg.PublicKeyToBytes(publicKey)
 }
 
 // AlgorithmID returns the algorithm ID for the given jwk.JWK
 func AlgorithmID(jwk *jwk.JWK) (string, error) {
-	switch jwk.KTY {
-	case ecdsa.KeyType:
-		return ecdsa.AlgorithmID(jwk)
-	case eddsa.KeyType:
-		return eddsa.AlgorithmID(jwk)
-	default:
-		return "", fmt.Errorf("unsupported key type: %s", jwk.KTY)
+	alg, err := lookupByKey(*jwk)
+	if err != nil {
+		return "", err
 	}
+
+	return alg.ID(), nil
 }
@@ -1,10 +1,18 @@
 package dsa_test
 
 import (
+	"bytes"
+	"crypto/elliptic"
+	"crypto/sha512"
+	"encoding/base64"
 	"encoding/hex"
+	"fmt"
+	"math/big"
 	"testing"
 
+	"filippo.io/edwards25519"
 	"github.com/alecthomas/assert/v2"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 	"github.com/tbd54566975/web5-go/crypto/dsa"
 	"github.com/tbd54566975/web5-go/crypto/dsa/ecdsa"
 	"github.com/tbd54566975/web5-go/crypto/dsa/eddsa"
@@ -22,6 +30,17 @@ func TestGeneratePrivateKeySECP256K1(t *testing.T) {
 	assert.True(t, privateJwk.Y != "", "privateJwk.Y is empty")
 }
 
+func TestGeneratePrivateKeySECP256R1(t *testing.T) {
+	privateJwk, err := dsa.GeneratePrivateKey(dsa.AlgorithmIDSECP256R1)
+
+	assert.NoError(t, err)
+	assert.Equal[string](t, ecdsa.SECP256R1JWACurve, privateJwk.CRV)
+	assert.Equal[string](t, ecdsa.KeyType, privateJwk.KTY)
+	assert.True(t, privateJwk.D != "", "privateJwk.D is empty")
+	assert.True(t, privateJwk.X != "", "privateJwk.X is empty")
+	assert.True(t, privateJwk.Y != "", "privateJwk.Y is empty")
+}
+
 func TestGeneratePrivateKeyED25519(t *testing.T) {
 	privateJwk, err := dsa.GeneratePrivateKey(dsa.AlgorithmIDED25519)
 	if err != nil {
@@ -71,6 +90,24 @@ func TestSignDeterministicSECP256K1(t *testing.T) {
 	assert.Equal(t, signature1, signature2, "signature is not deterministic")
 }
 
+func TestSignDeterministicNIST(t *testing.T) {
+	for _, algorithmID := range []string{dsa.AlgorithmIDSECP256R1, dsa.AlgorithmIDSECP384R1, dsa.AlgorithmIDSECP521R1} {
+		t.Run(algorithmID, func(t *testing.T) {
+			privateJwk, err := dsa.GeneratePrivateKey(algorithmID)
+			assert.NoError(t, err)
+
+			payload := []byte("hello world")
+			signature1, err := dsa.Sign(payload, privateJwk)
+			assert.NoError(t, err, "failed to sign")
+
+			signature2, err := dsa.Sign(payload, privateJwk)
+			assert.NoError(t, err)
+
+			assert.Equal(t, signature1, signature2, "signature is not deterministic")
+		})
+	}
+}
+
 func TestSignDeterministicED25519(t *testing.T) {
 	privateJwk, err := dsa.GeneratePrivateKey(dsa.AlgorithmIDED25519)
 	assert.NoError(t, err)
@@ -85,6 +122,49 @@ func TestSignDeterministicED25519(t *testing.T) {
 	assert.Equal(t, signature1, signature2, "signature is not deterministic")
 }
 
+func TestSignWithPrivateKeyBytes(t *testing.T) {
+	algorithmIDs := []string{
+		dsa.AlgorithmIDSECP256K1,
+		dsa.AlgorithmIDSECP256R1,
+		dsa.AlgorithmIDSECP384R1,
+		dsa.AlgorithmIDSECP521R1,
+		dsa.AlgorithmIDED25519,
+	}
+
+	for _, algorithmID := range algorithmIDs {
+		t.Run(algorithmID, func(t *testing.T) {
+			privateJwk, err := dsa.GeneratePrivateKey(algorithmID)
+			assert.NoError(t, err)
+
+			privateKeyBytes, err := base64.RawURLEncoding.DecodeString(privateJwk.D)
+			assert.NoError(t, err)
+
+			original := bytes.Clone(privateKeyBytes)
+			publicJwk := dsa.GetPublicKey(privateJwk)
+			payload := []byte("hello world")
+
+			signature, err := dsa.SignWithPrivateKeyBytes(payload, publicJwk, privateKeyBytes)
+			assert.NoError(t, err)
+			assert.Equal(t, original, privateKeyBytes)
+
+			// all built-in algorithms sign deterministically
+			expected, err := dsa.Sign(payload, privateJwk)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, signature)
+
+			signature, err = dsa.SignWithPrivateKeyBytes(payload, publicJwk, privateKeyBytes, dsa.SigningPolicy(dsa.PolicyStrict))
+			assert.NoError(t, err)
+
+			legit, err := dsa.Verify(payload, signature, publicJwk, dsa.VerificationPolicy(dsa.PolicyStrict))
+			assert.NoError(t, err)
+			assert.True(t, legit)
+
+			_, err = dsa.SignWithPrivateKeyBytes(payload, publicJwk, nil)
+			assert.EqualError(t, err, "d must be set")
+		})
+	}
+}
+
 func TestVerifySECP256K1(t *testing.T) {
 	privateJwk, err := dsa.GeneratePrivateKey(dsa.AlgorithmIDSECP256K1)
 	assert.NoError(t, err)
@@ -101,6 +181,75 @@ func TestVerifySECP256K1(t *testing.T) {
 	assert.True(t, legit, "failed to verify signature")
 }
 
+func TestVerifySECP256R1(t *testing.T) {
+	privateJwk, err := dsa.GeneratePrivateKey(dsa.AlgorithmIDSECP256R1)
+	assert.NoError(t, err)
+
+	payload := []byte("hello world")
+	signature, err := dsa.Sign(payload, privateJwk)
+	assert.NoError(t, err)
+	assert.True(t, len(signature) == 64, "invalid signature length")
+
+	publicJwk := dsa.GetPublicKey(privateJwk)
+
+	legit, err := dsa.Verify(payload, signature, publicJwk)
+	assert.NoError(t, err)
+
+	assert.True(t, legit, "failed to verify signature")
+
+	jwa, err := dsa.GetJWA(publicJwk)
+	assert.NoError(t, err)
+	assert.Equal(t, ecdsa.SECP256R1JWA, jwa)
+
+	algID, err := dsa.AlgorithmID(&publicJwk)
+	assert.NoError(t, err)
+	assert.Equal(t, dsa.AlgorithmIDSECP256R1, algID)
+}
+
+func TestVerifyNISTCurves(t *testing.T) {
+	vectors := []struct {
+		algorithmID string
+		jwa         string
+		sigLength   int
+	}{
+		{algorithmID: dsa.AlgorithmIDSECP384R1, jwa: ecdsa.SECP384R1JWA, sigLength: 96},
+		{algorithmID: dsa.AlgorithmIDSECP521R1, jwa: ecdsa.SECP521R1JWA, sigLength: 132},
+	}
+
+	for _, vec := range vectors {
+		t.Run(vec.algorithmID, func(t *testing.T) {
+			privateJwk, err := dsa.GeneratePrivateKey(vec.algorithmID)
+			assert.NoError(t, err)
+
+			payload := []byte("hello world")
+			signature, err := dsa.Sign(payload, privateJwk)
+			assert.NoError(t, err)
+			assert.Equal(t, vec.sigLength, len(signature))
+
+			publicJwk := dsa.GetPublicKey(privateJwk)
+
+			legit, err := dsa.Verify(payload, signature, publicJwk)
+			assert.NoError(t, err)
+			assert.True(t, legit, "failed to verify signature")
+
+			jwa, err := dsa.GetJWA(publicJwk)
+			assert.NoError(t, err)
+			assert.Equal(t, vec.jwa, jwa)
+
+			algID, err := dsa.AlgorithmID(&publicJwk)
+			assert.NoError(t, err)
+			assert.Equal(t, vec.algorithmID, algID)
+
+			publicKeyBytes, err := dsa.PublicKeyToBytes(publicJwk)
+			assert.NoError(t, err)
+
+			parsed, err := dsa.BytesToPublicKey(vec.algorithmID, publicKeyBytes)
+			assert.NoError(t, err)
+			assert.Equal(t, publicJwk, parsed)
+		})
+	}
+}
+
 func TestVerifyED25519(t *testing.T) {
 	privateJwk, err := dsa.GeneratePrivateKey(dsa.AlgorithmIDED25519)
 	assert.NoError(t, err)
@@ -147,3 +296,277 @@ func TestPublicKeyToBytes_UnsupportedKTY(t *testing.T) {
 	_, err := dsa.PublicKeyToBytes(jwk.JWK{KTY: "yolocrypto"})
 	assert.Error(t, err)
 }
+
+func TestGeneratePrivateKey_EntropySource(t *testing.T) {
+	builtins := []string{
+		dsa.AlgorithmIDED25519,
+		dsa.AlgorithmIDSECP256K1,
+		dsa.AlgorithmIDSECP256R1,
+		dsa.AlgorithmIDSECP384R1,
+		dsa.AlgorithmIDSECP521R1,
+	}
+
+	for _, algorithmID := range builtins {
+		t.Run(algorithmID, func(t *testing.T) {
+			entropy := bytes.Repeat([]byte{0x42}, 256)
+
+			first, err := dsa.GeneratePrivateKey(algorithmID, dsa.EntropySource(bytes.NewReader(entropy)))
+			assert.NoError(t, err)
+
+			second, err := dsa.GeneratePrivateKey(algorithmID, dsa.EntropySource(bytes.NewReader(entropy)))
+			assert.NoError(t, err)
+
+			assert.Equal(t, first, second)
+		})
+	}
+}
+
+func TestGeneratePrivateKey_EntropySourceED25519Vector(t *testing.T) {
+	// test 1 from https://datatracker.ietf.org/doc/html/rfc8032#section-7.1
+	seed, err := hex.DecodeString("9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60")
+	assert.NoError(t, err)
+
+	privateKey, err := dsa.GeneratePrivateKey(dsa.AlgorithmIDED25519, dsa.EntropySource(bytes.NewReader(seed)))
+	assert.NoError(t, err)
+
+	x, err := base64.RawURLEncoding.DecodeString(privateKey.X)
+	assert.NoError(t, err)
+	assert.Equal(t, "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a", hex.EncodeToString(x))
+}
+
+func TestGeneratePrivateKey_EntropySourceExhausted(t *testing.T) {
+	_, err := dsa.GeneratePrivateKey(dsa.AlgorithmIDSECP256K1, dsa.EntropySource(bytes.NewReader([]byte{1, 2, 3})))
+	assert.Error(t, err)
+}
+
+// highS returns the malleated (r, n - s) form of the given ECDSA signature
+func highS(signature []byte, n *big.Int) []byte {
+	size := len(signature) / 2
+	s := new(big.Int).SetBytes(signature[size:])
+	s.Sub(n, s)
+
+	malleated := bytes.Clone(signature)
+	s.FillBytes(malleated[size:])
+
+	return malleated
+}
+
+func TestVerify_StrictPolicyRejectsHighS(t *testing.T) {
+	vectors := []struct {
+		algorithmID string
+		n           *big.Int
+	}{
+		{algorithmID: dsa.AlgorithmIDSECP256K1, n: secp256k1.S256().N},
+		{algorithmID: dsa.AlgorithmIDSECP256R1, n: elliptic.P256().Params().N},
+		{algorithmID: dsa.AlgorithmIDSECP384R1, n: elliptic.P384().Params().N},
+		{algorithmID: dsa.AlgorithmIDSECP521R1, n: elliptic.P521().Params().N},
+	}
+
+	for _, vec := range vectors {
+		t.Run(vec.algorithmID, func(t *testing.T) {
+			privateJwk, err := dsa.GeneratePrivateKey(vec.algorithmID)
+			assert.NoError(t, err)
+
+			publicJwk := dsa.GetPublicKey(privateJwk)
+			payload := []byte("hello world")
+
+			signature, err := dsa.Sign(payload, privateJwk, dsa.SigningPolicy(dsa.PolicyStrict))
+			assert.NoError(t, err)
+
+			legit, err := dsa.Verify(payload, signature, publicJwk, dsa.VerificationPolicy(dsa.PolicyStrict))
+			assert.NoError(t, err)
+			assert.True(t, legit, "expected low-S signature to pass strict verification")
+
+			malleated := highS(signature, vec.n)
+
+			legit, err = dsa.Verify(payload, malleated, publicJwk)
+			assert.NoError(t, err)
+			assert.True(t, legit, "expected high-S signature to pass lenient verification")
+
+			_, err = dsa.Verify(payload, malleated, publicJwk, dsa.VerificationPolicy(dsa.PolicyStrict))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestVerify_StrictPolicyRejectsOverflowingScalars(t *testing.T) {
+	privateJwk, err := dsa.GeneratePrivateKey(dsa.AlgorithmIDSECP256K1)
+	assert.NoError(t, err)
+
+	publicJwk := dsa.GetPublicKey(privateJwk)
+	payload := []byte("hello world")
+
+	signature, err := dsa.Sign(payload, privateJwk)
+	assert.NoError(t, err)
+
+	copy(signature[:32], bytes.Repeat([]byte{0xff}, 32))
+
+	_, err = dsa.Verify(payload, signature, publicJwk, dsa.VerificationPolicy(dsa.PolicyStrict))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "overflow")
+}
+
+func TestVerify_StrictPolicyRejectsSmallOrderED25519(t *testing.T) {
+	// the identity point as public key and R with S = 0 is a valid signature over any payload
+	// under cofactorless verification
+	identity := append([]byte{0x01}, make([]byte, 31)...)
+	publicJwk := jwk.JWK{
+		KTY: eddsa.KeyType,
+		CRV: eddsa.ED25519JWACurve,
+		X:   base64.RawURLEncoding.EncodeToString(identity),
+	}
+
+	signature := append(bytes.Clone(identity), make([]byte, 32)...)
+	payload := []byte("hello world")
+
+	legit, err := dsa.Verify(payload, signature, publicJwk)
+	assert.NoError(t, err)
+	assert.True(t, legit)
+
+	_, err = dsa.Verify(payload, signature, publicJwk, dsa.VerificationPolicy(dsa.PolicyStrict))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "small order")
+}
+
+func TestSetDefaultPolicy(t *testing.T) {
+	dsa.SetDefaultPolicy(dsa.PolicyStrict)
+	defer dsa.SetDefaultPolicy(dsa.PolicyLenient)
+
+	assert.Equal(t, dsa.PolicyStrict, dsa.DefaultPolicy())
+
+	privateJwk, err := dsa.GeneratePrivateKey(dsa.AlgorithmIDSECP256R1)
+	assert.NoError(t, err)
+
+	publicJwk := dsa.GetPublicKey(privateJwk)
+	payload := []byte("hello world")
+
+	// without the strict default, roughly half of these would be high-S
+	for i := 0; i < 16; i++ {
+		signature, err := dsa.Sign(payload, privateJwk)
+		assert.NoError(t, err)
+
+		legit, err := dsa.Verify(payload, signature, publicJwk)
+		assert.NoError(t, err)
+		assert.True(t, legit)
+
+		_, err = dsa.Verify(payload, highS(signature, elliptic.P256().Params().N), publicJwk)
+		assert.Error(t, err)
+
+		legit, err = dsa.Verify(payload, highS(signature, elliptic.P256().Params().N), publicJwk, dsa.VerificationPolicy(dsa.PolicyLenient))
+		assert.NoError(t, err)
+		assert.True(t, legit)
+	}
+}
+
+func TestBatchVerify(t *testing.T) {
+	algorithmIDs := []string{dsa.AlgorithmIDED25519, dsa.AlgorithmIDSECP256K1, dsa.AlgorithmIDSECP256R1}
+
+	var items []dsa.BatchItem
+	for i := 0; i < 150; i++ {
+		algorithmID := algorithmIDs[0]
+		if i%5 == 0 {
+			algorithmID = algorithmIDs[i%3]
+		}
+
+		privateJwk, err := dsa.GeneratePrivateKey(algorithmID)
+		assert.NoError(t, err)
+
+		payload := []byte(fmt.Sprintf("payload %d", i))
+		signature, err := dsa.Sign(payload, privateJwk)
+		assert.NoError(t, err)
+
+		items = append(items, dsa.BatchItem{Payload: payload, Signature: signature, PublicKey: dsa.GetPublicKey(privateJwk)})
+	}
+
+	for _, result := range dsa.BatchVerify(items, dsa.MaxWorkers(4)) {
+		assert.NoError(t, result.Err)
+		assert.True(t, result.Verified)
+	}
+
+	// invalidate a signature in the first and the last ed25519 batch as well as a non-ed25519 signature
+	invalid := map[int]bool{1: true, 149: true, 10: true}
+	for i := range invalid {
+		items[i].Payload = []byte("tampered")
+	}
+
+	items = append(items, dsa.BatchItem{Payload: []byte("hi"), Signature: []byte("hi"), PublicKey: jwk.JWK{KTY: "RSA"}})
+
+	results := dsa.BatchVerify(items)
+	assert.Equal(t, len(items), len(results))
+
+	for i, result := range results[:len(results)-1] {
+		assert.NoError(t, result.Err)
+		assert.Equal(t, !invalid[i], result.Verified, "item %d", i)
+	}
+
+	assert.Error(t, results[len(results)-1].Err)
+}
+
+func TestBatchVerify_SmallOrderR(t *testing.T) {
+	seed := bytes.Repeat([]byte{0x07}, 32)
+	privateJwk, err := dsa.GeneratePrivateKey(dsa.AlgorithmIDED25519, dsa.EntropySource(bytes.NewReader(seed)))
+	assert.NoError(t, err)
+
+	publicJwk := dsa.GetPublicKey(privateJwk)
+	publicKeyBytes, err := base64.RawURLEncoding.DecodeString(publicJwk.X)
+	assert.NoError(t, err)
+
+	digest := sha512.Sum512(seed)
+	a, err := edwards25519.NewScalar().SetBytesWithClamping(digest[:32])
+	assert.NoError(t, err)
+
+	// R is a point of order 8 and S = k * a. The cofactored batch equation accepts this signature whereas the
+	// cofactorless equation used by Verify rejects it
+	r, err := hex.DecodeString("c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a")
+	assert.NoError(t, err)
+
+	payload := []byte("hello world")
+
+	h := sha512.New()
+	h.Write(r)
+	h.Write(publicKeyBytes)
+	h.Write(payload)
+	k, err := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	assert.NoError(t, err)
+
+	signature := append(r, edwards25519.NewScalar().Multiply(k, a).Bytes()...)
+
+	ok, err := eddsa.ED25519VerifyBatch([][]byte{payload}, [][]byte{signature}, []jwk.JWK{publicJwk})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	verified, err := dsa.Verify(payload, signature, publicJwk)
+	assert.NoError(t, err)
+	assert.False(t, verified)
+
+	results := dsa.BatchVerify([]dsa.BatchItem{{Payload: payload, Signature: signature, PublicKey: publicJwk}})
+	assert.NoError(t, results[0].Err)
+	assert.False(t, results[0].Verified)
+}
+
+func TestBatchVerify_MalformedKey(t *testing.T) {
+	privateJwk, err := dsa.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	payload := []byte("hello world")
+	signature, err := dsa.Sign(payload, privateJwk)
+	assert.NoError(t, err)
+
+	publicJwk := dsa.GetPublicKey(privateJwk)
+	malformedJwk := publicJwk
+	malformedJwk.X = base64.RawURLEncoding.EncodeToString([]byte{1, 2, 3})
+
+	results := dsa.BatchVerify([]dsa.BatchItem{
+		{Payload: payload, Signature: signature, PublicKey: publicJwk},
+		{Payload: payload, Signature: signature, PublicKey: malformedJwk},
+	})
+
+	assert.NoError(t, results[0].Err)
+	assert.True(t, results[0].Verified)
+	assert.EqualError(t, results[1].Err, "public key must be 32 bytes")
+	assert.False(t, results[1].Verified)
+}
+
+func TestBatchVerify_Empty(t *testing.T) {
+	assert.Equal(t, 0, len(dsa.BatchVerify(nil)))
+}
@@ -3,6 +3,7 @@ package ecdsa
 import (
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/tbd54566975/web5-go/jwk"
 )
@@ -13,6 +14,9 @@ const (
 
 var algorithmIDs = map[string]bool{
 	SECP256K1AlgorithmID: true,
+	SECP256R1AlgorithmID: true,
+	SECP384R1AlgorithmID: true,
+	SECP521R1AlgorithmID: true,
 }
 
 // GeneratePrivateKey generates an ECDSA private key for the given algorithm
@@ -20,6 +24,40 @@ func GeneratePrivateKey(algorithmID string) (jwk.JWK, error) {
 	switch algorithmID {
 	case SECP256K1AlgorithmID:
 		return SECP256K1GeneratePrivateKey()
+	case SECP256R1AlgorithmID:
+		return SECP256R1GeneratePrivateKey()
+	case SECP384R1AlgorithmID:
+		return SECP384R1GeneratePrivateKey()
+	case SECP521R1AlgorithmID:
+		return SECP521R1GeneratePrivateKey()
+	default:
+		return jwk.JWK{}, fmt.Errorf("unsupported algorithm: %s", algorithmID)
+	}
+}
+
+// GeneratePrivateKeyFromReader generates an ECDSA private key for the given algorithm using the given
+// entropy source instead of crypto/rand. The same entropy always results in the same key which makes
+// this useful for reproducible test fixtures.
+func GeneratePrivateKeyFromReader(algorithmID string, rand io.Reader) (jwk.JWK, error) {
+	switch algorithmID {
+	case SECP256K1AlgorithmID:
+		return secp256k1GeneratePrivateKey(rand)
+	case SECP256R1AlgorithmID:
+		return secp256r1.generatePrivateKey(rand)
+	case SECP384R1AlgorithmID:
+		return secp384r1.generatePrivateKey(rand)
+	case SECP521R1AlgorithmID:
+		return secp521r1.generatePrivateKey(rand)
+	default:
+		return jwk.JWK{}, fmt.Errorf("unsupported algorithm: %s", algorithmID)
+	}
+}
+
+// BytesToPrivateKey deserializes the given private key scalar into a jwk.JWK for the given cryptographic algorithm
+func BytesToPrivateKey(algorithmID string, input []byte) (jwk.JWK, error) {
+	switch algorithmID {
+	case SECP256K1AlgorithmID:
+		return SECP256K1BytesToPrivateKey(input)
 	default:
 		return jwk.JWK{}, fmt.Errorf("unsupported algorithm: %s", algorithmID)
 	}
@@ -48,11 +86,49 @@ func Sign(payload []byte, privateKey jwk.JWK) ([]byte, error) {
 	switch privateKey.CRV {
 	case SECP256K1JWACurve:
 		return SECP256K1Sign(payload, privateKey)
+	case SECP256R1JWACurve:
+		return SECP256R1Sign(payload, privateKey)
+	case SECP384R1JWACurve:
+		return SECP384R1Sign(payload, privateKey)
+	case SECP521R1JWACurve:
+		return SECP521R1Sign(payload, privateKey)
 	default:
 		return nil, fmt.Errorf("unsupported curve: %s", privateKey.CRV)
 	}
 }
 
+// SignWithPrivateKeyBytes generates a cryptographic signature like [Sign], using the raw private key scalar
+// of the given curve instead of a private JWK. The private key bytes are neither modified nor retained
+func SignWithPrivateKeyBytes(payload []byte, curve string, privateKeyBytes []byte) ([]byte, error) {
+	return signWithPrivateKeyBytes(payload, curve, privateKeyBytes, false)
+}
+
+// SignStrictWithPrivateKeyBytes generates a cryptographic signature like [SignStrict], using the raw private
+// key scalar of the given curve instead of a private JWK. The private key bytes are neither modified nor retained
+func SignStrictWithPrivateKeyBytes(payload []byte, curve string, privateKeyBytes []byte) ([]byte, error) {
+	return signWithPrivateKeyBytes(payload, curve, privateKeyBytes, true)
+}
+
+func signWithPrivateKeyBytes(payload []byte, curve string, privateKeyBytes []byte, lowS bool) ([]byte, error) {
+	if len(privateKeyBytes) == 0 {
+		return nil, errors.New("d must be set")
+	}
+
+	switch curve {
+	case SECP256K1JWACurve:
+		// signatures produced by secp256k1Sign are always low-S
+		return secp256k1Sign(payload, privateKeyBytes)
+	case SECP256R1JWACurve:
+		return secp256r1.signWithPrivateKeyBytes(payload, privateKeyBytes, lowS)
+	case SECP384R1JWACurve:
+		return secp384r1.signWithPrivateKeyBytes(payload, privateKeyBytes, lowS)
+	case SECP521R1JWACurve:
+		return secp521r1.signWithPrivateKeyBytes(payload, privateKeyBytes, lowS)
+	default:
+		return nil, fmt.Errorf("unsupported curve: %s", curve)
+	}
+}
+
 // Verify verifies the given signature over a given payload by the given public key
 //
 // # Note
@@ -62,11 +138,78 @@ func Verify(payload []byte, signature []byte, publicKey jwk.JWK) (bool, error) {
 	switch publicKey.CRV {
 	case SECP256K1JWACurve:
 		return SECP256K1Verify(payload, signature, publicKey)
+	case SECP256R1JWACurve:
+		return SECP256R1Verify(payload, signature, publicKey)
+	case SECP384R1JWACurve:
+		return SECP384R1Verify(payload, signature, publicKey)
+	case SECP521R1JWACurve:
+		return SECP521R1Verify(payload, signature, publicKey)
 	default:
 		return false, fmt.Errorf("unsupported curve: %s", publicKey.CRV)
 	}
 }
 
+// SignStrict generates a cryptographic signature for the given payload with the given private key that
+// always passes [VerifyStrict], i.e. s is always in the lower half of the curve order (low-S)
+func SignStrict(payload []byte, privateKey jwk.JWK) ([]byte, error) {
+	if privateKey.D == "" {
+		return nil, errors.New("d must be set")
+	}
+
+	switch privateKey.CRV {
+	case SECP256K1JWACurve:
+		// signatures produced by SECP256K1Sign are always low-S
+		return SECP256K1Sign(payload, privateKey)
+	case SECP256R1JWACurve:
+		return secp256r1.sign(payload, privateKey, true)
+	case SECP384R1JWACurve:
+		return secp384r1.sign(payload, privateKey, true)
+	case SECP521R1JWACurve:
+		return secp521r1.sign(payload, privateKey, true)
+	default:
+		return nil, fmt.Errorf("unsupported curve: %s", privateKey.CRV)
+	}
+}
+
+// VerifyStrict verifies the given signature over a given payload by the given public key, rejecting
+// malleable signatures: r and s must be non-zero and less than the curve order and s must be in the
+// lower half of the curve order (low-S)
+func VerifyStrict(payload []byte, signature []byte, publicKey jwk.JWK) (bool, error) {
+	switch publicKey.CRV {
+	case SECP256K1JWACurve:
+		return SECP256K1VerifyStrict(payload, signature, publicKey)
+	case SECP256R1JWACurve:
+		return secp256r1.verify(payload, signature, publicKey, true)
+	case SECP384R1JWACurve:
+		return secp384r1.verify(payload, signature, publicKey, true)
+	case SECP521R1JWACurve:
+		return secp521r1.verify(payload, signature, publicKey, true)
+	default:
+		return false, fmt.Errorf("unsupported curve: %s", publicKey.CRV)
+	}
+}
+
+// SignRecoverable generates a recoverable signature for the given payload with the given private key.
+// Only secp256k1 keys are supported. See [SECP256K1SignRecoverable]
+func SignRecoverable(payload []byte, privateKey jwk.JWK) ([]byte, error) {
+	if privateKey.D == "" {
+		return nil, errors.New("d must be set")
+	}
+
+	switch privateKey.CRV {
+	case SECP256K1JWACurve:
+		return SECP256K1SignRecoverable(payload, privateKey)
+	default:
+		return nil, fmt.Errorf("public key recovery not supported for curve: %s", privateKey.CRV)
+	}
+}
+
+// RecoverPublicKey recovers the public key that produced the given recoverable signature over the given
+// payload. Only secp256k1 (ES256K-R) signatures are supported. See [SECP256K1RecoverPublicKey]
+func RecoverPublicKey(payload []byte, signature []byte) (jwk.JWK, error) {
+	return SECP256K1RecoverPublicKey(payload, signature)
+}
+
 // GetJWA returns the [JWA] for the given ECDSA key
 //
 // [JWA]: https://datatracker.ietf.org/doc/html/rfc7518
@@ -74,6 +217,12 @@ func GetJWA(jwk jwk.JWK) (string, error) {
 	switch jwk.CRV {
 	case SECP256K1JWACurve:
 		return SECP256K1JWA, nil
+	case SECP256R1JWACurve:
+		return SECP256R1JWA, nil
+	case SECP384R1JWACurve:
+		return SECP384R1JWA, nil
+	case SECP521R1JWACurve:
+		return SECP521R1JWA, nil
 	default:
 		return "", fmt.Errorf("unsupported curve: %s", jwk.CRV)
 	}
@@ -84,6 +233,12 @@ func BytesToPublicKey(algorithmID string, input []byte) (jwk.JWK, error) {
 	switch algorithmID {
 	case SECP256K1AlgorithmID:
 		return SECP256K1BytesToPublicKey(input)
+	case SECP256R1AlgorithmID:
+		return SECP256R1BytesToPublicKey(input)
+	case SECP384R1AlgorithmID:
+		return SECP384R1BytesToPublicKey(input)
+	case SECP521R1AlgorithmID:
+		return SECP521R1BytesToPublicKey(input)
 	default:
 		return jwk.JWK{}, fmt.Errorf("unsupported algorithm: %s", algorithmID)
 	}
@@ -94,6 +249,12 @@ func PublicKeyToBytes(publicKey jwk.JWK) ([]byte, error) {
 	switch publicKey.CRV {
 	case SECP256K1JWACurve:
 		return SECP256K1PublicKeyToBytes(publicKey)
+	case SECP256R1JWACurve:
+		return SECP256R1PublicKeyToBytes(publicKey)
+	case SECP384R1JWACurve:
+		return SECP384R1PublicKeyToBytes(publicKey)
+	case SECP521R1JWACurve:
+		return SECP521R1PublicKeyToBytes(publicKey)
 	default:
 		return nil, fmt.Errorf("unsupported curve: %s", publicKey.CRV)
 	}
@@ -109,6 +270,12 @@ func AlgorithmID(jwk *jwk.JWK) (string, error) {
 	switch jwk.CRV {
 	case SECP256K1JWACurve:
 		return SECP256K1AlgorithmID, nil
+	case SECP256R1JWACurve:
+		return SECP256R1AlgorithmID, nil
+	case SECP384R1JWACurve:
+		return SECP384R1AlgorithmID, nil
+	case SECP521R1JWACurve:
+		return SECP521R1AlgorithmID, nil
 	default:
 		return "", fmt.Errorf("unsupported curve: %s", jwk.CRV)
 	}
@@ -0,0 +1,350 @@
+package ecdsa
+
+import (
+	"bytes"
+	_crypto "crypto"
+	_ecdh "crypto/ecdh"
+	_ecdsa "crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	_ "crypto/sha256" // registers SHA-256 for crypto.Hash
+	_ "crypto/sha512" // registers SHA-384 and SHA-512 for crypto.Hash
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+// nistCurve holds everything needed to implement ES256, ES384 and ES512 over their
+// respective NIST prime curves. Each curve uses the hash function mandated by
+// https://datatracker.ietf.org/doc/html/rfc7518#section-3.4
+type nistCurve struct {
+	jwaCurve string
+	curve    elliptic.Curve
+	ecdh     _ecdh.Curve
+	hash     _crypto.Hash
+	// size is the size in bytes of a scalar or field element
+	size int
+}
+
+var (
+	secp256r1 = nistCurve{jwaCurve: SECP256R1JWACurve, curve: elliptic.P256(), ecdh: _ecdh.P256(), hash: _crypto.SHA256, size: 32}
+	secp384r1 = nistCurve{jwaCurve: SECP384R1JWACurve, curve: elliptic.P384(), ecdh: _ecdh.P384(), hash: _crypto.SHA384, size: 48}
+	secp521r1 = nistCurve{jwaCurve: SECP521R1JWACurve, curve: elliptic.P521(), ecdh: _ecdh.P521(), hash: _crypto.SHA512, size: 66}
+)
+
+// generatePrivateKey generates a private key by rejection sampling scalars read from the given
+// entropy source. The same entropy always results in the same key.
+func (c nistCurve) generatePrivateKey(rand io.Reader) (jwk.JWK, error) {
+	var key *_ecdh.PrivateKey
+	for key == nil {
+		scalar := make([]byte, c.size)
+		if _, err := io.ReadFull(rand, scalar); err != nil {
+			return jwk.JWK{}, fmt.Errorf("failed to generate private key: %w", err)
+		}
+
+		// discard the bits beyond the bit size of the curve order (only relevant for P-521)
+		scalar[0] &= byte(0xff >> (8*c.size - c.curve.Params().N.BitLen()))
+
+		// NewPrivateKey rejects zero and scalars >= the curve order, in which case we try again
+		key, _ = c.ecdh.NewPrivateKey(scalar)
+	}
+
+	// uncompressed public key: 0x04 || x || y
+	pubKeyBytes := key.PublicKey().Bytes()
+
+	privateKey := jwk.JWK{
+		KTY: KeyType,
+		CRV: c.jwaCurve,
+		D:   base64.RawURLEncoding.EncodeToString(key.Bytes()),
+		X:   base64.RawURLEncoding.EncodeToString(pubKeyBytes[1 : 1+c.size]),
+		Y:   base64.RawURLEncoding.EncodeToString(pubKeyBytes[1+c.size:]),
+	}
+
+	return privateKey, nil
+}
+
+// sign signs the given payload using the deterministic nonces of https://datatracker.ietf.org/doc/html/rfc6979
+// so that the same key and payload always result in the same signature. When lowS is set, s is normalized to
+// the lower half of the curve order so that the signature passes [nistCurve.verify] in strict mode
+func (c nistCurve) sign(payload []byte, privateKey jwk.JWK, lowS bool) ([]byte, error) {
+	privateKeyBytes, err := base64.RawURLEncoding.DecodeString(privateKey.D)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode d %w", err)
+	}
+	defer clear(privateKeyBytes)
+
+	return c.signWithPrivateKeyBytes(payload, privateKeyBytes, lowS)
+}
+
+// signWithPrivateKeyBytes is [nistCurve.sign] for the raw private key scalar, which is left untouched
+func (c nistCurve) signWithPrivateKeyBytes(payload []byte, privateKeyBytes []byte, lowS bool) ([]byte, error) {
+	if _, err := c.ecdh.NewPrivateKey(privateKeyBytes); err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	n := c.curve.Params().N
+	digest := c.digest(payload)
+
+	d := new(big.Int).SetBytes(privateKeyBytes)
+	defer clear(d.Bits())
+
+	e := c.bits2int(digest)
+	e.Mod(e, n)
+
+	nonces := c.newNonceGenerator(privateKeyBytes, digest)
+	defer nonces.clear()
+
+	var r, s *big.Int
+	for {
+		k := nonces.next()
+
+		// r is the x coordinate of k*G, reduced modulo n
+		kBytes := k.FillBytes(make([]byte, c.size))
+		kG, err := c.ecdh.NewPrivateKey(kBytes)
+		clear(kBytes)
+		if err != nil {
+			clear(k.Bits())
+			continue
+		}
+
+		x, _ := c.coordinates(kG.PublicKey().Bytes())
+		r = x.Mod(x, n)
+
+		// s = k^-1 * (e + r*d) mod n
+		s = new(big.Int).Mul(r, d)
+		s.Add(s, e)
+		s.Mul(s, new(big.Int).ModInverse(k, n))
+		s.Mod(s, n)
+		clear(k.Bits())
+
+		if r.Sign() != 0 && s.Sign() != 0 {
+			break
+		}
+	}
+
+	if lowS && s.Cmp(c.halfOrder()) > 0 {
+		s.Sub(n, s)
+	}
+
+	signature := make([]byte, 2*c.size)
+	r.FillBytes(signature[:c.size])
+	s.FillBytes(signature[c.size:])
+
+	return signature, nil
+}
+
+// verify verifies the given signature. When strict is set, signatures with r or s outside of [1, n-1] or
+// with s in the upper half of the curve order are rejected
+func (c nistCurve) verify(payload []byte, signature []byte, publicKey jwk.JWK, strict bool) (bool, error) {
+	keyBytes, err := c.publicKeyToBytes(publicKey)
+	if err != nil {
+		return false, err
+	}
+
+	if len(signature) != 2*c.size {
+		return false, fmt.Errorf("signature must be %d bytes", 2*c.size)
+	}
+
+	x, y := c.coordinates(keyBytes)
+	key := &_ecdsa.PublicKey{Curve: c.curve, X: x, Y: y}
+
+	r := new(big.Int).SetBytes(signature[:c.size])
+	s := new(big.Int).SetBytes(signature[c.size:])
+
+	if strict {
+		n := c.curve.Params().N
+		switch {
+		case r.Cmp(n) >= 0 || s.Cmp(n) >= 0:
+			return false, errors.New("signature scalar overflows the curve order")
+		case r.Sign() == 0 || s.Sign() == 0:
+			return false, errors.New("signature scalar must not be zero")
+		case s.Cmp(c.halfOrder()) > 0:
+			return false, errors.New("signature s value must be in the lower half of the curve order")
+		}
+	}
+
+	legit := _ecdsa.Verify(key, c.digest(payload), r, s)
+
+	return legit, nil
+}
+
+// nonceGenerator implements the HMAC_DRBG based nonce generation of
+// https://datatracker.ietf.org/doc/html/rfc6979#section-3.2
+type nonceGenerator struct {
+	c    nistCurve
+	k, v []byte
+}
+
+// newNonceGenerator performs steps a. through g. of https://datatracker.ietf.org/doc/html/rfc6979#section-3.2
+// for the given private key scalar and message digest
+func (c nistCurve) newNonceGenerator(privateKeyBytes []byte, digest []byte) *nonceGenerator {
+	hashSize := c.hash.Size()
+	g := &nonceGenerator{
+		c: c,
+		k: make([]byte, hashSize),
+		v: bytes.Repeat([]byte{0x01}, hashSize),
+	}
+
+	// bits2octets(h1) as per https://datatracker.ietf.org/doc/html/rfc6979#section-2.3.4
+	z := c.bits2int(digest)
+	if n := c.curve.Params().N; z.Cmp(n) >= 0 {
+		z.Sub(z, n)
+	}
+	h1 := z.FillBytes(make([]byte, c.size))
+
+	x := make([]byte, c.size)
+	copy(x[c.size-len(privateKeyBytes):], privateKeyBytes)
+	defer clear(x)
+
+	g.k = g.mac(g.v, []byte{0x00}, x, h1)
+	g.v = g.mac(g.v)
+	g.k = g.mac(g.v, []byte{0x01}, x, h1)
+	g.v = g.mac(g.v)
+
+	return g
+}
+
+// next returns the next candidate nonce in [1, n-1] as per step h. of
+// https://datatracker.ietf.org/doc/html/rfc6979#section-3.2
+func (g *nonceGenerator) next() *big.Int {
+	n := g.c.curve.Params().N
+	for {
+		var t []byte
+		for len(t) < g.c.size {
+			g.v = g.mac(g.v)
+			t = append(t, g.v...)
+		}
+
+		k := g.c.bits2int(t[:g.c.size])
+		clear(t)
+
+		// prepare the state for the following candidate, which is needed if this one is out of range or
+		// results in r or s being zero
+		g.k = g.mac(g.v, []byte{0x00})
+		g.v = g.mac(g.v)
+
+		if k.Sign() > 0 && k.Cmp(n) < 0 {
+			return k
+		}
+	}
+}
+
+func (g *nonceGenerator) mac(data ...[]byte) []byte {
+	h := hmac.New(g.c.hash.New, g.k)
+	for _, d := range data {
+		h.Write(d)
+	}
+
+	return h.Sum(nil)
+}
+
+func (g *nonceGenerator) clear() {
+	clear(g.k)
+	clear(g.v)
+}
+
+// bits2int converts the given bit string into an integer of at most the bit length of the curve order as
+// per https://datatracker.ietf.org/doc/html/rfc6979#section-2.3.2
+func (c nistCurve) bits2int(input []byte) *big.Int {
+	x := new(big.Int).SetBytes(input)
+	if excess := len(input)*8 - c.curve.Params().N.BitLen(); excess > 0 {
+		x.Rsh(x, uint(excess))
+	}
+
+	return x
+}
+
+func (c nistCurve) halfOrder() *big.Int {
+	return new(big.Int).Rsh(c.curve.Params().N, 1)
+}
+
+func (c nistCurve) bytesToPublicKey(input []byte) (jwk.JWK, error) {
+	var x, y []byte
+
+	switch len(input) {
+	case 1 + c.size:
+		bigX, bigY := elliptic.UnmarshalCompressed(c.curve, input)
+		if bigX == nil {
+			return jwk.JWK{}, errors.New("failed to parse public key: invalid compressed point")
+		}
+
+		x = bigX.FillBytes(make([]byte, c.size))
+		y = bigY.FillBytes(make([]byte, c.size))
+	case 1 + 2*c.size:
+		if _, err := c.ecdh.NewPublicKey(input); err != nil {
+			return jwk.JWK{}, fmt.Errorf("failed to parse public key: %w", err)
+		}
+
+		x = input[1 : 1+c.size]
+		y = input[1+c.size:]
+	default:
+		return jwk.JWK{}, fmt.Errorf("failed to parse public key: invalid length %d", len(input))
+	}
+
+	return jwk.JWK{
+		KTY: KeyType,
+		CRV: c.jwaCurve,
+		X:   base64.RawURLEncoding.EncodeToString(x),
+		Y:   base64.RawURLEncoding.EncodeToString(y),
+	}, nil
+}
+
+func (c nistCurve) publicKeyToBytes(publicKey jwk.JWK) ([]byte, error) {
+	if publicKey.X == "" || publicKey.Y == "" {
+		return nil, errors.New("x and y must be set")
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(publicKey.X)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode x: %w", err)
+	}
+
+	y, err := base64.RawURLEncoding.DecodeString(publicKey.Y)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode y: %w", err)
+	}
+
+	if len(x) != c.size || len(y) != c.size {
+		return nil, fmt.Errorf("x and y must be %d bytes", c.size)
+	}
+
+	keyBytes := []byte{0x04}
+	keyBytes = append(keyBytes, x...)
+	keyBytes = append(keyBytes, y...)
+
+	if _, err := c.ecdh.NewPublicKey(keyBytes); err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+
+	return keyBytes, nil
+}
+
+func (c nistCurve) publicKeyToCompressedBytes(publicKey jwk.JWK) ([]byte, error) {
+	keyBytes, err := c.publicKeyToBytes(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	x, y := c.coordinates(keyBytes)
+
+	return elliptic.MarshalCompressed(c.curve, x, y), nil
+}
+
+// coordinates splits the given uncompressed public key into its x and y coordinates
+func (c nistCurve) coordinates(uncompressed []byte) (*big.Int, *big.Int) {
+	x := new(big.Int).SetBytes(uncompressed[1 : 1+c.size])
+	y := new(big.Int).SetBytes(uncompressed[1+c.size:])
+
+	return x, y
+}
+
+func (c nistCurve) digest(payload []byte) []byte {
+	h := c.hash.New()
+	h.Write(payload)
+
+	return h.Sum(nil)
+}
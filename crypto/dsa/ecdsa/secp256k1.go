@@ -1,10 +1,13 @@
 package ecdsa
 
 import (
+	"bytes"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 
 	_secp256k1 "github.com/decred/dcrd/dcrec/secp256k1/v4"
 	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
@@ -12,32 +15,79 @@ import (
 )
 
 const (
-	SECP256K1JWA         string = "ES256K"
-	SECP256K1JWACurve    string = "secp256k1"
-	SECP256K1AlgorithmID string = SECP256K1JWACurve
+	SECP256K1JWA            string = "ES256K"
+	SECP256K1RecoverableJWA string = "ES256K-R"
+	SECP256K1JWACurve       string = "secp256k1"
+	SECP256K1AlgorithmID    string = SECP256K1JWACurve
+)
+
+const (
+	secp256k1SignatureLen            = 64
+	secp256k1RecoverableSignatureLen = secp256k1SignatureLen + 1
+
+	// compactSigMagicOffset is the value added to the recovery id in the header byte of the compact
+	// signatures produced and consumed by decred's SignCompact and RecoverCompact
+	compactSigMagicOffset = 27
+	// ethereumRecoveryIDOffset is the value Ethereum wallets historically add to the recovery id (v = 27 or 28)
+	ethereumRecoveryIDOffset = 27
 )
 
 // SECP256K1GeneratePrivateKey generates a new private key
 func SECP256K1GeneratePrivateKey() (jwk.JWK, error) {
-	keyPair, err := _secp256k1.GeneratePrivateKey()
-	if err != nil {
-		return jwk.JWK{}, fmt.Errorf("failed to generate private key: %w", err)
+	return secp256k1GeneratePrivateKey(rand.Reader)
+}
+
+// secp256k1GeneratePrivateKey generates a private key by rejection sampling scalars read from the
+// given entropy source. The same entropy always results in the same key.
+func secp256k1GeneratePrivateKey(rand io.Reader) (jwk.JWK, error) {
+	input := make([]byte, _secp256k1.PrivKeyBytesLen)
+	for {
+		if _, err := io.ReadFull(rand, input); err != nil {
+			return jwk.JWK{}, fmt.Errorf("failed to generate private key: %w", err)
+		}
+
+		var scalar _secp256k1.ModNScalar
+		if overflow := scalar.SetByteSlice(input); !overflow && !scalar.IsZero() {
+			return secp256k1PrivateKeyToJWK(_secp256k1.NewPrivateKey(&scalar)), nil
+		}
+	}
+}
+
+// SECP256K1BytesToPrivateKey converts a 32 byte secp256k1 private key scalar into a JWK
+func SECP256K1BytesToPrivateKey(input []byte) (jwk.JWK, error) {
+	if len(input) != _secp256k1.PrivKeyBytesLen {
+		return jwk.JWK{}, fmt.Errorf("private key must be %d bytes", _secp256k1.PrivKeyBytesLen)
+	}
+
+	var scalar _secp256k1.ModNScalar
+	if overflow := scalar.SetByteSlice(input); overflow || scalar.IsZero() {
+		return jwk.JWK{}, errors.New("invalid private key")
 	}
 
+	return secp256k1PrivateKeyToJWK(_secp256k1.NewPrivateKey(&scalar)), nil
+}
+
+func secp256k1PrivateKeyToJWK(keyPair *_secp256k1.PrivateKey) jwk.JWK {
 	dBytes := keyPair.Key.Bytes()
-	pubKey := keyPair.PubKey()
-	xBytes := pubKey.X().Bytes()
-	yBytes := pubKey.Y().Bytes()
 
-	privateKey := jwk.JWK{
+	privateKey := secp256k1PublicKeyToJWK(keyPair.PubKey())
+	privateKey.D = base64.RawURLEncoding.EncodeToString(dBytes[:])
+
+	return privateKey
+}
+
+// secp256k1PublicKeyToJWK converts the given public key into a JWK. x and y are always encoded as 32 bytes,
+// including leading zeros, as required by https://datatracker.ietf.org/doc/html/rfc7518#section-6.2.1.2
+func secp256k1PublicKeyToJWK(pubKey *_secp256k1.PublicKey) jwk.JWK {
+	// uncompressed public key: 0x04 || x || y
+	pubKeyBytes := pubKey.SerializeUncompressed()
+
+	return jwk.JWK{
 		KTY: KeyType,
 		CRV: SECP256K1JWACurve,
-		D:   base64.RawURLEncoding.EncodeToString(dBytes[:]),
-		X:   base64.RawURLEncoding.EncodeToString(xBytes),
-		Y:   base64.RawURLEncoding.EncodeToString(yBytes),
+		X:   base64.RawURLEncoding.EncodeToString(pubKeyBytes[1:33]),
+		Y:   base64.RawURLEncoding.EncodeToString(pubKeyBytes[33:]),
 	}
-
-	return privateKey, nil
 }
 
 // SECP256K1Sign signs the given payload with the given private key
@@ -46,8 +96,15 @@ func SECP256K1Sign(payload []byte, privateKey jwk.JWK) ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode d %w", err)
 	}
+	defer clear(privateKeyBytes)
 
+	return secp256k1Sign(payload, privateKeyBytes)
+}
+
+// secp256k1Sign is [SECP256K1Sign] for the raw private key scalar, which is left untouched
+func secp256k1Sign(payload []byte, privateKeyBytes []byte) ([]byte, error) {
 	key := _secp256k1.PrivKeyFromBytes(privateKeyBytes)
+	defer key.Zero()
 
 	hash := sha256.Sum256(payload)
 	signature := ecdsa.SignCompact(key, hash[:], false)[1:]
@@ -55,8 +112,136 @@ func SECP256K1Sign(payload []byte, privateKey jwk.JWK) ([]byte, error) {
 	return signature, nil
 }
 
-// SECP256K1Verify verifies the given signature over the given payload with the given public key
+// SECP256K1SignRecoverable signs the given payload with the given private key and returns a 65 byte
+// [ES256K-R] signature in the form r || s || v where v is the recovery id (0 or 1). The signer's public key
+// can be recovered from the signature with [SECP256K1RecoverPublicKey].
+//
+// [ES256K-R]: https://github.com/decentralized-identity/EcdsaSecp256k1RecoverySignature2020
+func SECP256K1SignRecoverable(payload []byte, privateKey jwk.JWK) ([]byte, error) {
+	privateKeyBytes, err := base64.RawURLEncoding.DecodeString(privateKey.D)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode d %w", err)
+	}
+	defer clear(privateKeyBytes)
+
+	key := _secp256k1.PrivKeyFromBytes(privateKeyBytes)
+	defer key.Zero()
+
+	hash := sha256.Sum256(payload)
+	compact := ecdsa.SignCompact(key, hash[:], false)
+
+	// decred's compact format is header || r || s. move the recovery id to the end
+	signature := make([]byte, 0, secp256k1RecoverableSignatureLen)
+	signature = append(signature, compact[1:]...)
+	signature = append(signature, compact[0]-compactSigMagicOffset)
+
+	return signature, nil
+}
+
+// SECP256K1ToRecoverableSignature converts the given 64 byte r || s signature over the given payload into
+// a 65 byte r || s || v recoverable signature by determining which recovery id yields the given public key.
+// This is useful when the signature was produced by a [github.com/tbd54566975/web5-go/crypto.KeyManager]
+// that only exposes [SECP256K1Sign].
+func SECP256K1ToRecoverableSignature(payload []byte, signature []byte, publicKey jwk.JWK) ([]byte, error) {
+	if len(signature) != secp256k1SignatureLen {
+		return nil, fmt.Errorf("signature must be %d bytes", secp256k1SignatureLen)
+	}
+
+	expected, err := SECP256K1PublicKeyToBytes(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert public key to bytes: %w", err)
+	}
+
+	hash := sha256.Sum256(payload)
+	recoverable := make([]byte, secp256k1RecoverableSignatureLen)
+	copy(recoverable, signature)
+
+	for recoveryID := byte(0); recoveryID < 2; recoveryID++ {
+		recoverable[secp256k1SignatureLen] = recoveryID
+
+		key, err := secp256k1RecoverPublicKey(hash[:], recoverable)
+		if err != nil {
+			continue
+		}
+
+		if bytes.Equal(key.SerializeUncompressed(), expected) {
+			return recoverable, nil
+		}
+	}
+
+	return nil, errors.New("signature was not produced by the given public key")
+}
+
+// SECP256K1RecoverPublicKey recovers the public key that produced the given 65 byte r || s || v signature
+// over the given payload. The payload is hashed with SHA-256 as done by [SECP256K1SignRecoverable].
+//
+// # Note
+//
+// A successfully recovered public key only proves that the signature is valid for that key. Callers
+// must still check that the recovered key is the one they expect.
+func SECP256K1RecoverPublicKey(payload []byte, signature []byte) (jwk.JWK, error) {
+	hash := sha256.Sum256(payload)
+	return SECP256K1RecoverPublicKeyFromHash(hash[:], signature)
+}
+
+// SECP256K1RecoverPublicKeyFromHash recovers the public key that produced the given 65 byte r || s || v
+// signature over the given 32 byte message hash. Use this to recover keys from signatures that were not
+// computed over a SHA-256 hash, e.g. the Keccak-256 hash of an Ethereum personal_sign message.
+// Both recovery id conventions, v = 0 or 1 and Ethereum's v = 27 or 28, are accepted.
+func SECP256K1RecoverPublicKeyFromHash(hash []byte, signature []byte) (jwk.JWK, error) {
+	key, err := secp256k1RecoverPublicKey(hash, signature)
+	if err != nil {
+		return jwk.JWK{}, err
+	}
+
+	return secp256k1PublicKeyToJWK(key), nil
+}
+
+func secp256k1RecoverPublicKey(hash []byte, signature []byte) (*_secp256k1.PublicKey, error) {
+	if len(hash) != sha256.Size {
+		return nil, fmt.Errorf("hash must be %d bytes", sha256.Size)
+	}
+
+	if len(signature) != secp256k1RecoverableSignatureLen {
+		return nil, fmt.Errorf("signature must be %d bytes", secp256k1RecoverableSignatureLen)
+	}
+
+	recoveryID := signature[secp256k1SignatureLen]
+	if recoveryID >= ethereumRecoveryIDOffset {
+		recoveryID -= ethereumRecoveryIDOffset
+	}
+
+	if recoveryID > 1 {
+		return nil, fmt.Errorf("invalid recovery id: %d", signature[secp256k1SignatureLen])
+	}
+
+	compact := make([]byte, 0, secp256k1RecoverableSignatureLen)
+	compact = append(compact, recoveryID+compactSigMagicOffset)
+	compact = append(compact, signature[:secp256k1SignatureLen]...)
+
+	key, _, err := ecdsa.RecoverCompact(compact, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover public key: %w", err)
+	}
+
+	return key, nil
+}
+
+// SECP256K1Verify verifies the given 64 byte r || s signature over the given payload with the given public key.
+// 65 byte ES256K-R signatures are rejected, use [SECP256K1RecoverPublicKey] to verify them instead
 func SECP256K1Verify(payload []byte, signature []byte, publicKey jwk.JWK) (bool, error) {
+	return secp256k1Verify(payload, signature, publicKey, false)
+}
+
+// SECP256K1VerifyStrict verifies the given signature over the given payload with the given public key,
+// additionally rejecting malleable signatures. In contrast to [SECP256K1Verify], the signature must be
+// exactly 64 bytes, r and s must be non-zero and less than the curve order and s must be in the lower
+// half of the curve order (low-S) as required by BIP-62 / BIP-146.
+func SECP256K1VerifyStrict(payload []byte, signature []byte, publicKey jwk.JWK) (bool, error) {
+	return secp256k1Verify(payload, signature, publicKey, true)
+}
+
+func secp256k1Verify(payload []byte, signature []byte, publicKey jwk.JWK, strict bool) (bool, error) {
 	if publicKey.X == "" || publicKey.Y == "" {
 		return false, errors.New("x and y must be set")
 	}
@@ -73,15 +258,26 @@ func SECP256K1Verify(payload []byte, signature []byte, publicKey jwk.JWK) (bool,
 		return false, fmt.Errorf("failed to parse public key: %w", err)
 	}
 
-	if len(signature) != 64 {
+	if len(signature) != secp256k1SignatureLen {
 		return false, errors.New("signature must be 64 bytes")
 	}
 
 	r := new(_secp256k1.ModNScalar)
-	r.SetByteSlice(signature[:32])
+	rOverflow := r.SetByteSlice(signature[:32])
 
 	s := new(_secp256k1.ModNScalar)
-	s.SetByteSlice(signature[32:])
+	sOverflow := s.SetByteSlice(signature[32:])
+
+	if strict {
+		switch {
+		case rOverflow || sOverflow:
+			return false, errors.New("signature scalar overflows the curve order")
+		case r.IsZero() || s.IsZero():
+			return false, errors.New("signature scalar must not be zero")
+		case s.IsOverHalfOrder():
+			return false, errors.New("signature s value must be in the lower half of the curve order")
+		}
+	}
 
 	sig := ecdsa.NewSignature(r, s)
 	legit := sig.Verify(hash[:], key)
@@ -98,17 +294,12 @@ func SECP256K1BytesToPublicKey(input []byte) (jwk.JWK, error) {
 		return jwk.JWK{}, fmt.Errorf("failed to parse public key: %w", err)
 	}
 
-	return jwk.JWK{
-		KTY: KeyType,
-		CRV: SECP256K1JWACurve,
-		X:   base64.RawURLEncoding.EncodeToString(pubKey.X().Bytes()),
-		Y:   base64.RawURLEncoding.EncodeToString(pubKey.Y().Bytes()),
-	}, nil
+	return secp256k1PublicKeyToJWK(pubKey), nil
 }
 
 // SECP256K1PublicKeyToBytes converts a secp256k1 public key JWK to bytes.
-// Note: this function returns the uncompressed public key. compressed is not
-// yet supported
+// Note: this function returns the uncompressed public key. Use
+// [SECP256K1PublicKeyToCompressedBytes] for the compressed form
 func SECP256K1PublicKeyToBytes(publicKey jwk.JWK) ([]byte, error) {
 	uncheckedBytes, err := secp256k1PublicKeyToUncheckedBytes(publicKey)
 	if err != nil {
@@ -123,6 +314,22 @@ func SECP256K1PublicKeyToBytes(publicKey jwk.JWK) ([]byte, error) {
 	return key.SerializeUncompressed(), nil
 }
 
+// SECP256K1PublicKeyToCompressedBytes converts a secp256k1 public key JWK to its 33 byte
+// compressed form described in https://www.secg.org/sec1-v2.pdf section 2.3.3
+func SECP256K1PublicKeyToCompressedBytes(publicKey jwk.JWK) ([]byte, error) {
+	uncheckedBytes, err := secp256k1PublicKeyToUncheckedBytes(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := _secp256k1.ParsePubKey(uncheckedBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+
+	return key.SerializeCompressed(), nil
+}
+
 func secp256k1PublicKeyToUncheckedBytes(publicKey jwk.JWK) ([]byte, error) {
 	if publicKey.X == "" || publicKey.Y == "" {
 		return nil, errors.New("x and y must be set")
@@ -142,9 +349,19 @@ func secp256k1PublicKeyToUncheckedBytes(publicKey jwk.JWK) ([]byte, error) {
 	// This byte is a prefix that distinguishes uncompressed keys, which include both X and Y coordinates,
 	// from compressed keys which only include one coordinate and an indication of the other's parity.
 	// The secp256k1 standard requires this prefix for uncompressed keys to ensure proper interpretation.
+	//
+	// x and y are left padded so that keys which were serialized without their leading zeros still parse.
 	keyBytes := []byte{0x04}
-	keyBytes = append(keyBytes, x...)
-	keyBytes = append(keyBytes, y...)
+	keyBytes = append(keyBytes, leftPad(x, 32)...)
+	keyBytes = append(keyBytes, leftPad(y, 32)...)
 
 	return keyBytes, nil
 }
+
+func leftPad(input []byte, size int) []byte {
+	if len(input) >= size {
+		return input
+	}
+
+	return append(make([]byte, size-len(input)), input...)
+}
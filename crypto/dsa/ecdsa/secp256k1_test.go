@@ -96,3 +96,106 @@ func TestSECP256K1PublicKeyToBytes_Bad(t *testing.T) {
 		assert.Equal(t, nil, pubKeyBytes)
 	}
 }
+
+func TestSECP256K1PublicKeyToCompressedBytes(t *testing.T) {
+	privateKey, err := ecdsa.SECP256K1GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	publicKey := ecdsa.GetPublicKey(privateKey)
+
+	compressed, err := ecdsa.SECP256K1PublicKeyToCompressedBytes(publicKey)
+	assert.NoError(t, err)
+	assert.Equal(t, 33, len(compressed))
+
+	decoded, err := ecdsa.SECP256K1BytesToPublicKey(compressed)
+	assert.NoError(t, err)
+	assert.Equal(t, publicKey, decoded)
+}
+
+func TestSECP256K1SignRecoverable(t *testing.T) {
+	privateKey, err := ecdsa.SECP256K1GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	payload := []byte("hello world")
+
+	signature, err := ecdsa.SECP256K1SignRecoverable(payload, privateKey)
+	assert.NoError(t, err)
+	assert.Equal(t, 65, len(signature))
+	assert.True(t, signature[64] <= 1, "expected recovery id to be 0 or 1")
+
+	recovered, err := ecdsa.SECP256K1RecoverPublicKey(payload, signature)
+	assert.NoError(t, err)
+	assert.Equal(t, ecdsa.GetPublicKey(privateKey), recovered)
+
+	verified, err := ecdsa.SECP256K1Verify(payload, signature[:64], recovered)
+	assert.NoError(t, err)
+	assert.True(t, verified)
+
+	// the recovery id must not be ignored, otherwise every signature has 256 valid encodings
+	_, err = ecdsa.SECP256K1Verify(payload, signature, recovered)
+	assert.Error(t, err)
+}
+
+func TestSECP256K1RecoverPublicKey_EthereumRecoveryID(t *testing.T) {
+	privateKey, err := ecdsa.SECP256K1GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	payload := []byte("hello world")
+
+	signature, err := ecdsa.SECP256K1SignRecoverable(payload, privateKey)
+	assert.NoError(t, err)
+
+	signature[64] += 27
+
+	recovered, err := ecdsa.SECP256K1RecoverPublicKey(payload, signature)
+	assert.NoError(t, err)
+	assert.Equal(t, ecdsa.GetPublicKey(privateKey), recovered)
+}
+
+func TestSECP256K1RecoverPublicKey_Bad(t *testing.T) {
+	privateKey, err := ecdsa.SECP256K1GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	payload := []byte("hello world")
+
+	signature, err := ecdsa.SECP256K1SignRecoverable(payload, privateKey)
+	assert.NoError(t, err)
+
+	_, err = ecdsa.SECP256K1RecoverPublicKey(payload, signature[:64])
+	assert.Error(t, err)
+
+	signature[64] = 5
+	_, err = ecdsa.SECP256K1RecoverPublicKey(payload, signature)
+	assert.Error(t, err)
+
+	signature[64] = 0
+	recovered, err := ecdsa.SECP256K1RecoverPublicKey([]byte("tampered"), signature)
+	if err == nil {
+		assert.NotEqual(t, ecdsa.GetPublicKey(privateKey), recovered)
+	}
+}
+
+func TestSECP256K1ToRecoverableSignature(t *testing.T) {
+	privateKey, err := ecdsa.SECP256K1GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	publicKey := ecdsa.GetPublicKey(privateKey)
+	payload := []byte("hello world")
+
+	signature, err := ecdsa.SECP256K1Sign(payload, privateKey)
+	assert.NoError(t, err)
+
+	recoverable, err := ecdsa.SECP256K1ToRecoverableSignature(payload, signature, publicKey)
+	assert.NoError(t, err)
+	assert.Equal(t, signature, recoverable[:64])
+
+	recovered, err := ecdsa.SECP256K1RecoverPublicKey(payload, recoverable)
+	assert.NoError(t, err)
+	assert.Equal(t, publicKey, recovered)
+
+	otherKey, err := ecdsa.SECP256K1GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	_, err = ecdsa.SECP256K1ToRecoverableSignature(payload, signature, ecdsa.GetPublicKey(otherKey))
+	assert.Error(t, err)
+}
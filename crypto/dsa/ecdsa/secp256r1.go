@@ -0,0 +1,50 @@
+package ecdsa
+
+import (
+	"crypto/rand"
+
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+const (
+	SECP256R1JWA         string = "ES256"
+	SECP256R1JWACurve    string = "P-256"
+	SECP256R1AlgorithmID string = SECP256R1JWACurve
+)
+
+// SECP256R1GeneratePrivateKey generates a new P-256 (secp256r1) private key
+func SECP256R1GeneratePrivateKey() (jwk.JWK, error) {
+	return secp256r1.generatePrivateKey(rand.Reader)
+}
+
+// SECP256R1Sign signs the given payload with the given private key. The payload is hashed
+// with SHA-256 and the returned signature is the 64 byte concatenation of r and s as
+// described in https://datatracker.ietf.org/doc/html/rfc7518#section-3.4
+func SECP256R1Sign(payload []byte, privateKey jwk.JWK) ([]byte, error) {
+	return secp256r1.sign(payload, privateKey, false)
+}
+
+// SECP256R1Verify verifies the given signature over the given payload with the given public key
+func SECP256R1Verify(payload []byte, signature []byte, publicKey jwk.JWK) (bool, error) {
+	return secp256r1.verify(payload, signature, publicKey, false)
+}
+
+// SECP256R1BytesToPublicKey converts a P-256 public key to a JWK.
+// Supports both Compressed and Uncompressed public keys described in
+// https://www.secg.org/sec1-v2.pdf section 2.3.3
+func SECP256R1BytesToPublicKey(input []byte) (jwk.JWK, error) {
+	return secp256r1.bytesToPublicKey(input)
+}
+
+// SECP256R1PublicKeyToBytes converts a P-256 public key JWK to bytes.
+// Note: this function returns the uncompressed public key. Use
+// [SECP256R1PublicKeyToCompressedBytes] for the compressed form
+func SECP256R1PublicKeyToBytes(publicKey jwk.JWK) ([]byte, error) {
+	return secp256r1.publicKeyToBytes(publicKey)
+}
+
+// SECP256R1PublicKeyToCompressedBytes converts a P-256 public key JWK to its 33 byte
+// compressed form described in https://www.secg.org/sec1-v2.pdf section 2.3.3
+func SECP256R1PublicKeyToCompressedBytes(publicKey jwk.JWK) ([]byte, error) {
+	return secp256r1.publicKeyToCompressedBytes(publicKey)
+}
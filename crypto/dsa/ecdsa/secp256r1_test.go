@@ -0,0 +1,130 @@
+package ecdsa_test
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/crypto/dsa/ecdsa"
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+// vector taken from https://datatracker.ietf.org/doc/html/rfc7515#appendix-A.3
+var rfc7515PublicKey = jwk.JWK{
+	KTY: "EC",
+	CRV: ecdsa.SECP256R1JWACurve,
+	X:   "f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU",
+	Y:   "x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0",
+}
+
+func TestSECP256R1GeneratePrivateKey(t *testing.T) {
+	key, err := ecdsa.SECP256R1GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	assert.Equal(t, ecdsa.KeyType, key.KTY)
+	assert.Equal(t, ecdsa.SECP256R1JWACurve, key.CRV)
+	assert.True(t, key.D != "", "privateJwk.D is empty")
+	assert.True(t, key.X != "", "privateJwk.X is empty")
+	assert.True(t, key.Y != "", "privateJwk.Y is empty")
+}
+
+func TestSECP256R1SignVerify(t *testing.T) {
+	key, err := ecdsa.SECP256R1GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	payload := []byte("hello world")
+	signature, err := ecdsa.SECP256R1Sign(payload, key)
+	assert.NoError(t, err)
+	assert.Equal(t, 64, len(signature))
+
+	legit, err := ecdsa.SECP256R1Verify(payload, signature, ecdsa.GetPublicKey(key))
+	assert.NoError(t, err)
+	assert.True(t, legit, "failed to verify signature")
+
+	legit, err = ecdsa.SECP256R1Verify([]byte("hello world!"), signature, ecdsa.GetPublicKey(key))
+	assert.NoError(t, err)
+	assert.False(t, legit, "expected signature over different payload to fail")
+}
+
+func TestSECP256R1Verify_RFC7515(t *testing.T) {
+	signingInput := "eyJhbGciOiJFUzI1NiJ9" +
+		".eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ"
+	signature, err := base64.RawURLEncoding.DecodeString(
+		"DtEhU3ljbEg8L38VWAfUAqOyKAM6-Xx-F4GawxaepmXFCgfTjDxw5djxLa8ISlSApmWQxfKTUJqPP3-Kg6NU1Q",
+	)
+	assert.NoError(t, err)
+
+	legit, err := ecdsa.SECP256R1Verify([]byte(signingInput), signature, rfc7515PublicKey)
+	assert.NoError(t, err)
+	assert.True(t, legit, "failed to verify RFC 7515 signature")
+}
+
+func TestSECP256R1Verify_BadSignatureLength(t *testing.T) {
+	_, err := ecdsa.SECP256R1Verify([]byte("hi"), []byte{0x00, 0x01}, rfc7515PublicKey)
+	assert.Error(t, err)
+}
+
+func TestSECP256R1BytesToPublicKey(t *testing.T) {
+	uncompressed, err := ecdsa.SECP256R1PublicKeyToBytes(rfc7515PublicKey)
+	assert.NoError(t, err)
+	assert.Equal(t, 65, len(uncompressed))
+
+	compressed, err := ecdsa.SECP256R1PublicKeyToCompressedBytes(rfc7515PublicKey)
+	assert.NoError(t, err)
+	assert.Equal(t, 33, len(compressed))
+
+	for _, input := range [][]byte{uncompressed, compressed} {
+		key, err := ecdsa.SECP256R1BytesToPublicKey(input)
+		assert.NoError(t, err)
+		assert.Equal(t, rfc7515PublicKey, key)
+	}
+}
+
+func TestSECP256R1BytesToPublicKey_Bad(t *testing.T) {
+	vectors := []string{
+		"00010203",
+		// point not on the curve
+		"04" +
+			"7fcdce2770f6c45d4183cbee6fdb4b7b580733357be9ef13bacf6e3c7bd15445" +
+			"c7f144cd1bbd9b7e872cdfedb9eeb9f4b3695d6ea90b24ad8a4623288588e5ae",
+	}
+
+	for _, vec := range vectors {
+		input, err := hex.DecodeString(vec)
+		assert.NoError(t, err)
+
+		_, err = ecdsa.SECP256R1BytesToPublicKey(input)
+		assert.Error(t, err)
+	}
+}
+
+func TestSECP256R1PublicKeyToBytes_Bad(t *testing.T) {
+	vectors := []jwk.JWK{
+		{KTY: "EC", CRV: ecdsa.SECP256R1JWACurve, X: rfc7515PublicKey.X},
+		{KTY: "EC", CRV: ecdsa.SECP256R1JWACurve, Y: rfc7515PublicKey.Y},
+		{KTY: "EC", CRV: ecdsa.SECP256R1JWACurve, X: "=///", Y: rfc7515PublicKey.Y},
+		{KTY: "EC", CRV: ecdsa.SECP256R1JWACurve, X: rfc7515PublicKey.X, Y: rfc7515PublicKey.X},
+	}
+
+	for _, vec := range vectors {
+		pubKeyBytes, err := ecdsa.SECP256R1PublicKeyToBytes(vec)
+		assert.Error(t, err)
+		assert.Equal(t, nil, pubKeyBytes)
+	}
+}
+
+func TestSECP256R1Sign_RFC6979(t *testing.T) {
+	// vector taken from https://datatracker.ietf.org/doc/html/rfc6979#appendix-A.2.5 (SHA-256, message "sample")
+	d, err := hex.DecodeString("c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721")
+	assert.NoError(t, err)
+
+	key := jwk.JWK{KTY: ecdsa.KeyType, CRV: ecdsa.SECP256R1JWACurve, D: base64.RawURLEncoding.EncodeToString(d)}
+
+	signature, err := ecdsa.SECP256R1Sign([]byte("sample"), key)
+	assert.NoError(t, err)
+
+	expected := "efd48b2aacb6a8fd1140dd9cd45e81d69d2c877b56aaf991c34d0ea84eaf3716" +
+		"f7cb1c942d657c41d436c7a1b6e29f65f3e900dbb9aff4064dc4ab2f843acda8"
+	assert.Equal(t, expected, hex.EncodeToString(signature))
+}
@@ -0,0 +1,50 @@
+package ecdsa
+
+import (
+	"crypto/rand"
+
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+const (
+	SECP384R1JWA         string = "ES384"
+	SECP384R1JWACurve    string = "P-384"
+	SECP384R1AlgorithmID string = SECP384R1JWACurve
+)
+
+// SECP384R1GeneratePrivateKey generates a new P-384 (secp384r1) private key
+func SECP384R1GeneratePrivateKey() (jwk.JWK, error) {
+	return secp384r1.generatePrivateKey(rand.Reader)
+}
+
+// SECP384R1Sign signs the given payload with the given private key. The payload is hashed
+// with SHA-384 and the returned signature is the 96 byte concatenation of r and s as
+// described in https://datatracker.ietf.org/doc/html/rfc7518#section-3.4
+func SECP384R1Sign(payload []byte, privateKey jwk.JWK) ([]byte, error) {
+	return secp384r1.sign(payload, privateKey, false)
+}
+
+// SECP384R1Verify verifies the given signature over the given payload with the given public key
+func SECP384R1Verify(payload []byte, signature []byte, publicKey jwk.JWK) (bool, error) {
+	return secp384r1.verify(payload, signature, publicKey, false)
+}
+
+// SECP384R1BytesToPublicKey converts a P-384 public key to a JWK.
+// Supports both Compressed and Uncompressed public keys described in
+// https://www.secg.org/sec1-v2.pdf section 2.3.3
+func SECP384R1BytesToPublicKey(input []byte) (jwk.JWK, error) {
+	return secp384r1.bytesToPublicKey(input)
+}
+
+// SECP384R1PublicKeyToBytes converts a P-384 public key JWK to bytes.
+// Note: this function returns the uncompressed public key. Use
+// [SECP384R1PublicKeyToCompressedBytes] for the compressed form
+func SECP384R1PublicKeyToBytes(publicKey jwk.JWK) ([]byte, error) {
+	return secp384r1.publicKeyToBytes(publicKey)
+}
+
+// SECP384R1PublicKeyToCompressedBytes converts a P-384 public key JWK to its 49 byte
+// compressed form described in https://www.secg.org/sec1-v2.pdf section 2.3.3
+func SECP384R1PublicKeyToCompressedBytes(publicKey jwk.JWK) ([]byte, error) {
+	return secp384r1.publicKeyToCompressedBytes(publicKey)
+}
@@ -0,0 +1,58 @@
+package ecdsa_test
+
+import (
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/crypto/dsa/ecdsa"
+)
+
+func TestSECP384R1GeneratePrivateKey(t *testing.T) {
+	key, err := ecdsa.SECP384R1GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	assert.Equal(t, ecdsa.KeyType, key.KTY)
+	assert.Equal(t, ecdsa.SECP384R1JWACurve, key.CRV)
+	assert.True(t, key.D != "", "privateJwk.D is empty")
+	assert.True(t, key.X != "", "privateJwk.X is empty")
+	assert.True(t, key.Y != "", "privateJwk.Y is empty")
+}
+
+func TestSECP384R1SignVerify(t *testing.T) {
+	key, err := ecdsa.SECP384R1GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	payload := []byte("hello world")
+	signature, err := ecdsa.SECP384R1Sign(payload, key)
+	assert.NoError(t, err)
+	assert.Equal(t, 96, len(signature))
+
+	legit, err := ecdsa.SECP384R1Verify(payload, signature, ecdsa.GetPublicKey(key))
+	assert.NoError(t, err)
+	assert.True(t, legit, "failed to verify signature")
+
+	// a P-256 sized signature must not be accepted for a P-384 key
+	_, err = ecdsa.SECP384R1Verify(payload, signature[:64], ecdsa.GetPublicKey(key))
+	assert.Error(t, err)
+}
+
+func TestSECP384R1BytesToPublicKey(t *testing.T) {
+	key, err := ecdsa.SECP384R1GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	publicKey := ecdsa.GetPublicKey(key)
+
+	uncompressed, err := ecdsa.SECP384R1PublicKeyToBytes(publicKey)
+	assert.NoError(t, err)
+	assert.Equal(t, 97, len(uncompressed))
+
+	compressed, err := ecdsa.SECP384R1PublicKeyToCompressedBytes(publicKey)
+	assert.NoError(t, err)
+	assert.Equal(t, 49, len(compressed))
+
+	for _, input := range [][]byte{uncompressed, compressed} {
+		parsed, err := ecdsa.SECP384R1BytesToPublicKey(input)
+		assert.NoError(t, err)
+		assert.Equal(t, publicKey, parsed)
+	}
+}
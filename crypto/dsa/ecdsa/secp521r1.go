@@ -0,0 +1,50 @@
+package ecdsa
+
+import (
+	"crypto/rand"
+
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+const (
+	SECP521R1JWA         string = "ES512"
+	SECP521R1JWACurve    string = "P-521"
+	SECP521R1AlgorithmID string = SECP521R1JWACurve
+)
+
+// SECP521R1GeneratePrivateKey generates a new P-521 (secp521r1) private key
+func SECP521R1GeneratePrivateKey() (jwk.JWK, error) {
+	return secp521r1.generatePrivateKey(rand.Reader)
+}
+
+// SECP521R1Sign signs the given payload with the given private key. The payload is hashed
+// with SHA-512 and the returned signature is the 132 byte concatenation of r and s as
+// described in https://datatracker.ietf.org/doc/html/rfc7518#section-3.4
+func SECP521R1Sign(payload []byte, privateKey jwk.JWK) ([]byte, error) {
+	return secp521r1.sign(payload, privateKey, false)
+}
+
+// SECP521R1Verify verifies the given signature over the given payload with the given public key
+func SECP521R1Verify(payload []byte, signature []byte, publicKey jwk.JWK) (bool, error) {
+	return secp521r1.verify(payload, signature, publicKey, false)
+}
+
+// SECP521R1BytesToPublicKey converts a P-521 public key to a JWK.
+// Supports both Compressed and Uncompressed public keys described in
+// https://www.secg.org/sec1-v2.pdf section 2.3.3
+func SECP521R1BytesToPublicKey(input []byte) (jwk.JWK, error) {
+	return secp521r1.bytesToPublicKey(input)
+}
+
+// SECP521R1PublicKeyToBytes converts a P-521 public key JWK to bytes.
+// Note: this function returns the uncompressed public key. Use
+// [SECP521R1PublicKeyToCompressedBytes] for the compressed form
+func SECP521R1PublicKeyToBytes(publicKey jwk.JWK) ([]byte, error) {
+	return secp521r1.publicKeyToBytes(publicKey)
+}
+
+// SECP521R1PublicKeyToCompressedBytes converts a P-521 public key JWK to its 67 byte
+// compressed form described in https://www.secg.org/sec1-v2.pdf section 2.3.3
+func SECP521R1PublicKeyToCompressedBytes(publicKey jwk.JWK) ([]byte, error) {
+	return secp521r1.publicKeyToCompressedBytes(publicKey)
+}
@@ -0,0 +1,63 @@
+package ecdsa_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/crypto/dsa/ecdsa"
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+func TestSECP521R1GeneratePrivateKey(t *testing.T) {
+	key, err := ecdsa.SECP521R1GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	assert.Equal(t, ecdsa.KeyType, key.KTY)
+	assert.Equal(t, ecdsa.SECP521R1JWACurve, key.CRV)
+	assert.True(t, key.D != "", "privateJwk.D is empty")
+	assert.True(t, key.X != "", "privateJwk.X is empty")
+	assert.True(t, key.Y != "", "privateJwk.Y is empty")
+}
+
+func TestSECP521R1SignVerify(t *testing.T) {
+	key, err := ecdsa.SECP521R1GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	payload := []byte("hello world")
+	signature, err := ecdsa.SECP521R1Sign(payload, key)
+	assert.NoError(t, err)
+	assert.Equal(t, 132, len(signature))
+
+	legit, err := ecdsa.SECP521R1Verify(payload, signature, ecdsa.GetPublicKey(key))
+	assert.NoError(t, err)
+	assert.True(t, legit, "failed to verify signature")
+}
+
+func TestSECP521R1Verify_RFC7515(t *testing.T) {
+	// vector taken from https://datatracker.ietf.org/doc/html/rfc7515#appendix-A.4
+	publicKey := jwk.JWK{
+		KTY: "EC",
+		CRV: ecdsa.SECP521R1JWACurve,
+		X:   "AekpBQ8ST8a8VcfVOTNl353vSrDCLLJXmPk06wTjxrrjcBpXp5EOnYG_NjFZ6OvLFV1jSfS9tsz4qUxcWceqwQGk",
+		Y:   "ADSmRA43Z1DSNx_RvcLI87cdL07l6jQyyBXMoxVg_l2Th-x3S1WDhjDly79ajL4Kkd0AZMaZmh9ubmf63e3kyMj2",
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(
+		"AdwMgeerwtHoh-l192l60hp9wAHZFVJbLfD_UxMi70cwnZOYaRI1bKPWROc-mZZqwqT2SI-KGDKB34XO0aw_7Xdt" +
+			"AG8GaSwFKdCAPZgoXD2YBJZCPEX3xKpRwcdOO8KpEHwJjyqOgzDO7iKvU8vcnwNrmxYbSW9ERBXukOXolLzeO_Jn",
+	)
+	assert.NoError(t, err)
+
+	legit, err := ecdsa.SECP521R1Verify([]byte("eyJhbGciOiJFUzUxMiJ9.UGF5bG9hZA"), signature, publicKey)
+	assert.NoError(t, err)
+	assert.True(t, legit, "failed to verify RFC 7515 signature")
+
+	compressed, err := ecdsa.SECP521R1PublicKeyToCompressedBytes(publicKey)
+	assert.NoError(t, err)
+	assert.Equal(t, 67, len(compressed))
+
+	parsed, err := ecdsa.SECP521R1BytesToPublicKey(compressed)
+	assert.NoError(t, err)
+	assert.Equal(t, publicKey, parsed)
+}
@@ -1,12 +1,16 @@
 package eddsa
 
 import (
+	"bytes"
 	_ed25519 "crypto/ed25519"
 	"crypto/rand"
+	"crypto/sha512"
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 
+	"filippo.io/edwards25519"
 	"github.com/tbd54566975/web5-go/jwk"
 )
 
@@ -17,19 +21,35 @@ const (
 
 // ED25519GeneratePrivateKey generates a new ED25519 private key
 func ED25519GeneratePrivateKey() (jwk.JWK, error) {
-	publicKey, privateKey, err := _ed25519.GenerateKey(rand.Reader)
-	if err != nil {
+	return ed25519GeneratePrivateKey(rand.Reader)
+}
+
+// ed25519GeneratePrivateKey generates a private key from a seed read from the given entropy source
+func ed25519GeneratePrivateKey(rand io.Reader) (jwk.JWK, error) {
+	seed := make([]byte, _ed25519.SeedSize)
+	if _, err := io.ReadFull(rand, seed); err != nil {
 		return jwk.JWK{}, err
 	}
 
-	privKeyJwk := jwk.JWK{
+	return ED25519BytesToPrivateKey(seed)
+}
+
+// ED25519BytesToPrivateKey converts a 32 byte Ed25519 seed (the private key as described in
+// https://datatracker.ietf.org/doc/html/rfc8032#section-5.1.5) into a JWK
+func ED25519BytesToPrivateKey(input []byte) (jwk.JWK, error) {
+	if len(input) != _ed25519.SeedSize {
+		return jwk.JWK{}, fmt.Errorf("private key must be %d bytes", _ed25519.SeedSize)
+	}
+
+	privateKey := _ed25519.NewKeyFromSeed(input)
+	publicKey := privateKey.Public().(_ed25519.PublicKey)
+
+	return jwk.JWK{
 		KTY: KeyType,
 		CRV: ED25519JWACurve,
 		D:   base64.RawURLEncoding.EncodeToString(privateKey),
 		X:   base64.RawURLEncoding.EncodeToString(publicKey),
-	}
-
-	return privKeyJwk, nil
+	}, nil
 }
 
 // ED25519Sign signs the given payload with the given private key
@@ -38,6 +58,16 @@ func ED25519Sign(payload []byte, privateKey jwk.JWK) ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode d %w", err)
 	}
+	defer clear(privateKeyBytes)
+
+	return ed25519Sign(payload, privateKeyBytes)
+}
+
+// ed25519Sign is [ED25519Sign] for the raw private key, which is left untouched
+func ed25519Sign(payload []byte, privateKeyBytes []byte) ([]byte, error) {
+	if len(privateKeyBytes) != _ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("private key must be %d bytes", _ed25519.PrivateKeySize)
+	}
 
 	signature := _ed25519.Sign(privateKeyBytes, payload)
 	return signature, nil
@@ -50,10 +80,168 @@ func ED25519Verify(payload []byte, signature []byte, publicKey jwk.JWK) (bool, e
 		return false, err
 	}
 
+	if len(publicKeyBytes) != _ed25519.PublicKeySize {
+		return false, fmt.Errorf("public key must be %d bytes", _ed25519.PublicKeySize)
+	}
+
+	legit := _ed25519.Verify(publicKeyBytes, payload, signature)
+	return legit, nil
+}
+
+// ED25519VerifyStrict verifies the given signature against the given payload using the given public key,
+// additionally rejecting signatures and keys that the (cofactorless) verification of [ED25519Verify]
+// accepts but that allow malleability or key substitution:
+//   - the public key A and the signature's R must be canonically encoded points that are not of small order
+//   - the signature's S must be canonically encoded, i.e. less than the group order
+func ED25519VerifyStrict(payload []byte, signature []byte, publicKey jwk.JWK) (bool, error) {
+	publicKeyBytes, err := base64.RawURLEncoding.DecodeString(publicKey.X)
+	if err != nil {
+		return false, err
+	}
+
+	if len(publicKeyBytes) != _ed25519.PublicKeySize {
+		return false, fmt.Errorf("public key must be %d bytes", _ed25519.PublicKeySize)
+	}
+
+	if len(signature) != _ed25519.SignatureSize {
+		return false, fmt.Errorf("signature must be %d bytes", _ed25519.SignatureSize)
+	}
+
+	if err := checkCanonicalPoint(publicKeyBytes); err != nil {
+		return false, fmt.Errorf("invalid public key: %w", err)
+	}
+
+	if err := checkCanonicalPoint(signature[:32]); err != nil {
+		return false, fmt.Errorf("invalid signature R: %w", err)
+	}
+
+	if _, err := edwards25519.NewScalar().SetCanonicalBytes(signature[32:]); err != nil {
+		return false, errors.New("invalid signature S: non-canonical scalar")
+	}
+
 	legit := _ed25519.Verify(publicKeyBytes, payload, signature)
 	return legit, nil
 }
 
+// ED25519VerifyBatch verifies many signatures at once, which is considerably faster than verifying each of
+// them with [ED25519Verify]. payloads[i] and signatures[i] must belong to publicKeys[i]. It returns true only
+// if every signature is valid. When it returns false, the signatures have to be verified individually to
+// find out which ones are invalid.
+//
+// # Note
+//
+// Batch verification uses the cofactored verification equation whereas [ED25519Verify] is cofactorless.
+// Both agree on all honestly generated signatures, however a maliciously crafted signature involving points
+// that are non-canonically encoded or of small order can pass one and fail the other. Such signatures are
+// rejected here, check them with [ED25519CanVerifyInBatch] and verify them with [ED25519Verify] instead.
+func ED25519VerifyBatch(payloads [][]byte, signatures [][]byte, publicKeys []jwk.JWK) (bool, error) {
+	if len(payloads) != len(signatures) || len(payloads) != len(publicKeys) {
+		return false, errors.New("payloads, signatures and public keys must have the same length")
+	}
+
+	// the batch equation is [8]([-sum(z_i * S_i)]B + sum([z_i]R_i) + sum([z_i * k_i]A_i)) == identity where
+	// the z_i are random scalars that prevent invalid signatures from cancelling each other out
+	scalars := make([]*edwards25519.Scalar, 0, 2*len(payloads)+1)
+	points := make([]*edwards25519.Point, 0, 2*len(payloads)+1)
+
+	bScalar := edwards25519.NewScalar()
+	scalars = append(scalars, bScalar)
+	points = append(points, edwards25519.NewGeneratorPoint())
+
+	for i := range payloads {
+		publicKeyBytes, err := base64.RawURLEncoding.DecodeString(publicKeys[i].X)
+		if err != nil || len(publicKeyBytes) != _ed25519.PublicKeySize || len(signatures[i]) != _ed25519.SignatureSize {
+			return false, nil
+		}
+
+		if checkCanonicalPoint(publicKeyBytes) != nil || checkCanonicalPoint(signatures[i][:32]) != nil {
+			return false, nil
+		}
+
+		a, err := new(edwards25519.Point).SetBytes(publicKeyBytes)
+		if err != nil {
+			return false, nil
+		}
+
+		r, err := new(edwards25519.Point).SetBytes(signatures[i][:32])
+		if err != nil {
+			return false, nil
+		}
+
+		s, err := edwards25519.NewScalar().SetCanonicalBytes(signatures[i][32:])
+		if err != nil {
+			return false, nil
+		}
+
+		h := sha512.New()
+		h.Write(signatures[i][:32])
+		h.Write(publicKeyBytes)
+		h.Write(payloads[i])
+
+		k, err := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+		if err != nil {
+			return false, fmt.Errorf("failed to compute challenge: %w", err)
+		}
+
+		z, err := randomBatchScalar()
+		if err != nil {
+			return false, err
+		}
+
+		bScalar.Subtract(bScalar, edwards25519.NewScalar().Multiply(z, s))
+
+		scalars = append(scalars, z, edwards25519.NewScalar().Multiply(z, k))
+		points = append(points, r, a)
+	}
+
+	check := new(edwards25519.Point).VarTimeMultiScalarMult(scalars, points)
+	check.MultByCofactor(check)
+
+	return check.Equal(edwards25519.NewIdentityPoint()) == 1, nil
+}
+
+// ED25519CanVerifyInBatch reports whether the given signature can be passed to [ED25519VerifyBatch], which
+// requires R and the public key to be canonically encoded points that are not of small order. Signatures
+// for which it returns false must be verified with [ED25519Verify]
+func ED25519CanVerifyInBatch(signature []byte, publicKey jwk.JWK) bool {
+	publicKeyBytes, err := base64.RawURLEncoding.DecodeString(publicKey.X)
+	if err != nil || len(publicKeyBytes) != _ed25519.PublicKeySize || len(signature) != _ed25519.SignatureSize {
+		return false
+	}
+
+	return checkCanonicalPoint(publicKeyBytes) == nil && checkCanonicalPoint(signature[:32]) == nil
+}
+
+// randomBatchScalar returns a random 128 bit scalar
+func randomBatchScalar() (*edwards25519.Scalar, error) {
+	var buf [32]byte
+	if _, err := rand.Read(buf[:16]); err != nil {
+		return nil, fmt.Errorf("failed to generate batch scalar: %w", err)
+	}
+
+	// 128 bit values are always less than the group order, so this never fails
+	return edwards25519.NewScalar().SetCanonicalBytes(buf[:])
+}
+
+// checkCanonicalPoint returns an error if the given encoded point is not on the curve, is not canonically
+// encoded (e.g. its y coordinate is not reduced) or is of small order
+func checkCanonicalPoint(encoded []byte) error {
+	point, err := new(edwards25519.Point).SetBytes(encoded)
+	if err != nil {
+		return errors.New("not a valid point")
+	}
+
+	if !bytes.Equal(point.Bytes(), encoded) {
+		return errors.New("non-canonical point encoding")
+	}
+
+	if new(edwards25519.Point).MultByCofactor(point).Equal(edwards25519.NewIdentityPoint()) == 1 {
+		return errors.New("small order point")
+	}
+
+	return nil
+}
+
 // ED25519BytesToPublicKey deserializes the byte array into a jwk.JWK public key
 func ED25519BytesToPublicKey(input []byte) (jwk.JWK, error) {
 	if len(input) != _ed25519.PublicKeySize {
@@ -3,6 +3,7 @@ package eddsa_test
 import (
 	"encoding/base64"
 	"encoding/hex"
+	"math/big"
 	"testing"
 
 	"github.com/alecthomas/assert/v2"
@@ -65,3 +66,94 @@ func TestED25519PublicKeyToBytes_Bad(t *testing.T) {
 		assert.Equal(t, nil, pubKeyBytes)
 	}
 }
+
+func TestED25519VerifyStrict(t *testing.T) {
+	privateKey, err := eddsa.ED25519GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	publicKey := eddsa.GetPublicKey(privateKey)
+	payload := []byte("hello world")
+
+	signature, err := eddsa.ED25519Sign(payload, privateKey)
+	assert.NoError(t, err)
+
+	legit, err := eddsa.ED25519VerifyStrict(payload, signature, publicKey)
+	assert.NoError(t, err)
+	assert.True(t, legit)
+}
+
+func TestED25519VerifyStrict_NonCanonicalS(t *testing.T) {
+	privateKey, err := eddsa.ED25519GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	publicKey := eddsa.GetPublicKey(privateKey)
+	payload := []byte("hello world")
+
+	signature, err := eddsa.ED25519Sign(payload, privateKey)
+	assert.NoError(t, err)
+
+	// S + L, little endian. L = 2^252 + 27742317777372353535851937790883648493
+	order, _ := new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+	s := new(big.Int).SetBytes(reverse(signature[32:]))
+	s.Add(s, order)
+	copy(signature[32:], reverse(s.FillBytes(make([]byte, 32))))
+
+	_, err = eddsa.ED25519VerifyStrict(payload, signature, publicKey)
+	assert.Error(t, err)
+}
+
+func TestED25519VerifyStrict_NonCanonicalPoint(t *testing.T) {
+	// y = p + 1 is a non-canonical encoding of y = 1 (the identity)
+	nonCanonical, err := hex.DecodeString("eeffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f")
+	assert.NoError(t, err)
+
+	publicKey := jwk.JWK{
+		KTY: eddsa.KeyType,
+		CRV: eddsa.ED25519JWACurve,
+		X:   base64.RawURLEncoding.EncodeToString(nonCanonical),
+	}
+
+	_, err = eddsa.ED25519VerifyStrict([]byte("hello world"), make([]byte, 64), publicKey)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "non-canonical")
+}
+
+func reverse(input []byte) []byte {
+	reversed := make([]byte, len(input))
+	for i := range input {
+		reversed[len(input)-1-i] = input[i]
+	}
+
+	return reversed
+}
+
+func TestED25519VerifyBatch(t *testing.T) {
+	var payloads, signatures [][]byte
+	var publicKeys []jwk.JWK
+
+	for i := 0; i < 16; i++ {
+		privateKey, err := eddsa.ED25519GeneratePrivateKey()
+		assert.NoError(t, err)
+
+		payload := []byte{byte(i)}
+		signature, err := eddsa.ED25519Sign(payload, privateKey)
+		assert.NoError(t, err)
+
+		payloads = append(payloads, payload)
+		signatures = append(signatures, signature)
+		publicKeys = append(publicKeys, eddsa.GetPublicKey(privateKey))
+	}
+
+	ok, err := eddsa.ED25519VerifyBatch(payloads, signatures, publicKeys)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	payloads[7] = []byte("tampered")
+
+	ok, err = eddsa.ED25519VerifyBatch(payloads, signatures, publicKeys)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = eddsa.ED25519VerifyBatch(payloads[:1], signatures, publicKeys)
+	assert.Error(t, err)
+}
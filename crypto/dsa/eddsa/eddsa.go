@@ -5,6 +5,7 @@ package eddsa
 import (
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/tbd54566975/web5-go/jwk"
 )
@@ -28,6 +29,28 @@ func GeneratePrivateKey(algorithmID string) (jwk.JWK, error) {
 	}
 }
 
+// GeneratePrivateKeyFromReader generates an EdDSA private key for the given algorithm using the given
+// entropy source instead of crypto/rand. The same entropy always results in the same key which makes
+// this useful for reproducible test fixtures.
+func GeneratePrivateKeyFromReader(algorithmID string, rand io.Reader) (jwk.JWK, error) {
+	switch algorithmID {
+	case ED25519AlgorithmID:
+		return ed25519GeneratePrivateKey(rand)
+	default:
+		return jwk.JWK{}, fmt.Errorf("unsupported algorithm: %s", algorithmID)
+	}
+}
+
+// BytesToPrivateKey deserializes the given private key bytes into a jwk.JWK for the given cryptographic algorithm
+func BytesToPrivateKey(algorithmID string, input []byte) (jwk.JWK, error) {
+	switch algorithmID {
+	case ED25519AlgorithmID:
+		return ED25519BytesToPrivateKey(input)
+	default:
+		return jwk.JWK{}, fmt.Errorf("unsupported algorithm: %s", algorithmID)
+	}
+}
+
 // GetPublicKey builds an EdDSA public key from the given EdDSA private key
 func GetPublicKey(privateKey jwk.JWK) jwk.JWK {
 	return jwk.JWK{
@@ -55,6 +78,22 @@ func Sign(payload []byte, privateKey jwk.JWK) ([]byte, error) {
 	}
 }
 
+// SignWithPrivateKeyBytes generates a cryptographic signature like [Sign], using the raw private key of the
+// given curve, i.e. the decoded d of a private JWK, instead of a private JWK. The private key bytes are
+// neither modified nor retained
+func SignWithPrivateKeyBytes(payload []byte, curve string, privateKeyBytes []byte) ([]byte, error) {
+	if len(privateKeyBytes) == 0 {
+		return nil, errors.New("d must be set")
+	}
+
+	switch curve {
+	case ED25519JWACurve:
+		return ed25519Sign(payload, privateKeyBytes)
+	default:
+		return nil, fmt.Errorf("unsupported curve: %s", curve)
+	}
+}
+
 // Verify verifies the given signature over a given payload by the given public key
 //
 // # Note
@@ -69,6 +108,23 @@ func Verify(payload []byte, signature []byte, publicKey jwk.JWK) (bool, error) {
 	}
 }
 
+// SignStrict generates a cryptographic signature for the given payload with the given private key that
+// always passes [VerifyStrict]. EdDSA signatures are deterministic and canonical so this is equivalent to [Sign]
+func SignStrict(payload []byte, privateKey jwk.JWK) ([]byte, error) {
+	return Sign(payload, privateKey)
+}
+
+// VerifyStrict verifies the given signature over a given payload by the given public key, rejecting
+// non-canonical encodings and small order points. See [ED25519VerifyStrict]
+func VerifyStrict(payload []byte, signature []byte, publicKey jwk.JWK) (bool, error) {
+	switch publicKey.CRV {
+	case ED25519JWACurve:
+		return ED25519VerifyStrict(payload, signature, publicKey)
+	default:
+		return false, fmt.Errorf("unsupported curve: %s", publicKey.CRV)
+	}
+}
+
 // GetJWA returns the [JWA] for the given EdDSA key
 //
 // # Note
@@ -0,0 +1,77 @@
+package dsa
+
+import (
+	"sync/atomic"
+
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+// Policy determines how strictly [Sign] produces and [Verify] checks signatures
+type Policy int32
+
+const (
+	// PolicyLenient accepts any signature the underlying algorithm considers valid. This is the default.
+	PolicyLenient Policy = iota
+	// PolicyStrict only produces and accepts non-malleable signatures. For ECDSA this means r and s must
+	// be non-zero and less than the curve order and s must be in the lower half of the curve order (low-S).
+	// For EdDSA this means the public key and R must be canonically encoded points that are not of small
+	// order and S must be canonically encoded. Use this for signatures that are stored or referenced by
+	// their bytes, e.g. in ledgers, where a second valid encoding of the same signature is a problem.
+	PolicyStrict
+)
+
+var defaultPolicy atomic.Int32
+
+// SetDefaultPolicy sets the [Policy] used by [Sign] and [Verify] when no policy is passed explicitly.
+// This also applies to everything built on top of them, e.g. [github.com/tbd54566975/web5-go/crypto.LocalKeyManager]
+// and [github.com/tbd54566975/web5-go/jws]
+func SetDefaultPolicy(policy Policy) {
+	defaultPolicy.Store(int32(policy))
+}
+
+// DefaultPolicy returns the [Policy] used by [Sign] and [Verify] when no policy is passed explicitly
+func DefaultPolicy() Policy {
+	return Policy(defaultPolicy.Load())
+}
+
+// StrictAlgorithm is implemented by an [Algorithm] that supports [PolicyStrict]. Signing or verifying
+// with [PolicyStrict] fails for algorithms that don't implement it.
+type StrictAlgorithm interface {
+	// SignStrict signs the given payload with the given private key, producing a signature that
+	// passes VerifyStrict
+	SignStrict(payload []byte, privateKey jwk.JWK) ([]byte, error)
+	// VerifyStrict verifies the given signature over the given payload with the given public key,
+	// rejecting malleable signatures and non-canonical encodings
+	VerifyStrict(payload []byte, signature []byte, publicKey jwk.JWK) (bool, error)
+}
+
+// options that [Sign] can take
+type signOpts struct {
+	policy Policy
+}
+
+// SignOpt is a type that represents an option that can be passed to [Sign]
+type SignOpt func(opts *signOpts)
+
+// SigningPolicy is an option that can be passed to [Sign] to override the [DefaultPolicy]
+func SigningPolicy(policy Policy) SignOpt {
+	return func(opts *signOpts) {
+		opts.policy = policy
+	}
+}
+
+// options that [Verify] and [BatchVerify] can take
+type verifyOpts struct {
+	policy     Policy
+	maxWorkers int
+}
+
+// VerifyOpt is a type that represents an option that can be passed to [Verify] or [BatchVerify]
+type VerifyOpt func(opts *verifyOpts)
+
+// VerificationPolicy is an option that can be passed to [Verify] or [BatchVerify] to override the [DefaultPolicy]
+func VerificationPolicy(policy Policy) VerifyOpt {
+	return func(opts *verifyOpts) {
+		opts.policy = policy
+	}
+}
@@ -0,0 +1,148 @@
+package dsa
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+// Algorithm is a digital signature algorithm that can be plugged into this package using [Register].
+// Once registered, the algorithm is available through all of the top level functions in this package
+// (e.g. [GeneratePrivateKey], [Sign], [Verify]) as well as anything built on top of them such as
+// [github.com/tbd54566975/web5-go/crypto.LocalKeyManager] and [github.com/tbd54566975/web5-go/jws]
+type Algorithm interface {
+	// ID returns the algorithm ID that can be passed to [GeneratePrivateKey] and [BytesToPublicKey]
+	ID() string
+	// KeyType returns the JWK kty value of keys used with this algorithm
+	KeyType() string
+	// Curve returns the JWK crv value of keys used with this algorithm
+	Curve() string
+	// JWA returns the JWS alg value for signatures produced by this algorithm
+	JWA() string
+	// GeneratePrivateKey generates a new private key
+	GeneratePrivateKey() (jwk.JWK, error)
+	// GetPublicKey returns the public key corresponding to the given private key
+	GetPublicKey(privateKey jwk.JWK) jwk.JWK
+	// Sign signs the given payload with the given private key
+	Sign(payload []byte, privateKey jwk.JWK) ([]byte, error)
+	// Verify verifies the given signature over the given payload with the given public key
+	Verify(payload []byte, signature []byte, publicKey jwk.JWK) (bool, error)
+	// BytesToPublicKey deserializes the given byte array into a public key
+	BytesToPublicKey(input []byte) (jwk.JWK, error)
+	// PublicKeyToBytes serializes the given public key into a byte array
+	PublicKeyToBytes(publicKey jwk.JWK) ([]byte, error)
+}
+
+// EntropyAlgorithm is implemented by an [Algorithm] that can generate keys from a caller provided source of
+// randomness. Generating a key with [EntropySource] fails for algorithms that don't implement it.
+type EntropyAlgorithm interface {
+	// GeneratePrivateKeyFromReader generates a new private key using the given source of randomness
+	GeneratePrivateKeyFromReader(rand io.Reader) (jwk.JWK, error)
+}
+
+// PrivateKeyBytesAlgorithm is implemented by an [Algorithm] that can sign with the raw private key, i.e. the
+// decoded d of a private JWK. [SignWithPrivateKeyBytes] falls back to building a private JWK for algorithms
+// that don't implement it.
+type PrivateKeyBytesAlgorithm interface {
+	// SignWithPrivateKeyBytes signs the given payload with the given raw private key, which must be left untouched
+	SignWithPrivateKeyBytes(payload []byte, privateKeyBytes []byte) ([]byte, error)
+	// SignStrictWithPrivateKeyBytes is like SignWithPrivateKeyBytes, producing a signature that passes
+	// VerifyStrict. Only called for algorithms that implement [StrictAlgorithm] too
+	SignStrictWithPrivateKeyBytes(payload []byte, privateKeyBytes []byte) ([]byte, error)
+}
+
+var registry = struct {
+	sync.RWMutex
+	byID  map[string]Algorithm
+	byKey map[keyTypeAndCurve]Algorithm
+	kty   map[string]bool
+}{
+	byID:  make(map[string]Algorithm),
+	byKey: make(map[keyTypeAndCurve]Algorithm),
+	kty:   make(map[string]bool),
+}
+
+type keyTypeAndCurve struct {
+	kty string
+	crv string
+}
+
+// Register makes the given algorithm available to this package. Register is intended to be called
+// from the init function of the package implementing the algorithm.
+//
+// # Note
+//
+// Register panics if alg is nil or if an algorithm with the same ID, or the same key type and curve,
+// has already been registered
+func Register(alg Algorithm) {
+	if alg == nil {
+		panic("dsa: Register algorithm is nil")
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, ok := registry.byID[alg.ID()]; ok {
+		panic("dsa: Register called twice for algorithm " + alg.ID())
+	}
+
+	key := keyTypeAndCurve{kty: alg.KeyType(), crv: alg.Curve()}
+	if existing, ok := registry.byKey[key]; ok {
+		panic(fmt.Sprintf("dsa: algorithm %s uses the same key type and curve as %s", alg.ID(), existing.ID()))
+	}
+
+	registry.byID[alg.ID()] = alg
+	registry.byKey[key] = alg
+	registry.kty[key.kty] = true
+}
+
+// Lookup returns the registered algorithm with the given algorithm ID
+func Lookup(algorithmID string) (Algorithm, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	alg, ok := registry.byID[algorithmID]
+
+	return alg, ok
+}
+
+// Algorithms returns the IDs of all registered algorithms in sorted order
+func Algorithms() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	ids := make([]string, 0, len(registry.byID))
+	for id := range registry.byID {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+// SupportsAlgorithmID informs as to whether or not an algorithm with the given ID has been registered
+func SupportsAlgorithmID(id string) bool {
+	_, ok := Lookup(id)
+	return ok
+}
+
+// lookupByKey returns the registered algorithm for the kty and crv of the given key
+func lookupByKey(key jwk.JWK) (Algorithm, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	alg, ok := registry.byKey[keyTypeAndCurve{kty: key.KTY, crv: key.CRV}]
+	if ok {
+		return alg, nil
+	}
+
+	if !registry.kty[key.KTY] {
+		return nil, fmt.Errorf("unsupported key type: %s", key.KTY)
+	}
+
+	return nil, fmt.Errorf("unsupported curve: %s", key.CRV)
+}
@@ -0,0 +1,156 @@
+package dsa_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/crypto/dsa/eddsa"
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+const testAlgorithmID = "test-ed25519"
+
+// testAlgorithm is an Ed25519 implementation registered under a different curve name. It stands in
+// for algorithms implemented outside of this module (e.g. HSM backed or experimental schemes)
+type testAlgorithm struct{}
+
+func (testAlgorithm) ID() string      { return testAlgorithmID }
+func (testAlgorithm) KeyType() string { return eddsa.KeyType }
+func (testAlgorithm) Curve() string   { return "TestEd25519" }
+func (testAlgorithm) JWA() string     { return "TestEdDSA" }
+
+func (a testAlgorithm) GeneratePrivateKey() (jwk.JWK, error) {
+	key, err := eddsa.GeneratePrivateKey(eddsa.ED25519AlgorithmID)
+	key.CRV = a.Curve()
+
+	return key, err
+}
+
+func (testAlgorithm) GetPublicKey(privateKey jwk.JWK) jwk.JWK {
+	return eddsa.GetPublicKey(privateKey)
+}
+
+func (testAlgorithm) Sign(payload []byte, privateKey jwk.JWK) ([]byte, error) {
+	privateKey.CRV = eddsa.ED25519JWACurve
+	return eddsa.ED25519Sign(payload, privateKey)
+}
+
+func (testAlgorithm) Verify(payload []byte, signature []byte, publicKey jwk.JWK) (bool, error) {
+	publicKey.CRV = eddsa.ED25519JWACurve
+	return eddsa.ED25519Verify(payload, signature, publicKey)
+}
+
+func (a testAlgorithm) BytesToPublicKey(input []byte) (jwk.JWK, error) {
+	key, err := eddsa.ED25519BytesToPublicKey(input)
+	key.CRV = a.Curve()
+
+	return key, err
+}
+
+func (testAlgorithm) PublicKeyToBytes(publicKey jwk.JWK) ([]byte, error) {
+	publicKey.CRV = eddsa.ED25519JWACurve
+	return eddsa.ED25519PublicKeyToBytes(publicKey)
+}
+
+func init() {
+	dsa.Register(testAlgorithm{})
+}
+
+func TestRegister(t *testing.T) {
+	assert.True(t, dsa.SupportsAlgorithmID(testAlgorithmID))
+
+	privateJwk, err := dsa.GeneratePrivateKey(testAlgorithmID)
+	assert.NoError(t, err)
+	assert.Equal(t, "TestEd25519", privateJwk.CRV)
+
+	// the test algorithm doesn't implement dsa.EntropyAlgorithm
+	_, err = dsa.GeneratePrivateKey(testAlgorithmID, dsa.EntropySource(rand.Reader))
+	assert.NoError(t, err)
+
+	_, err = dsa.GeneratePrivateKey(testAlgorithmID, dsa.EntropySource(bytes.NewReader(make([]byte, 32))))
+	assert.EqualError(t, err, "entropy source not supported for algorithm: "+testAlgorithmID)
+
+	algorithmID, err := dsa.AlgorithmID(&privateJwk)
+	assert.NoError(t, err)
+	assert.Equal(t, testAlgorithmID, algorithmID)
+
+	jwa, err := dsa.GetJWA(privateJwk)
+	assert.NoError(t, err)
+	assert.Equal(t, "TestEdDSA", jwa)
+
+	payload := []byte("hello world")
+	signature, err := dsa.Sign(payload, privateJwk)
+	assert.NoError(t, err)
+
+	publicJwk := dsa.GetPublicKey(privateJwk)
+	assert.Equal(t, "", publicJwk.D)
+
+	legit, err := dsa.Verify(payload, signature, publicJwk)
+	assert.NoError(t, err)
+	assert.True(t, legit)
+
+	// the test algorithm doesn't implement dsa.PrivateKeyBytesAlgorithm
+	privateKeyBytes, err := base64.RawURLEncoding.DecodeString(privateJwk.D)
+	assert.NoError(t, err)
+
+	signature, err = dsa.SignWithPrivateKeyBytes(payload, publicJwk, privateKeyBytes)
+	assert.NoError(t, err)
+
+	legit, err = dsa.Verify(payload, signature, publicJwk)
+	assert.NoError(t, err)
+	assert.True(t, legit)
+
+	publicKeyBytes, err := dsa.PublicKeyToBytes(publicJwk)
+	assert.NoError(t, err)
+
+	parsed, err := dsa.BytesToPublicKey(testAlgorithmID, publicKeyBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, publicJwk, parsed)
+}
+
+func TestRegister_Duplicate(t *testing.T) {
+	assert.Panics(t, func() { dsa.Register(testAlgorithm{}) })
+	assert.Panics(t, func() { dsa.Register(nil) })
+}
+
+func TestLookup(t *testing.T) {
+	alg, ok := dsa.Lookup(dsa.AlgorithmIDED25519)
+	assert.True(t, ok)
+	assert.Equal(t, eddsa.JWA, alg.JWA())
+
+	_, ok = dsa.Lookup("brainpoolP256r1")
+	assert.False(t, ok)
+}
+
+func TestAlgorithms(t *testing.T) {
+	algorithms := map[string]bool{}
+	for _, id := range dsa.Algorithms() {
+		algorithms[id] = true
+	}
+
+	for _, id := range []string{
+		dsa.AlgorithmIDED25519,
+		dsa.AlgorithmIDSECP256K1,
+		dsa.AlgorithmIDSECP256R1,
+		dsa.AlgorithmIDSECP384R1,
+		dsa.AlgorithmIDSECP521R1,
+		testAlgorithmID,
+	} {
+		assert.True(t, algorithms[id], "expected %s to be registered", id)
+	}
+}
+
+func TestUnsupportedKey(t *testing.T) {
+	_, err := dsa.GetJWA(jwk.JWK{KTY: "RSA"})
+	assert.EqualError(t, err, "unsupported key type: RSA")
+
+	_, err = dsa.GetJWA(jwk.JWK{KTY: "OKP", CRV: "Ed448"})
+	assert.EqualError(t, err, "unsupported curve: Ed448")
+
+	_, err = dsa.Sign([]byte("hi"), jwk.JWK{KTY: "OKP", CRV: "Ed25519", X: "abc"})
+	assert.EqualError(t, err, "d must be set")
+}
@@ -0,0 +1,180 @@
+// Package ecdh implements Elliptic Curve Diffie-Hellman key agreement over X25519
+// (https://datatracker.ietf.org/doc/html/rfc7748), secp256k1 and the NIST prime curves.
+// Keys are represented as JWKs as per https://datatracker.ietf.org/doc/html/rfc8037 and
+// https://datatracker.ietf.org/doc/html/rfc7518#section-6.2
+package ecdh
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/tbd54566975/web5-go/crypto/dsa/ecdsa"
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+const (
+	KeyTypeEC  string = ecdsa.KeyType
+	KeyTypeOKP string = "OKP"
+)
+
+var algorithmIDs = map[string]bool{
+	X25519AlgorithmID:          true,
+	ecdsa.SECP256K1AlgorithmID: true,
+	ecdsa.SECP256R1AlgorithmID: true,
+	ecdsa.SECP384R1AlgorithmID: true,
+	ecdsa.SECP521R1AlgorithmID: true,
+}
+
+// options that [GeneratePrivateKey] can take
+type generateOpts struct {
+	rand io.Reader
+}
+
+// GenerateOpt is a type that represents an option that can be passed to [GeneratePrivateKey]
+type GenerateOpt func(opts *generateOpts)
+
+// EntropySource is an option that can be passed to [GeneratePrivateKey]. It sets the source of randomness
+// used to generate the key. The same entropy always results in the same key which makes this useful for
+// reproducible test fixtures. Defaults to crypto/rand when nil or not provided
+func EntropySource(rand io.Reader) GenerateOpt {
+	return func(opts *generateOpts) {
+		opts.rand = rand
+	}
+}
+
+// GeneratePrivateKey generates a private key suitable for key agreement using the given algorithm
+//
+// # Note
+//
+// EC private keys are interchangeable between ECDSA and ECDH. As such, EC keys are generated by
+// [github.com/tbd54566975/web5-go/crypto/dsa/ecdsa.GeneratePrivateKeyFromReader]
+func GeneratePrivateKey(algorithmID string, opts ...GenerateOpt) (jwk.JWK, error) {
+	o := generateOpts{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.rand == nil {
+		o.rand = rand.Reader
+	}
+
+	switch algorithmID {
+	case X25519AlgorithmID:
+		return x25519GeneratePrivateKey(o.rand)
+	default:
+		if !algorithmIDs[algorithmID] {
+			return jwk.JWK{}, fmt.Errorf("unsupported algorithm: %s", algorithmID)
+		}
+
+		return ecdsa.GeneratePrivateKeyFromReader(algorithmID, o.rand)
+	}
+}
+
+// GetPublicKey builds a public key from the given private key
+func GetPublicKey(privateKey jwk.JWK) jwk.JWK {
+	switch privateKey.KTY {
+	case KeyTypeOKP:
+		return jwk.JWK{KTY: privateKey.KTY, CRV: privateKey.CRV, X: privateKey.X}
+	default:
+		return ecdsa.GetPublicKey(privateKey)
+	}
+}
+
+// SharedSecret computes the raw ECDH shared secret (often referred to as Z) between the given
+// private key and the given public key. Both keys must be on the same curve.
+//
+// For EC keys the shared secret is the x coordinate of the shared point as described in
+// https://www.secg.org/sec1-v2.pdf section 3.3.1
+func SharedSecret(privateKey jwk.JWK, publicKey jwk.JWK) ([]byte, error) {
+	if privateKey.D == "" {
+		return nil, errors.New("d must be set")
+	}
+
+	if privateKey.KTY != publicKey.KTY || privateKey.CRV != publicKey.CRV {
+		return nil, fmt.Errorf("curve mismatch: private key is %s, public key is %s", privateKey.CRV, publicKey.CRV)
+	}
+
+	switch privateKey.CRV {
+	case X25519JWACurve:
+		return X25519SharedSecret(privateKey, publicKey)
+	case ecdsa.SECP256K1JWACurve:
+		return SECP256K1SharedSecret(privateKey, publicKey)
+	case ecdsa.SECP256R1JWACurve, ecdsa.SECP384R1JWACurve, ecdsa.SECP521R1JWACurve:
+		return nistSharedSecret(privateKey, publicKey)
+	default:
+		return nil, fmt.Errorf("unsupported curve: %s", privateKey.CRV)
+	}
+}
+
+// SharedSecretWithPrivateKeyBytes computes the raw ECDH shared secret like [SharedSecret], using the raw
+// private key, i.e. the decoded d of a private JWK, instead of a private JWK. key identifies the curve and may
+// be the public key. The private key bytes are neither modified nor retained
+func SharedSecretWithPrivateKeyBytes(key jwk.JWK, privateKeyBytes []byte, publicKey jwk.JWK) ([]byte, error) {
+	if len(privateKeyBytes) == 0 {
+		return nil, errors.New("d must be set")
+	}
+
+	if key.KTY != publicKey.KTY || key.CRV != publicKey.CRV {
+		return nil, fmt.Errorf("curve mismatch: private key is %s, public key is %s", key.CRV, publicKey.CRV)
+	}
+
+	switch key.CRV {
+	case X25519JWACurve:
+		return x25519SharedSecret(privateKeyBytes, publicKey)
+	case ecdsa.SECP256K1JWACurve:
+		return secp256k1SharedSecret(privateKeyBytes, publicKey)
+	case ecdsa.SECP256R1JWACurve, ecdsa.SECP384R1JWACurve, ecdsa.SECP521R1JWACurve:
+		return nistSharedSecretWithPrivateKeyBytes(key.CRV, privateKeyBytes, publicKey)
+	default:
+		return nil, fmt.Errorf("unsupported curve: %s", key.CRV)
+	}
+}
+
+// BytesToPublicKey deserializes the given byte array into a jwk.JWK for the given cryptographic algorithm
+func BytesToPublicKey(algorithmID string, input []byte) (jwk.JWK, error) {
+	switch algorithmID {
+	case X25519AlgorithmID:
+		return X25519BytesToPublicKey(input)
+	default:
+		if !algorithmIDs[algorithmID] {
+			return jwk.JWK{}, fmt.Errorf("unsupported algorithm: %s", algorithmID)
+		}
+
+		return ecdsa.BytesToPublicKey(algorithmID, input)
+	}
+}
+
+// PublicKeyToBytes serializes the given public key into a byte array
+func PublicKeyToBytes(publicKey jwk.JWK) ([]byte, error) {
+	switch publicKey.CRV {
+	case X25519JWACurve:
+		return X25519PublicKeyToBytes(publicKey)
+	default:
+		if !algorithmIDs[publicKey.CRV] {
+			return nil, fmt.Errorf("unsupported curve: %s", publicKey.CRV)
+		}
+
+		return ecdsa.PublicKeyToBytes(publicKey)
+	}
+}
+
+// SupportsAlgorithmID informs as to whether or not the given algorithm ID is supported by this package
+func SupportsAlgorithmID(id string) bool {
+	return algorithmIDs[id]
+}
+
+// AlgorithmID returns the algorithm ID for the given jwk.JWK
+func AlgorithmID(jwk *jwk.JWK) (string, error) {
+	switch jwk.CRV {
+	case X25519JWACurve:
+		return X25519AlgorithmID, nil
+	default:
+		if !algorithmIDs[jwk.CRV] {
+			return "", fmt.Errorf("unsupported curve: %s", jwk.CRV)
+		}
+
+		return ecdsa.AlgorithmID(jwk)
+	}
+}
@@ -0,0 +1,83 @@
+package ecdh_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/crypto/ecdh"
+)
+
+func TestSharedSecret(t *testing.T) {
+	algorithmIDs := []string{
+		ecdh.X25519AlgorithmID,
+		dsa.AlgorithmIDSECP256K1,
+		dsa.AlgorithmIDSECP256R1,
+		dsa.AlgorithmIDSECP384R1,
+		dsa.AlgorithmIDSECP521R1,
+	}
+
+	for _, algorithmID := range algorithmIDs {
+		t.Run(algorithmID, func(t *testing.T) {
+			alice, err := ecdh.GeneratePrivateKey(algorithmID)
+			assert.NoError(t, err)
+
+			bob, err := ecdh.GeneratePrivateKey(algorithmID)
+			assert.NoError(t, err)
+
+			aliceSecret, err := ecdh.SharedSecret(alice, ecdh.GetPublicKey(bob))
+			assert.NoError(t, err)
+
+			bobSecret, err := ecdh.SharedSecret(bob, ecdh.GetPublicKey(alice))
+			assert.NoError(t, err)
+
+			assert.Equal(t, aliceSecret, bobSecret)
+			assert.NotZero(t, len(aliceSecret))
+
+			alicePrivateKeyBytes, err := base64.RawURLEncoding.DecodeString(alice.D)
+			assert.NoError(t, err)
+
+			secret, err := ecdh.SharedSecretWithPrivateKeyBytes(ecdh.GetPublicKey(alice), alicePrivateKeyBytes, ecdh.GetPublicKey(bob))
+			assert.NoError(t, err)
+			assert.Equal(t, aliceSecret, secret)
+
+			alicePublicKey := ecdh.GetPublicKey(alice)
+			pubKeyBytes, err := ecdh.PublicKeyToBytes(alicePublicKey)
+			assert.NoError(t, err)
+
+			parsed, err := ecdh.BytesToPublicKey(algorithmID, pubKeyBytes)
+			assert.NoError(t, err)
+			assert.Equal(t, alicePublicKey, parsed)
+
+			algID, err := ecdh.AlgorithmID(&alicePublicKey)
+			assert.NoError(t, err)
+			assert.Equal(t, algorithmID, algID)
+		})
+	}
+}
+
+func TestSharedSecret_CurveMismatch(t *testing.T) {
+	alice, err := ecdh.GeneratePrivateKey(ecdh.X25519AlgorithmID)
+	assert.NoError(t, err)
+
+	bob, err := ecdh.GeneratePrivateKey(dsa.AlgorithmIDSECP256R1)
+	assert.NoError(t, err)
+
+	_, err = ecdh.SharedSecret(alice, ecdh.GetPublicKey(bob))
+	assert.Error(t, err)
+}
+
+func TestSharedSecret_PublicKeyOnly(t *testing.T) {
+	alice, err := ecdh.GeneratePrivateKey(ecdh.X25519AlgorithmID)
+	assert.NoError(t, err)
+
+	_, err = ecdh.SharedSecret(ecdh.GetPublicKey(alice), ecdh.GetPublicKey(alice))
+	assert.Error(t, err)
+}
+
+func TestGeneratePrivateKey_Unsupported(t *testing.T) {
+	_, err := ecdh.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.Error(t, err)
+	assert.False(t, ecdh.SupportsAlgorithmID(dsa.AlgorithmIDED25519))
+}
@@ -0,0 +1,57 @@
+package ecdh
+
+import (
+	_ecdh "crypto/ecdh"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/tbd54566975/web5-go/crypto/dsa/ecdsa"
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+var nistCurves = map[string]_ecdh.Curve{
+	ecdsa.SECP256R1JWACurve: _ecdh.P256(),
+	ecdsa.SECP384R1JWACurve: _ecdh.P384(),
+	ecdsa.SECP521R1JWACurve: _ecdh.P521(),
+}
+
+// nistSharedSecret computes the ECDH shared secret over P-256, P-384 or P-521
+func nistSharedSecret(privateKey jwk.JWK, publicKey jwk.JWK) ([]byte, error) {
+	privateKeyBytes, err := base64.RawURLEncoding.DecodeString(privateKey.D)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode d %w", err)
+	}
+	defer clear(privateKeyBytes)
+
+	return nistSharedSecretWithPrivateKeyBytes(privateKey.CRV, privateKeyBytes, publicKey)
+}
+
+// nistSharedSecretWithPrivateKeyBytes is [nistSharedSecret] for the raw private key scalar, which is left untouched
+func nistSharedSecretWithPrivateKeyBytes(crv string, privateKeyBytes []byte, publicKey jwk.JWK) ([]byte, error) {
+	curve, ok := nistCurves[crv]
+	if !ok {
+		return nil, fmt.Errorf("unsupported curve: %s", crv)
+	}
+
+	key, err := curve.NewPrivateKey(privateKeyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	publicKeyBytes, err := ecdsa.PublicKeyToBytes(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey, err := curve.NewPublicKey(publicKeyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+
+	sharedSecret, err := key.ECDH(pubKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute shared secret: %w", err)
+	}
+
+	return sharedSecret, nil
+}
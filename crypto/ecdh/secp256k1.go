@@ -0,0 +1,40 @@
+package ecdh
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	_secp256k1 "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/tbd54566975/web5-go/crypto/dsa/ecdsa"
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+// SECP256K1SharedSecret computes the secp256k1 ECDH shared secret between the given private and
+// public keys. The returned secret is the 32 byte x coordinate of the shared point
+func SECP256K1SharedSecret(privateKey jwk.JWK, publicKey jwk.JWK) ([]byte, error) {
+	privateKeyBytes, err := base64.RawURLEncoding.DecodeString(privateKey.D)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode d %w", err)
+	}
+	defer clear(privateKeyBytes)
+
+	return secp256k1SharedSecret(privateKeyBytes, publicKey)
+}
+
+// secp256k1SharedSecret is [SECP256K1SharedSecret] for the raw private key scalar, which is left untouched
+func secp256k1SharedSecret(privateKeyBytes []byte, publicKey jwk.JWK) ([]byte, error) {
+	publicKeyBytes, err := ecdsa.SECP256K1PublicKeyToBytes(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey, err := _secp256k1.ParsePubKey(publicKeyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	key := _secp256k1.PrivKeyFromBytes(privateKeyBytes)
+	defer key.Zero()
+
+	return _secp256k1.GenerateSharedSecret(key, pubKey), nil
+}
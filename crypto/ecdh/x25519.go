@@ -0,0 +1,112 @@
+package ecdh
+
+import (
+	_ecdh "crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+const (
+	X25519JWACurve    string = "X25519"
+	X25519AlgorithmID string = X25519JWACurve
+)
+
+// X25519GeneratePrivateKey generates a new X25519 private key
+func X25519GeneratePrivateKey() (jwk.JWK, error) {
+	return x25519GeneratePrivateKey(rand.Reader)
+}
+
+// x25519GeneratePrivateKey generates a private key from 32 bytes read from the given entropy source
+func x25519GeneratePrivateKey(rand io.Reader) (jwk.JWK, error) {
+	scalar := make([]byte, 32)
+	if _, err := io.ReadFull(rand, scalar); err != nil {
+		return jwk.JWK{}, fmt.Errorf("failed to generate private key: %w", err)
+	}
+
+	key, err := _ecdh.X25519().NewPrivateKey(scalar)
+	if err != nil {
+		return jwk.JWK{}, fmt.Errorf("failed to generate private key: %w", err)
+	}
+
+	privateKey := jwk.JWK{
+		KTY: KeyTypeOKP,
+		CRV: X25519JWACurve,
+		D:   base64.RawURLEncoding.EncodeToString(key.Bytes()),
+		X:   base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+	}
+
+	return privateKey, nil
+}
+
+// X25519SharedSecret computes the X25519 shared secret between the given private and public keys
+func X25519SharedSecret(privateKey jwk.JWK, publicKey jwk.JWK) ([]byte, error) {
+	privateKeyBytes, err := base64.RawURLEncoding.DecodeString(privateKey.D)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode d %w", err)
+	}
+	defer clear(privateKeyBytes)
+
+	return x25519SharedSecret(privateKeyBytes, publicKey)
+}
+
+// x25519SharedSecret is [X25519SharedSecret] for the raw private key, which is left untouched
+func x25519SharedSecret(privateKeyBytes []byte, publicKey jwk.JWK) ([]byte, error) {
+	key, err := _ecdh.X25519().NewPrivateKey(privateKeyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	publicKeyBytes, err := X25519PublicKeyToBytes(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey, err := _ecdh.X25519().NewPublicKey(publicKeyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+
+	// errors if the result is the all-zero value, i.e. the public key is a low order point
+	sharedSecret, err := key.ECDH(pubKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute shared secret: %w", err)
+	}
+
+	return sharedSecret, nil
+}
+
+// X25519BytesToPublicKey deserializes the byte array into a jwk.JWK public key
+func X25519BytesToPublicKey(input []byte) (jwk.JWK, error) {
+	if _, err := _ecdh.X25519().NewPublicKey(input); err != nil {
+		return jwk.JWK{}, errors.New("invalid public key")
+	}
+
+	return jwk.JWK{
+		KTY: KeyTypeOKP,
+		CRV: X25519JWACurve,
+		X:   base64.RawURLEncoding.EncodeToString(input),
+	}, nil
+}
+
+// X25519PublicKeyToBytes serializes the given public key into a byte array
+func X25519PublicKeyToBytes(publicKey jwk.JWK) ([]byte, error) {
+	if publicKey.X == "" {
+		return nil, errors.New("x must be set")
+	}
+
+	publicKeyBytes, err := base64.RawURLEncoding.DecodeString(publicKey.X)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode x %w", err)
+	}
+
+	if _, err := _ecdh.X25519().NewPublicKey(publicKeyBytes); err != nil {
+		return nil, errors.New("invalid public key")
+	}
+
+	return publicKeyBytes, nil
+}
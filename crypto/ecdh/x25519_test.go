@@ -0,0 +1,91 @@
+package ecdh_test
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/crypto/ecdh"
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+func hexToJWK(t *testing.T, d string, x string) jwk.JWK {
+	t.Helper()
+
+	dBytes, err := hex.DecodeString(d)
+	assert.NoError(t, err)
+
+	xBytes, err := hex.DecodeString(x)
+	assert.NoError(t, err)
+
+	return jwk.JWK{
+		KTY: ecdh.KeyTypeOKP,
+		CRV: ecdh.X25519JWACurve,
+		D:   base64.RawURLEncoding.EncodeToString(dBytes),
+		X:   base64.RawURLEncoding.EncodeToString(xBytes),
+	}
+}
+
+func TestX25519GeneratePrivateKey(t *testing.T) {
+	key, err := ecdh.X25519GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	assert.Equal(t, ecdh.KeyTypeOKP, key.KTY)
+	assert.Equal(t, ecdh.X25519JWACurve, key.CRV)
+	assert.True(t, key.D != "", "privateJwk.D is empty")
+	assert.True(t, key.X != "", "privateJwk.X is empty")
+}
+
+func TestX25519SharedSecret(t *testing.T) {
+	// vector taken from https://datatracker.ietf.org/doc/html/rfc7748#section-6.1
+	alice := hexToJWK(t,
+		"77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a",
+		"8520f0098930a754748b7ddcb43ef75a0dbf3a0d26381af4eba4a98eaa9b4e6a",
+	)
+	bob := hexToJWK(t,
+		"5dab087e624a8a4b79e17f8b83800ee66f3bb1292618b6fd1c2f8b27ff88e0eb",
+		"de9edb7d7b7dc1b4d35b61c2ece435373f8343c85b78674dadfc7e146f882b4f",
+	)
+
+	expected := "4a5d9d5ba4ce2de1728e3bf480350f25e07e21c947d19e3376f09b3c1e161742"
+
+	sharedSecret, err := ecdh.X25519SharedSecret(alice, ecdh.GetPublicKey(bob))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, hex.EncodeToString(sharedSecret))
+
+	sharedSecret, err = ecdh.X25519SharedSecret(bob, ecdh.GetPublicKey(alice))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, hex.EncodeToString(sharedSecret))
+}
+
+func TestX25519SharedSecret_LowOrderPoint(t *testing.T) {
+	alice, err := ecdh.X25519GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	lowOrder := jwk.JWK{
+		KTY: ecdh.KeyTypeOKP,
+		CRV: ecdh.X25519JWACurve,
+		X:   base64.RawURLEncoding.EncodeToString(make([]byte, 32)),
+	}
+
+	_, err = ecdh.X25519SharedSecret(alice, lowOrder)
+	assert.Error(t, err)
+}
+
+func TestX25519BytesToPublicKey(t *testing.T) {
+	pubKeyBytes, err := hex.DecodeString("8520f0098930a754748b7ddcb43ef75a0dbf3a0d26381af4eba4a98eaa9b4e6a")
+	assert.NoError(t, err)
+
+	key, err := ecdh.X25519BytesToPublicKey(pubKeyBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, ecdh.KeyTypeOKP, key.KTY)
+	assert.Equal(t, ecdh.X25519JWACurve, key.CRV)
+
+	roundTripped, err := ecdh.X25519PublicKeyToBytes(key)
+	assert.NoError(t, err)
+	assert.Equal(t, pubKeyBytes, roundTripped)
+
+	_, err = ecdh.X25519BytesToPublicKey([]byte{0x00, 0x01, 0x02, 0x03})
+	assert.Error(t, err)
+}
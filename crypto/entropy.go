@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
+	"io"
 )
 
 // EntropySize represents the size of the entropy in bits, i.e. Entropy128 is equal to 128 bits (or 16 bytes) of entrop
@@ -20,12 +21,18 @@ const (
 
 // GenerateEntropy generates a random byte array of size n bytes
 func GenerateEntropy(n EntropySize) ([]byte, error) {
+	return GenerateEntropyFromReader(n, rand.Reader)
+}
+
+// GenerateEntropyFromReader reads a byte array of size n bytes from the given entropy source instead of
+// crypto/rand. This is useful for reproducible test fixtures.
+func GenerateEntropyFromReader(n EntropySize, rand io.Reader) ([]byte, error) {
 	if n <= 0 {
 		return nil, errors.New("entropy byte size must be > 0")
 	}
 
 	bytes := make([]byte, n)
-	_, err := rand.Read(bytes)
+	_, err := io.ReadFull(rand, bytes)
 	if err != nil {
 		return nil, err
 	}
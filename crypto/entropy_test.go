@@ -1,6 +1,7 @@
 package crypto_test
 
 import (
+	"bytes"
 	"encoding/hex"
 	"testing"
 
@@ -55,3 +56,14 @@ func Test_GenerateNonce_InvalidSize(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, "", nonce)
 }
+
+func Test_GenerateEntropyFromReader(t *testing.T) {
+	source := bytes.NewReader([]byte{1, 2, 3, 4, 5})
+
+	entropy, err := crypto.GenerateEntropyFromReader(4, source)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3, 4}, entropy)
+
+	_, err = crypto.GenerateEntropyFromReader(4, source)
+	assert.Error(t, err)
+}
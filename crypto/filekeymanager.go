@@ -0,0 +1,677 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/crypto/ecdh"
+	"github.com/tbd54566975/web5-go/jwk"
+	"golang.org/x/crypto/scrypt"
+)
+
+// ErrKeyManagerLocked is returned by [FileKeyManager] when an operation requiring access to a
+// private key is attempted while the key manager is locked
+var ErrKeyManagerLocked = errors.New("key manager is locked")
+
+const (
+	keystoreFileName = "keystore.json"
+	keystoreVersion  = 1
+	keystoreKDF      = "scrypt"
+	keystoreCipher   = "A256GCM"
+)
+
+// bounds of the scrypt parameters read from keystore.json, so that a corrupted or tampered key store can't
+// make deriving the encryption key exhaust memory or CPU
+const (
+	maxScryptN = 1 << 20
+	maxScryptR = 32
+	maxScryptP = 16
+	// maxScryptMemory bounds the memory used by scrypt, which is 128 * N * r bytes
+	maxScryptMemory = 1 << 30
+)
+
+// keyIDPattern restricts key ids to the base64url alphabet used by JWK thumbprints so that
+// a key id can never escape the key store directory
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// FileKeyManager is an implementation of KeyManager that persists keys to a directory on disk.
+// Every private key is encrypted at rest with AES-GCM using a key derived from a passphrase with
+// scrypt. Public keys are stored alongside the ciphertext so that [FileKeyManager.GetPublicKey]
+// keeps working while the key manager is locked.
+//
+// A FileKeyManager is safe for concurrent use by multiple goroutines.
+type FileKeyManager struct {
+	dir string
+	kdf keystoreKDFParams
+
+	mu            sync.RWMutex
+	encryptionKey []byte
+}
+
+// options that [NewFileKeyManager] can take
+type fileKeyManagerOpts struct {
+	n int
+	r int
+	p int
+}
+
+// FileKeyManagerOpt is a type that represents an option that can be passed to [NewFileKeyManager]
+type FileKeyManagerOpt func(opts *fileKeyManagerOpts)
+
+// ScryptParams is an option that can be passed to [NewFileKeyManager]. It is used to set the scrypt
+// cost parameters when creating a new key store. Defaults to N=32768, r=8, p=1. Existing key stores
+// always use the parameters they were created with.
+func ScryptParams(n, r, p int) FileKeyManagerOpt {
+	return func(opts *fileKeyManagerOpts) {
+		opts.n = n
+		opts.r = r
+		opts.p = p
+	}
+}
+
+// keystoreKDFParams is persisted to keystore.json within the key store directory
+type keystoreKDFParams struct {
+	Version int    `json:"version"`
+	KDF     string `json:"kdf"`
+	Cipher  string `json:"cipher"`
+	N       int    `json:"n"`
+	R       int    `json:"r"`
+	P       int    `json:"p"`
+	Salt    string `json:"salt"`
+	// Check is an AES-GCM tag over an empty plaintext used to detect an incorrect passphrase
+	Check      string `json:"check"`
+	CheckNonce string `json:"checkNonce"`
+}
+
+// encryptedKeyFile is persisted to <key id>.json within the key store directory. The public key is bound to
+// the key id, which is its thumbprint, and the key id and the metadata set on import are authenticated along
+// with the ciphertext (see [encryptedKeyFile.additionalData])
+type encryptedKeyFile struct {
+	PublicKey  jwk.JWK     `json:"publicKey"`
+	Metadata   KeyMetadata `json:"metadata"`
+	Nonce      string      `json:"nonce"`
+	Ciphertext string      `json:"ciphertext"`
+}
+
+// additionalData returns the AES-GCM additional data of the key file with the given key id. Tags are left
+// out because they can be changed while the key manager is locked
+func (f encryptedKeyFile) additionalData(keyID string) ([]byte, error) {
+	metadata, err := json.Marshal(KeyMetadata{AlgorithmID: f.Metadata.AlgorithmID, CreatedAt: f.Metadata.CreatedAt})
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize key metadata: %w", err)
+	}
+
+	return append([]byte(keyID+"."), metadata...), nil
+}
+
+// NewFileKeyManager returns a new [FileKeyManager] that stores keys within the given directory. The
+// directory and key store are created if they do not already exist. The returned key manager is
+// unlocked with the given passphrase.
+func NewFileKeyManager(dir string, passphrase []byte, opts ...FileKeyManagerOpt) (*FileKeyManager, error) {
+	o := fileKeyManagerOpts{n: 1 << 15, r: 8, p: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create key store directory: %w", err)
+	}
+
+	k := &FileKeyManager{dir: dir}
+
+	bytes, err := os.ReadFile(filepath.Join(dir, keystoreFileName))
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(bytes, &k.kdf); err != nil {
+			return nil, fmt.Errorf("failed to parse key store: %w", err)
+		}
+
+		if k.kdf.Version != keystoreVersion || k.kdf.KDF != keystoreKDF || k.kdf.Cipher != keystoreCipher {
+			return nil, fmt.Errorf("unsupported key store version %d (%s, %s)", k.kdf.Version, k.kdf.KDF, k.kdf.Cipher)
+		}
+
+		if err := k.Unlock(passphrase); err != nil {
+			return nil, err
+		}
+	case errors.Is(err, os.ErrNotExist):
+		if err := k.initialize(passphrase, o); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("failed to read key store: %w", err)
+	}
+
+	return k, nil
+}
+
+func (k *FileKeyManager) initialize(passphrase []byte, o fileKeyManagerOpts) error {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	k.kdf = keystoreKDFParams{
+		Version: keystoreVersion,
+		KDF:     keystoreKDF,
+		Cipher:  keystoreCipher,
+		N:       o.n,
+		R:       o.r,
+		P:       o.p,
+		Salt:    base64.RawURLEncoding.EncodeToString(salt),
+	}
+
+	encryptionKey, err := k.deriveKey(passphrase)
+	if err != nil {
+		return err
+	}
+
+	nonce, check, err := seal(encryptionKey, nil, []byte(keystoreFileName))
+	if err != nil {
+		return err
+	}
+
+	k.kdf.CheckNonce = base64.RawURLEncoding.EncodeToString(nonce)
+	k.kdf.Check = base64.RawURLEncoding.EncodeToString(check)
+
+	bytes, err := json.Marshal(k.kdf)
+	if err != nil {
+		return fmt.Errorf("failed to serialize key store: %w", err)
+	}
+
+	if err := writeFileAtomic(filepath.Join(k.dir, keystoreFileName), bytes); err != nil {
+		return fmt.Errorf("failed to write key store: %w", err)
+	}
+
+	k.encryptionKey = encryptionKey
+
+	return nil
+}
+
+// Unlock derives the key store encryption key from the given passphrase. An error is returned
+// if the passphrase is incorrect.
+func (k *FileKeyManager) Unlock(passphrase []byte) error {
+	nonce, err := base64.RawURLEncoding.DecodeString(k.kdf.CheckNonce)
+	if err != nil {
+		return fmt.Errorf("failed to decode key store check nonce: %w", err)
+	}
+
+	check, err := base64.RawURLEncoding.DecodeString(k.kdf.Check)
+	if err != nil {
+		return fmt.Errorf("failed to decode key store check: %w", err)
+	}
+
+	encryptionKey, err := k.deriveKey(passphrase)
+	if err != nil {
+		return err
+	}
+
+	if _, err := open(encryptionKey, nonce, check, []byte(keystoreFileName)); err != nil {
+		clear(encryptionKey)
+		return errors.New("incorrect passphrase")
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	clear(k.encryptionKey)
+	k.encryptionKey = encryptionKey
+
+	return nil
+}
+
+// Lock discards the key store encryption key from memory. Operations that require access to a
+// private key return [ErrKeyManagerLocked] until [FileKeyManager.Unlock] is called.
+func (k *FileKeyManager) Lock() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	clear(k.encryptionKey)
+	k.encryptionKey = nil
+}
+
+// Close locks the key manager, wiping the key store encryption key from memory. Private keys are only
+// decrypted for the duration of a single operation. Signing and key agreement decode d straight into
+// bytes that are wiped afterwards, whereas [FileKeyManager.ExportKey] returns a [jwk.JWK] whose d is
+// an immutable string that can't be wiped. Unlike
+// [LocalKeyManager.Close], a closed FileKeyManager can be reopened with [FileKeyManager.Unlock].
+func (k *FileKeyManager) Close() error {
+	k.Lock()
+
+	return nil
+}
+
+// Locked informs as to whether or not the key manager is locked
+func (k *FileKeyManager) Locked() bool {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.encryptionKey == nil
+}
+
+// GeneratePrivateKey generates a new private key using the algorithm provided,
+// stores it in the key store and returns the key id
+func (k *FileKeyManager) GeneratePrivateKey(algorithmID string) (string, error) {
+	key, err := generatePrivateKey(algorithmID, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate private key: %w", err)
+	}
+
+	return k.ImportKey(key)
+}
+
+// GetPublicKey returns the public key for the given key id. GetPublicKey works while the key manager is locked.
+func (k *FileKeyManager) GetPublicKey(keyID string) (jwk.JWK, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	keyFile, err := k.readKeyFile(keyID)
+	if err != nil {
+		return jwk.JWK{}, err
+	}
+
+	return keyFile.PublicKey, nil
+}
+
+// Sign signs the payload with the private key for the given key id
+func (k *FileKeyManager) Sign(keyID string, payload []byte) ([]byte, error) {
+	publicKey, d, err := k.getPrivateKeyBytes(keyID)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(d)
+
+	return dsa.SignWithPrivateKeyBytes(payload, publicKey, d)
+}
+
+// SharedSecret computes the raw ECDH shared secret between the private key for the given key id
+// and the given public key
+func (k *FileKeyManager) SharedSecret(keyID string, publicKey jwk.JWK) ([]byte, error) {
+	key, d, err := k.getPrivateKeyBytes(keyID)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(d)
+
+	return ecdh.SharedSecretWithPrivateKeyBytes(key, d, publicKey)
+}
+
+// ExportKey exports the key specific by the key ID from the [FileKeyManager]
+func (k *FileKeyManager) ExportKey(keyID string) (jwk.JWK, error) {
+	return k.getPrivateJWK(keyID)
+}
+
+// ImportKey encrypts and persists the key within the [FileKeyManager] and returns the key alias
+func (k *FileKeyManager) ImportKey(key jwk.JWK) (string, error) {
+	if key.D == "" {
+		return "", errors.New("d must be set")
+	}
+
+	keyAlias, err := key.ComputeThumbprint()
+	if err != nil {
+		return "", fmt.Errorf("failed to compute key alias: %w", err)
+	}
+
+	plaintext, err := json.Marshal(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize key: %w", err)
+	}
+	defer clear(plaintext)
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.encryptionKey == nil {
+		return "", ErrKeyManagerLocked
+	}
+
+	// re-importing a key keeps its original metadata
+	metadata := KeyMetadata{AlgorithmID: keyAlgorithmID(key), CreatedAt: time.Now().UTC().Round(0)}
+	if existing, err := k.readKeyFile(keyAlias); err == nil {
+		metadata = existing.Metadata
+	}
+
+	keyFile := encryptedKeyFile{PublicKey: getPublicKey(key), Metadata: metadata}
+
+	additionalData, err := keyFile.additionalData(keyAlias)
+	if err != nil {
+		return "", err
+	}
+
+	nonce, ciphertext, err := seal(k.encryptionKey, plaintext, additionalData)
+	if err != nil {
+		return "", err
+	}
+
+	keyFile.Nonce = base64.RawURLEncoding.EncodeToString(nonce)
+	keyFile.Ciphertext = base64.RawURLEncoding.EncodeToString(ciphertext)
+
+	if err := k.writeKeyFile(keyAlias, keyFile); err != nil {
+		return "", err
+	}
+
+	return keyAlias, nil
+}
+
+// ListKeys returns the ids of all keys stored in the [FileKeyManager] in sorted order. Files within the
+// key store directory that are not key files are ignored. ListKeys works while the key manager is locked.
+func (k *FileKeyManager) ListKeys() []string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	entries, err := os.ReadDir(k.dir)
+	if err != nil {
+		return []string{}
+	}
+
+	keyIDs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		keyID, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() || entry.Name() == keystoreFileName || !keyIDPattern.MatchString(keyID) {
+			continue
+		}
+
+		keyIDs = append(keyIDs, keyID)
+	}
+
+	// os.ReadDir returns entries sorted by filename
+	return keyIDs
+}
+
+// DeleteKey permanently removes the key with the given key id from the [FileKeyManager]
+func (k *FileKeyManager) DeleteKey(keyID string) error {
+	if !keyIDPattern.MatchString(keyID) {
+		return fmt.Errorf("invalid key alias %s", keyID)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	// the key file isn't parsed so that tampered key files can be deleted too
+	err := os.Remove(filepath.Join(k.dir, keyID+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("key with alias %s not found", keyID)
+	} else if err != nil {
+		return fmt.Errorf("failed to delete key file: %w", err)
+	}
+
+	return nil
+}
+
+// KeyMetadata returns the metadata of the key with the given key id. KeyMetadata works while the key manager is locked.
+func (k *FileKeyManager) KeyMetadata(keyID string) (KeyMetadata, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	keyFile, err := k.readKeyFile(keyID)
+	if err != nil {
+		return KeyMetadata{}, err
+	}
+
+	return keyFile.Metadata, nil
+}
+
+// SetKeyTags replaces the tags of the key with the given key id. SetKeyTags works while the key manager is locked.
+func (k *FileKeyManager) SetKeyTags(keyID string, tags ...string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	keyFile, err := k.readKeyFile(keyID)
+	if err != nil {
+		return err
+	}
+
+	keyFile.Metadata.Tags = tags
+
+	return k.writeKeyFile(keyID, keyFile)
+}
+
+func (k *FileKeyManager) getPrivateJWK(keyID string) (jwk.JWK, error) {
+	_, plaintext, err := k.decryptKey(keyID)
+	if err != nil {
+		return jwk.JWK{}, err
+	}
+	defer clear(plaintext)
+
+	var key jwk.JWK
+	if err := json.Unmarshal(plaintext, &key); err != nil {
+		return jwk.JWK{}, fmt.Errorf("failed to parse key: %w", err)
+	}
+
+	return key, nil
+}
+
+// getPrivateKeyBytes returns the public key and the raw private key for the given key id without
+// decoding the private key into a string. The caller must clear the returned bytes.
+func (k *FileKeyManager) getPrivateKeyBytes(keyID string) (jwk.JWK, []byte, error) {
+	publicKey, plaintext, err := k.decryptKey(keyID)
+	if err != nil {
+		return jwk.JWK{}, nil, err
+	}
+	defer clear(plaintext)
+
+	var key struct {
+		D privateKeyBytes `json:"d"`
+	}
+
+	if err := json.Unmarshal(plaintext, &key); err != nil {
+		clear(key.D)
+		return jwk.JWK{}, nil, fmt.Errorf("failed to parse key: %w", err)
+	}
+
+	if len(key.D) == 0 {
+		return jwk.JWK{}, nil, errors.New("d must be set")
+	}
+
+	return publicKey, key.D, nil
+}
+
+// decryptKey returns the public key and the decrypted private JWK for the given key id. The caller
+// must clear the returned plaintext.
+func (k *FileKeyManager) decryptKey(keyID string) (jwk.JWK, []byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if k.encryptionKey == nil {
+		return jwk.JWK{}, nil, ErrKeyManagerLocked
+	}
+
+	keyFile, err := k.readKeyFile(keyID)
+	if err != nil {
+		return jwk.JWK{}, nil, err
+	}
+
+	nonce, err := base64.RawURLEncoding.DecodeString(keyFile.Nonce)
+	if err != nil {
+		return jwk.JWK{}, nil, fmt.Errorf("failed to decode nonce: %w", err)
+	}
+
+	ciphertext, err := base64.RawURLEncoding.DecodeString(keyFile.Ciphertext)
+	if err != nil {
+		return jwk.JWK{}, nil, fmt.Errorf("failed to decode ciphertext: %w", err)
+	}
+
+	additionalData, err := keyFile.additionalData(keyID)
+	if err != nil {
+		return jwk.JWK{}, nil, err
+	}
+
+	plaintext, err := open(k.encryptionKey, nonce, ciphertext, additionalData)
+	if err != nil {
+		return jwk.JWK{}, nil, fmt.Errorf("failed to decrypt key with alias %s: %w", keyID, err)
+	}
+
+	return keyFile.PublicKey, plaintext, nil
+}
+
+// privateKeyBytes decodes the base64url encoded d of a JWK straight into bytes so that no string
+// copy of the private key, which couldn't be wiped, is left behind
+type privateKeyBytes []byte
+
+func (b *privateKeyBytes) UnmarshalJSON(data []byte) error {
+	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' {
+		return errors.New("d must be a string")
+	}
+
+	encoded := data[1 : len(data)-1]
+	decoded := make([]byte, base64.RawURLEncoding.DecodedLen(len(encoded)))
+
+	n, err := base64.RawURLEncoding.Decode(decoded, encoded)
+	if err != nil {
+		clear(decoded)
+		return fmt.Errorf("failed to decode d: %w", err)
+	}
+
+	*b = decoded[:n]
+
+	return nil
+}
+
+func (k *FileKeyManager) readKeyFile(keyID string) (encryptedKeyFile, error) {
+	if !keyIDPattern.MatchString(keyID) {
+		return encryptedKeyFile{}, fmt.Errorf("invalid key alias %s", keyID)
+	}
+
+	bytes, err := os.ReadFile(filepath.Join(k.dir, keyID+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return encryptedKeyFile{}, fmt.Errorf("key with alias %s not found", keyID)
+	} else if err != nil {
+		return encryptedKeyFile{}, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	var keyFile encryptedKeyFile
+	if err := json.Unmarshal(bytes, &keyFile); err != nil {
+		return encryptedKeyFile{}, fmt.Errorf("failed to parse key file: %w", err)
+	}
+
+	// the key id is the thumbprint of the public key. a mismatch means that the public key was swapped
+	thumbprint, err := keyFile.PublicKey.ComputeThumbprint()
+	if err != nil || thumbprint != keyID {
+		return encryptedKeyFile{}, fmt.Errorf("public key of key with alias %s does not match its alias", keyID)
+	}
+
+	return keyFile, nil
+}
+
+func (k *FileKeyManager) writeKeyFile(keyID string, keyFile encryptedKeyFile) error {
+	bytes, err := json.Marshal(keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to serialize key file: %w", err)
+	}
+
+	if err := writeFileAtomic(filepath.Join(k.dir, keyID+".json"), bytes); err != nil {
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+
+	return nil
+}
+
+func (k *FileKeyManager) deriveKey(passphrase []byte) ([]byte, error) {
+	salt, err := base64.RawURLEncoding.DecodeString(k.kdf.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode salt: %w", err)
+	}
+
+	if err := checkScryptParams(k.kdf.N, k.kdf.R, k.kdf.P); err != nil {
+		return nil, err
+	}
+
+	key, err := scrypt.Key(passphrase, salt, k.kdf.N, k.kdf.R, k.kdf.P, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive encryption key: %w", err)
+	}
+
+	return key, nil
+}
+
+// checkScryptParams ensures that the given scrypt parameters are within the bounds of maxScryptN, maxScryptR,
+// maxScryptP and maxScryptMemory
+func checkScryptParams(n, r, p int) error {
+	switch {
+	case n < 2 || n > maxScryptN || n&(n-1) != 0:
+		return fmt.Errorf("scrypt N must be a power of 2 between 2 and %d, got %d", maxScryptN, n)
+	case r < 1 || r > maxScryptR:
+		return fmt.Errorf("scrypt r must be between 1 and %d, got %d", maxScryptR, r)
+	case p < 1 || p > maxScryptP:
+		return fmt.Errorf("scrypt p must be between 1 and %d, got %d", maxScryptP, p)
+	case 128*n*r > maxScryptMemory:
+		return fmt.Errorf("scrypt parameters N=%d and r=%d require more than %d bytes of memory", n, r, maxScryptMemory)
+	}
+
+	return nil
+}
+
+// seal encrypts the plaintext with AES-GCM using a random nonce
+func seal(key []byte, plaintext []byte, additionalData []byte) ([]byte, []byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return nonce, aead.Seal(nil, nonce, plaintext, additionalData), nil
+}
+
+func open(key []byte, nonce []byte, ciphertext []byte, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("nonce must be %d bytes", aead.NonceSize())
+	}
+
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// writeFileAtomic writes to a temporary file within the same directory and renames it into place
+// so that readers never observe a partially written file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,350 @@
+package crypto_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/crypto"
+	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/crypto/ecdh"
+)
+
+// cheap scrypt parameters so that tests run quickly
+var testScryptParams = crypto.ScryptParams(1<<10, 8, 1)
+
+func TestFileKeyManager_Sign(t *testing.T) {
+	keyManager, err := crypto.NewFileKeyManager(t.TempDir(), []byte("hunter2"), testScryptParams)
+	assert.NoError(t, err)
+
+	keyID, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	publicKey, err := keyManager.GetPublicKey(keyID)
+	assert.NoError(t, err)
+	assert.Equal(t, "", publicKey.D)
+
+	thumbprint, err := publicKey.ComputeThumbprint()
+	assert.NoError(t, err)
+	assert.Equal(t, keyID, thumbprint)
+
+	payload := []byte("hello world")
+	signature, err := keyManager.Sign(keyID, payload)
+	assert.NoError(t, err)
+
+	legit, err := dsa.Verify(payload, signature, publicKey)
+	assert.NoError(t, err)
+	assert.True(t, legit)
+}
+
+func TestFileKeyManager_SignAlgorithms(t *testing.T) {
+	keyManager, err := crypto.NewFileKeyManager(t.TempDir(), []byte("hunter2"), testScryptParams)
+	assert.NoError(t, err)
+
+	algorithmIDs := []string{
+		dsa.AlgorithmIDSECP256K1,
+		dsa.AlgorithmIDSECP256R1,
+		dsa.AlgorithmIDSECP384R1,
+		dsa.AlgorithmIDSECP521R1,
+		dsa.AlgorithmIDED25519,
+	}
+
+	for _, algorithmID := range algorithmIDs {
+		keyID, err := keyManager.GeneratePrivateKey(algorithmID)
+		assert.NoError(t, err)
+
+		publicKey, err := keyManager.GetPublicKey(keyID)
+		assert.NoError(t, err)
+
+		payload := []byte("hello world")
+		signature, err := keyManager.Sign(keyID, payload)
+		assert.NoError(t, err, algorithmID)
+
+		legit, err := dsa.Verify(payload, signature, publicKey)
+		assert.NoError(t, err)
+		assert.True(t, legit, algorithmID)
+	}
+}
+
+func TestFileKeyManager_Persistence(t *testing.T) {
+	dir := t.TempDir()
+
+	keyManager, err := crypto.NewFileKeyManager(dir, []byte("hunter2"), testScryptParams)
+	assert.NoError(t, err)
+
+	keyID, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDSECP256K1)
+	assert.NoError(t, err)
+
+	exported, err := keyManager.ExportKey(keyID)
+	assert.NoError(t, err)
+
+	// private key material must not be written to disk in plaintext
+	bytes, err := os.ReadFile(filepath.Join(dir, keyID+".json"))
+	assert.NoError(t, err)
+	assert.False(t, strings.Contains(string(bytes), exported.D))
+
+	reopened, err := crypto.NewFileKeyManager(dir, []byte("hunter2"))
+	assert.NoError(t, err)
+
+	reExported, err := reopened.ExportKey(keyID)
+	assert.NoError(t, err)
+	assert.Equal(t, exported, reExported)
+
+	_, err = crypto.NewFileKeyManager(dir, []byte("wrong"))
+	assert.Error(t, err)
+}
+
+func TestFileKeyManager_LockUnlock(t *testing.T) {
+	keyManager, err := crypto.NewFileKeyManager(t.TempDir(), []byte("hunter2"), testScryptParams)
+	assert.NoError(t, err)
+
+	keyID, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDSECP256R1)
+	assert.NoError(t, err)
+
+	keyManager.Lock()
+	assert.True(t, keyManager.Locked())
+
+	_, err = keyManager.Sign(keyID, []byte("hello world"))
+	assert.IsError(t, err, crypto.ErrKeyManagerLocked)
+
+	_, err = keyManager.ExportKey(keyID)
+	assert.IsError(t, err, crypto.ErrKeyManagerLocked)
+
+	_, err = keyManager.GeneratePrivateKey(dsa.AlgorithmIDSECP256R1)
+	assert.IsError(t, err, crypto.ErrKeyManagerLocked)
+
+	// public keys remain available while locked
+	_, err = keyManager.GetPublicKey(keyID)
+	assert.NoError(t, err)
+
+	err = keyManager.Unlock([]byte("wrong"))
+	assert.Error(t, err)
+	assert.True(t, keyManager.Locked())
+
+	err = keyManager.Unlock([]byte("hunter2"))
+	assert.NoError(t, err)
+	assert.False(t, keyManager.Locked())
+
+	_, err = keyManager.Sign(keyID, []byte("hello world"))
+	assert.NoError(t, err)
+}
+
+func TestFileKeyManager_ImportExport(t *testing.T) {
+	privateKey, err := dsa.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	keyManager, err := crypto.NewFileKeyManager(t.TempDir(), []byte("hunter2"), testScryptParams)
+	assert.NoError(t, err)
+
+	keyID, err := keyManager.ImportKey(privateKey)
+	assert.NoError(t, err)
+
+	exported, err := keyManager.ExportKey(keyID)
+	assert.NoError(t, err)
+	assert.Equal(t, privateKey, exported)
+
+	_, err = keyManager.ImportKey(dsa.GetPublicKey(privateKey))
+	assert.Error(t, err)
+}
+
+func TestFileKeyManager_SharedSecret(t *testing.T) {
+	keyManager, err := crypto.NewFileKeyManager(t.TempDir(), []byte("hunter2"), testScryptParams)
+	assert.NoError(t, err)
+
+	keyID, err := keyManager.GeneratePrivateKey(ecdh.X25519AlgorithmID)
+	assert.NoError(t, err)
+
+	other, err := ecdh.GeneratePrivateKey(ecdh.X25519AlgorithmID)
+	assert.NoError(t, err)
+
+	publicKey, err := keyManager.GetPublicKey(keyID)
+	assert.NoError(t, err)
+
+	sharedSecret, err := keyManager.SharedSecret(keyID, ecdh.GetPublicKey(other))
+	assert.NoError(t, err)
+
+	expected, err := ecdh.SharedSecret(other, publicKey)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, sharedSecret)
+}
+
+func TestFileKeyManager_InvalidKeyID(t *testing.T) {
+	keyManager, err := crypto.NewFileKeyManager(t.TempDir(), []byte("hunter2"), testScryptParams)
+	assert.NoError(t, err)
+
+	_, err = keyManager.GetPublicKey("../keystore")
+	assert.Error(t, err)
+
+	_, err = keyManager.GetPublicKey("doesnotexist")
+	assert.Error(t, err)
+}
+
+func TestFileKeyManager_Concurrency(t *testing.T) {
+	keyManager, err := crypto.NewFileKeyManager(t.TempDir(), []byte("hunter2"), testScryptParams)
+	assert.NoError(t, err)
+
+	keyID, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+			assert.NoError(t, err)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			_, err := keyManager.Sign(keyID, []byte("hello world"))
+			assert.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestFileKeyManager_ListAndDeleteKeys(t *testing.T) {
+	dir := t.TempDir()
+
+	keyManager, err := crypto.NewFileKeyManager(dir, []byte("hunter2"), testScryptParams)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, keyManager.ListKeys())
+
+	keyID, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	// unrelated files within the directory are ignored
+	err = os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("hi"), 0o600)
+	assert.NoError(t, err)
+
+	keyManager.Lock()
+	assert.Equal(t, []string{keyID}, keyManager.ListKeys())
+
+	err = keyManager.DeleteKey(keyID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, keyManager.ListKeys())
+
+	err = keyManager.DeleteKey(keyID)
+	assert.Error(t, err)
+}
+
+func TestFileKeyManager_KeyMetadata(t *testing.T) {
+	dir := t.TempDir()
+
+	keyManager, err := crypto.NewFileKeyManager(dir, []byte("hunter2"), testScryptParams)
+	assert.NoError(t, err)
+
+	keyID, err := keyManager.GeneratePrivateKey(ecdh.X25519AlgorithmID)
+	assert.NoError(t, err)
+
+	err = keyManager.SetKeyTags(keyID, "keyAgreement")
+	assert.NoError(t, err)
+
+	reopened, err := crypto.NewFileKeyManager(dir, []byte("hunter2"))
+	assert.NoError(t, err)
+
+	metadata, err := reopened.KeyMetadata(keyID)
+	assert.NoError(t, err)
+	assert.Equal(t, ecdh.X25519AlgorithmID, metadata.AlgorithmID)
+	assert.Equal(t, []string{"keyAgreement"}, metadata.Tags)
+	assert.False(t, metadata.CreatedAt.IsZero())
+}
+
+func TestFileKeyManager_TamperedKeyFile(t *testing.T) {
+	dir := t.TempDir()
+
+	keyManager, err := crypto.NewFileKeyManager(dir, []byte("hunter2"), testScryptParams)
+	assert.NoError(t, err)
+
+	keyID, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	otherKeyID, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	// tags aren't authenticated so that they can be changed while locked
+	keyManager.Lock()
+	assert.NoError(t, keyManager.SetKeyTags(keyID, "assertionMethod"))
+	assert.NoError(t, keyManager.Unlock([]byte("hunter2")))
+
+	_, err = keyManager.Sign(keyID, []byte("hello world"))
+	assert.NoError(t, err)
+
+	path := filepath.Join(dir, keyID+".json")
+	original, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	tamper := func(modify func(keyFile map[string]any)) {
+		var keyFile map[string]any
+		assert.NoError(t, json.Unmarshal(original, &keyFile))
+		modify(keyFile)
+
+		bytes, err := json.Marshal(keyFile)
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(path, bytes, 0o600))
+	}
+
+	// swapping the public key is detected
+	otherKeyFile, err := os.ReadFile(filepath.Join(dir, otherKeyID+".json"))
+	assert.NoError(t, err)
+
+	var other map[string]any
+	assert.NoError(t, json.Unmarshal(otherKeyFile, &other))
+
+	tamper(func(keyFile map[string]any) { keyFile["publicKey"] = other["publicKey"] })
+
+	_, err = keyManager.GetPublicKey(keyID)
+	assert.Error(t, err)
+
+	_, err = keyManager.Sign(keyID, []byte("hello world"))
+	assert.Error(t, err)
+
+	// changing the metadata set on import is detected
+	tamper(func(keyFile map[string]any) {
+		keyFile["metadata"].(map[string]any)["algorithmId"] = dsa.AlgorithmIDSECP256K1
+	})
+
+	_, err = keyManager.Sign(keyID, []byte("hello world"))
+	assert.Error(t, err)
+
+	// tampered key files can still be deleted
+	assert.NoError(t, keyManager.DeleteKey(keyID))
+}
+
+func TestFileKeyManager_ScryptParamsBounds(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := crypto.NewFileKeyManager(dir, []byte("hunter2"), crypto.ScryptParams(1<<30, 8, 1))
+	assert.Error(t, err)
+
+	_, err = crypto.NewFileKeyManager(dir, []byte("hunter2"), testScryptParams)
+	assert.NoError(t, err)
+
+	// a tampered key store is rejected before deriving the encryption key
+	path := filepath.Join(dir, "keystore.json")
+	bytes, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	var keystore map[string]any
+	assert.NoError(t, json.Unmarshal(bytes, &keystore))
+
+	for _, params := range [][3]int{{1 << 30, 8, 1}, {1 << 10, 1 << 20, 1}, {1 << 10, 8, 1 << 20}, {1000, 8, 1}, {1 << 20, 32, 1}} {
+		keystore["n"], keystore["r"], keystore["p"] = params[0], params[1], params[2]
+
+		bytes, err := json.Marshal(keystore)
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(path, bytes, 0o600))
+
+		_, err = crypto.NewFileKeyManager(dir, []byte("hunter2"))
+		assert.Error(t, err, "params %v", params)
+	}
+}
@@ -0,0 +1,220 @@
+// Package hd implements hierarchical deterministic key derivation from a seed (e.g. one produced by
+// [github.com/tbd54566975/web5-go/crypto/bip39.NewSeed]).
+//
+// Ed25519 keys are derived as described in SLIP-0010 (https://github.com/satoshilabs/slips/blob/master/slip-0010.md)
+// which only supports hardened derivation. secp256k1 keys are derived as described in BIP-32
+// (https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki).
+//
+// [KeyManager] builds on top of this to provide a [github.com/tbd54566975/web5-go/crypto.KeyManager]
+// whose keys can be recreated from a seed, which in turn makes it possible to recreate a DID from a
+// recovery phrase.
+package hd
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	_secp256k1 "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/crypto/dsa/ecdsa"
+	"github.com/tbd54566975/web5-go/crypto/dsa/eddsa"
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+// HardenedOffset is added to a child index to indicate hardened derivation
+const HardenedOffset uint32 = 0x80000000
+
+// seed modifiers as defined in SLIP-0010 and BIP-32
+const (
+	ed25519SeedModifier   = "ed25519 seed"
+	secp256k1SeedModifier = "Bitcoin seed"
+)
+
+// ExtendedKey is a private key along with the chain code needed to derive its children
+type ExtendedKey struct {
+	algorithmID string
+	key         []byte
+	chainCode   []byte
+}
+
+// NewMasterKey derives the master key for the given algorithm from the given seed. Supported
+// algorithms are [dsa.AlgorithmIDED25519] and [dsa.AlgorithmIDSECP256K1]
+func NewMasterKey(algorithmID string, seed []byte) (ExtendedKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return ExtendedKey{}, fmt.Errorf("seed must be between 16 and 64 bytes, got %d", len(seed))
+	}
+
+	var modifier string
+	switch algorithmID {
+	case dsa.AlgorithmIDED25519:
+		modifier = ed25519SeedModifier
+	case dsa.AlgorithmIDSECP256K1:
+		modifier = secp256k1SeedModifier
+	default:
+		return ExtendedKey{}, fmt.Errorf("unsupported algorithm: %s", algorithmID)
+	}
+
+	data := seed
+	for {
+		il, ir := hmacSHA512([]byte(modifier), data)
+		if algorithmID == dsa.AlgorithmIDED25519 || isValidSECP256K1Scalar(il) {
+			return ExtendedKey{algorithmID: algorithmID, key: il, chainCode: ir}, nil
+		}
+
+		// SLIP-0010: retry with the previous output if the key is invalid
+		data = append(il, ir...)
+	}
+}
+
+// Derive derives the key at the given path (e.g. m/44'/0'/0'/0'/0') relative to the master key.
+// Hardened indices are denoted with either ' or h. Ed25519 only supports hardened indices.
+func Derive(algorithmID string, seed []byte, path string) (ExtendedKey, error) {
+	indices, err := ParsePath(path)
+	if err != nil {
+		return ExtendedKey{}, err
+	}
+
+	key, err := NewMasterKey(algorithmID, seed)
+	if err != nil {
+		return ExtendedKey{}, err
+	}
+
+	for _, index := range indices {
+		key, err = key.Child(index)
+		if err != nil {
+			return ExtendedKey{}, fmt.Errorf("failed to derive %s: %w", path, err)
+		}
+	}
+
+	return key, nil
+}
+
+// Child derives the child key at the given index. Add [HardenedOffset] to the index for hardened derivation.
+func (k ExtendedKey) Child(index uint32) (ExtendedKey, error) {
+	switch k.algorithmID {
+	case dsa.AlgorithmIDED25519:
+		return k.ed25519Child(index)
+	case dsa.AlgorithmIDSECP256K1:
+		return k.secp256k1Child(index)
+	default:
+		return ExtendedKey{}, fmt.Errorf("unsupported algorithm: %s", k.algorithmID)
+	}
+}
+
+// PrivateKey returns the private key as a JWK
+func (k ExtendedKey) PrivateKey() (jwk.JWK, error) {
+	switch k.algorithmID {
+	case dsa.AlgorithmIDED25519:
+		return eddsa.BytesToPrivateKey(k.algorithmID, k.key)
+	case dsa.AlgorithmIDSECP256K1:
+		return ecdsa.BytesToPrivateKey(k.algorithmID, k.key)
+	default:
+		return jwk.JWK{}, fmt.Errorf("unsupported algorithm: %s", k.algorithmID)
+	}
+}
+
+// Key returns a copy of the raw 32 byte private key
+func (k ExtendedKey) Key() []byte {
+	return append([]byte(nil), k.key...)
+}
+
+// ChainCode returns a copy of the 32 byte chain code
+func (k ExtendedKey) ChainCode() []byte {
+	return append([]byte(nil), k.chainCode...)
+}
+
+func (k ExtendedKey) ed25519Child(index uint32) (ExtendedKey, error) {
+	if index < HardenedOffset {
+		return ExtendedKey{}, errors.New("ed25519 only supports hardened derivation")
+	}
+
+	il, ir := hmacSHA512(k.chainCode, hardenedData(k.key, index))
+
+	return ExtendedKey{algorithmID: k.algorithmID, key: il, chainCode: ir}, nil
+}
+
+func (k ExtendedKey) secp256k1Child(index uint32) (ExtendedKey, error) {
+	var parent _secp256k1.ModNScalar
+	parent.SetByteSlice(k.key)
+
+	var data []byte
+	if index >= HardenedOffset {
+		data = hardenedData(k.key, index)
+	} else {
+		publicKey := _secp256k1.NewPrivateKey(&parent).PubKey().SerializeCompressed()
+		data = binary.BigEndian.AppendUint32(publicKey, index)
+	}
+
+	for {
+		il, ir := hmacSHA512(k.chainCode, data)
+
+		var child _secp256k1.ModNScalar
+		overflow := child.SetByteSlice(il)
+		if !overflow {
+			child.Add(&parent)
+			if !child.IsZero() {
+				key := child.Bytes()
+				return ExtendedKey{algorithmID: k.algorithmID, key: key[:], chainCode: ir}, nil
+			}
+		}
+
+		// SLIP-0010: retry with 0x01 || IR || ser32(i) if the resulting key is invalid
+		data = binary.BigEndian.AppendUint32(append([]byte{0x01}, ir...), index)
+	}
+}
+
+// ParsePath parses a derivation path such as m/44'/0'/0'/0'/0' into child indices. Hardened
+// indices (denoted with either ' or h) have [HardenedOffset] added to them.
+func ParsePath(path string) ([]uint32, error) {
+	segments := strings.Split(path, "/")
+	if segments[0] != "m" {
+		return nil, fmt.Errorf("invalid derivation path %q: must start with m", path)
+	}
+
+	indices := make([]uint32, 0, len(segments)-1)
+	for _, segment := range segments[1:] {
+		hardened := strings.HasSuffix(segment, "'") || strings.HasSuffix(segment, "h")
+		if hardened {
+			segment = segment[:len(segment)-1]
+		}
+
+		index, err := strconv.ParseUint(segment, 10, 32)
+		if err != nil || uint32(index) >= HardenedOffset {
+			return nil, fmt.Errorf("invalid derivation path %q: invalid index %q", path, segment)
+		}
+
+		if hardened {
+			index += uint64(HardenedOffset)
+		}
+
+		indices = append(indices, uint32(index))
+	}
+
+	return indices, nil
+}
+
+// hardenedData returns 0x00 || ser256(k) || ser32(i)
+func hardenedData(key []byte, index uint32) []byte {
+	data := append([]byte{0x00}, key...)
+	return binary.BigEndian.AppendUint32(data, index)
+}
+
+func hmacSHA512(key []byte, data []byte) ([]byte, []byte) {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	return sum[:32], sum[32:]
+}
+
+func isValidSECP256K1Scalar(input []byte) bool {
+	var scalar _secp256k1.ModNScalar
+	overflow := scalar.SetByteSlice(input)
+
+	return !overflow && !scalar.IsZero()
+}
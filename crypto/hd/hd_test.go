@@ -0,0 +1,80 @@
+package hd_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/crypto/hd"
+)
+
+// test vector 1 from https://github.com/satoshilabs/slips/blob/master/slip-0010.md
+// and https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#test-vector-1
+var vectors = []struct {
+	algorithmID string
+	path        string
+	key         string
+	chainCode   string
+}{
+	{
+		algorithmID: dsa.AlgorithmIDED25519,
+		path:        "m",
+		key:         "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7",
+		chainCode:   "90046a93de5380a72b5e45010748567d5ea02bbf6522f979e05c0d8d8ca9fffb",
+	},
+	{
+		algorithmID: dsa.AlgorithmIDED25519,
+		path:        "m/0'",
+		key:         "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3",
+		chainCode:   "8b59aa11380b624e81507a27fedda59fea6d0b779a778918a2fd3590e16e9c69",
+	},
+	{
+		algorithmID: dsa.AlgorithmIDSECP256K1,
+		path:        "m",
+		key:         "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35",
+		chainCode:   "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508",
+	},
+	{
+		algorithmID: dsa.AlgorithmIDSECP256K1,
+		path:        "m/0h",
+		key:         "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+		chainCode:   "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141",
+	},
+	{
+		algorithmID: dsa.AlgorithmIDSECP256K1,
+		path:        "m/0h/1",
+		key:         "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368",
+		chainCode:   "2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19",
+	},
+}
+
+func TestDerive_Vectors(t *testing.T) {
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	assert.NoError(t, err)
+
+	for _, v := range vectors {
+		t.Run(v.algorithmID+" "+v.path, func(t *testing.T) {
+			key, err := hd.Derive(v.algorithmID, seed, v.path)
+			assert.NoError(t, err)
+			assert.Equal(t, v.key, hex.EncodeToString(key.Key()))
+			assert.Equal(t, v.chainCode, hex.EncodeToString(key.ChainCode()))
+
+			_, err = key.PrivateKey()
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestDerive_ED25519NonHardened(t *testing.T) {
+	seed := make([]byte, 32)
+	_, err := hd.Derive(dsa.AlgorithmIDED25519, seed, "m/0'/1")
+	assert.Error(t, err)
+}
+
+func TestParsePath_Invalid(t *testing.T) {
+	for _, path := range []string{"", "0'", "m/", "m/a", "m/2147483648"} {
+		_, err := hd.ParsePath(path)
+		assert.Error(t, err, path)
+	}
+}
@@ -0,0 +1,156 @@
+package hd
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/tbd54566975/web5-go/crypto"
+	"github.com/tbd54566975/web5-go/crypto/bip39"
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+// DefaultBasePath is the path below which [KeyManager] derives keys when no [BasePath] is provided
+const DefaultBasePath = "m/44'/0'/0'/0'"
+
+// KeyManager is an implementation of [crypto.KeyManager] whose keys are derived from a seed rather than
+// generated randomly. Every call to [KeyManager.GeneratePrivateKey] derives the next key for the
+// requested algorithm at <base path>/<index>', with indices starting at 0 and tracked separately per
+// algorithm. As a result, a new KeyManager created from the same seed hands out the same keys in the
+// same order, which means that calling e.g. [github.com/tbd54566975/web5-go/dids/didjwk.Create] with
+// the same options recreates the same DID.
+//
+// Keys at arbitrary paths can be derived with [KeyManager.DeriveKey]. Derived keys are held in memory
+// until [KeyManager.Close] is called. A KeyManager is safe for concurrent use by multiple goroutines.
+type KeyManager struct {
+	seedMu   sync.RWMutex
+	seed     []byte
+	basePath string
+	keys     *crypto.LocalKeyManager
+
+	mu      sync.Mutex
+	indices map[string]uint32
+}
+
+// options that [NewKeyManager] can take
+type keyManagerOpts struct {
+	basePath string
+}
+
+// KeyManagerOpt is a type that represents an option that can be passed to [NewKeyManager]
+type KeyManagerOpt func(opts *keyManagerOpts)
+
+// BasePath is an option that can be passed to [NewKeyManager]. It sets the path below which
+// [KeyManager.GeneratePrivateKey] derives keys. Defaults to [DefaultBasePath]
+func BasePath(path string) KeyManagerOpt {
+	return func(opts *keyManagerOpts) {
+		opts.basePath = path
+	}
+}
+
+// NewKeyManager returns a new [KeyManager] that derives keys from the given seed
+func NewKeyManager(seed []byte, opts ...KeyManagerOpt) (*KeyManager, error) {
+	o := keyManagerOpts{basePath: DefaultBasePath}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if _, err := ParsePath(o.basePath); err != nil {
+		return nil, err
+	}
+
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("seed must be between 16 and 64 bytes, got %d", len(seed))
+	}
+
+	return &KeyManager{
+		seed:     append([]byte(nil), seed...),
+		basePath: o.basePath,
+		keys:     crypto.NewLocalKeyManager(),
+		indices:  make(map[string]uint32),
+	}, nil
+}
+
+// NewKeyManagerFromMnemonic returns a new [KeyManager] that derives keys from the seed of the given
+// mnemonic and (possibly empty) passphrase. See [bip39.NewSeed]
+func NewKeyManagerFromMnemonic(mnemonic string, passphrase string, opts ...KeyManagerOpt) (*KeyManager, error) {
+	seed, err := bip39.NewSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyManager(seed, opts...)
+}
+
+// GeneratePrivateKey derives the next private key for the given algorithm, stores it in the key store
+// and returns the key id. Supported algorithms are [github.com/tbd54566975/web5-go/crypto/dsa.AlgorithmIDED25519]
+// and [github.com/tbd54566975/web5-go/crypto/dsa.AlgorithmIDSECP256K1]
+func (k *KeyManager) GeneratePrivateKey(algorithmID string) (string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	index := k.indices[algorithmID]
+	path := fmt.Sprintf("%s/%d'", k.basePath, index)
+
+	keyID, err := k.DeriveKey(algorithmID, path)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate private key: %w", err)
+	}
+
+	k.indices[algorithmID] = index + 1
+
+	return keyID, nil
+}
+
+// DeriveKey derives the private key for the given algorithm at the given path, stores it in the key
+// store and returns the key id
+func (k *KeyManager) DeriveKey(algorithmID string, path string) (string, error) {
+	k.seedMu.RLock()
+	defer k.seedMu.RUnlock()
+
+	if k.seed == nil {
+		return "", crypto.ErrKeyManagerClosed
+	}
+
+	extendedKey, err := Derive(algorithmID, k.seed, path)
+	if err != nil {
+		return "", err
+	}
+
+	privateKey, err := extendedKey.PrivateKey()
+	if err != nil {
+		return "", err
+	}
+
+	return k.keys.ImportKey(privateKey)
+}
+
+// GetPublicKey returns the public key for the given key id
+func (k *KeyManager) GetPublicKey(keyID string) (jwk.JWK, error) {
+	return k.keys.GetPublicKey(keyID)
+}
+
+// Sign signs the payload with the private key for the given key id
+func (k *KeyManager) Sign(keyID string, payload []byte) ([]byte, error) {
+	return k.keys.Sign(keyID, payload)
+}
+
+// ExportKey exports the key specific by the key ID from the [KeyManager]
+func (k *KeyManager) ExportKey(keyID string) (jwk.JWK, error) {
+	return k.keys.ExportKey(keyID)
+}
+
+// ListKeys returns the ids of all keys derived so far in sorted order
+func (k *KeyManager) ListKeys() []string {
+	return k.keys.ListKeys()
+}
+
+// Close wipes the seed and all derived keys. Every subsequent operation returns [crypto.ErrKeyManagerClosed]
+func (k *KeyManager) Close() error {
+	k.seedMu.Lock()
+	defer k.seedMu.Unlock()
+
+	clear(k.seed)
+	k.seed = nil
+
+	return k.keys.Close()
+}
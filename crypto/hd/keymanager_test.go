@@ -0,0 +1,146 @@
+package hd_test
+
+import (
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/crypto"
+	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/crypto/hd"
+	"github.com/tbd54566975/web5-go/dids/didcore"
+	"github.com/tbd54566975/web5-go/dids/didjwk"
+	"github.com/tbd54566975/web5-go/dids/didweb"
+)
+
+const mnemonic = "legal winner thank year wave sausage worth useful legal winner thank yellow"
+
+func TestKeyManager_Deterministic(t *testing.T) {
+	first, err := hd.NewKeyManagerFromMnemonic(mnemonic, "")
+	assert.NoError(t, err)
+
+	second, err := hd.NewKeyManagerFromMnemonic(mnemonic, "")
+	assert.NoError(t, err)
+
+	for _, algorithmID := range []string{dsa.AlgorithmIDED25519, dsa.AlgorithmIDSECP256K1} {
+		firstKeyID, err := first.GeneratePrivateKey(algorithmID)
+		assert.NoError(t, err)
+
+		secondKeyID, err := second.GeneratePrivateKey(algorithmID)
+		assert.NoError(t, err)
+
+		assert.Equal(t, firstKeyID, secondKeyID)
+
+		nextKeyID, err := first.GeneratePrivateKey(algorithmID)
+		assert.NoError(t, err)
+		assert.NotEqual(t, firstKeyID, nextKeyID)
+	}
+
+	assert.Equal(t, 4, len(first.ListKeys()))
+}
+
+func TestKeyManager_Passphrase(t *testing.T) {
+	withoutPassphrase, err := hd.NewKeyManagerFromMnemonic(mnemonic, "")
+	assert.NoError(t, err)
+
+	withPassphrase, err := hd.NewKeyManagerFromMnemonic(mnemonic, "passphrase")
+	assert.NoError(t, err)
+
+	first, err := withoutPassphrase.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	second, err := withPassphrase.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, first, second)
+}
+
+func TestKeyManager_UnsupportedAlgorithm(t *testing.T) {
+	keyManager, err := hd.NewKeyManagerFromMnemonic(mnemonic, "")
+	assert.NoError(t, err)
+
+	_, err = keyManager.GeneratePrivateKey(dsa.AlgorithmIDSECP256R1)
+	assert.Error(t, err)
+}
+
+func TestKeyManager_DeriveKey(t *testing.T) {
+	keyManager, err := hd.NewKeyManagerFromMnemonic(mnemonic, "")
+	assert.NoError(t, err)
+
+	generated, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	derived, err := keyManager.DeriveKey(dsa.AlgorithmIDED25519, hd.DefaultBasePath+"/0'")
+	assert.NoError(t, err)
+	assert.Equal(t, generated, derived)
+
+	payload := []byte("hello")
+	signature, err := keyManager.Sign(derived, payload)
+	assert.NoError(t, err)
+
+	publicKey, err := keyManager.GetPublicKey(derived)
+	assert.NoError(t, err)
+
+	ok, err := dsa.Verify(payload, signature, publicKey)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestKeyManager_Close(t *testing.T) {
+	keyManager, err := hd.NewKeyManagerFromMnemonic(mnemonic, "")
+	assert.NoError(t, err)
+
+	keyID, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	assert.NoError(t, keyManager.Close())
+
+	_, err = keyManager.Sign(keyID, []byte("hi"))
+	assert.IsError(t, err, crypto.ErrKeyManagerClosed)
+
+	_, err = keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.IsError(t, err, crypto.ErrKeyManagerClosed)
+}
+
+func TestNewKeyManager_InvalidBasePath(t *testing.T) {
+	_, err := hd.NewKeyManagerFromMnemonic(mnemonic, "", hd.BasePath("44'/0'"))
+	assert.Error(t, err)
+}
+
+func TestNewKeyManagerFromMnemonic_Invalid(t *testing.T) {
+	_, err := hd.NewKeyManagerFromMnemonic("legal winner thank year", "")
+	assert.Error(t, err)
+}
+
+func TestKeyManager_RecreateDIDJWK(t *testing.T) {
+	first, err := hd.NewKeyManagerFromMnemonic(mnemonic, "")
+	assert.NoError(t, err)
+
+	second, err := hd.NewKeyManagerFromMnemonic(mnemonic, "")
+	assert.NoError(t, err)
+
+	firstDID, err := didjwk.Create(didjwk.KeyManager(first))
+	assert.NoError(t, err)
+
+	secondDID, err := didjwk.Create(didjwk.KeyManager(second))
+	assert.NoError(t, err)
+
+	assert.Equal(t, firstDID.URI, secondDID.URI)
+}
+
+func TestKeyManager_RecreateDIDWeb(t *testing.T) {
+	create := func() string {
+		keyManager, err := hd.NewKeyManagerFromMnemonic(mnemonic, "")
+		assert.NoError(t, err)
+
+		bearerDID, err := didweb.Create(
+			"example.com",
+			didweb.KeyManager(keyManager),
+			didweb.PrivateKey(dsa.AlgorithmIDSECP256K1, didcore.PurposeAssertion),
+		)
+		assert.NoError(t, err)
+
+		return bearerDID.Document.VerificationMethod[len(bearerDID.Document.VerificationMethod)-1].PublicKeyJwk.X
+	}
+
+	assert.Equal(t, create(), create())
+}
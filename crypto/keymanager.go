@@ -1,12 +1,25 @@
 package crypto
 
 import (
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/crypto/ecdh"
 	"github.com/tbd54566975/web5-go/jwk"
 )
 
+// ErrKeyManagerClosed is returned by [LocalKeyManager] when it is used after [LocalKeyManager.Close] was called
+var ErrKeyManagerClosed = errors.New("key manager is closed")
+
+// ErrKeyNotExportable is returned by [LocalKeyManager.ExportKey] if the key manager was created with [NonExportable]
+var ErrKeyNotExportable = errors.New("key is not exportable")
+
 // KeyManager is an abstraction that can be leveraged to manage/use keys (create, sign etc) as desired per the given use case
 // examples of concrete implementations include: AWS KMS, Azure Key Vault, Google Cloud KMS, Hashicorp Vault etc
 type KeyManager interface {
@@ -30,78 +43,243 @@ type KeyImporter interface {
 	ImportKey(key jwk.JWK) (string, error)
 }
 
-// LocalKeyManager is an implementation of KeyManager that stores keys in memory
+// KeyDeriver is an abstraction that can be leveraged to implement types which are capable of
+// performing key agreement (e.g. ECDH) without exporting the private key
+type KeyDeriver interface {
+	// SharedSecret computes the raw shared secret between the private key for the given key id
+	// and the given public key
+	SharedSecret(keyID string, publicKey jwk.JWK) ([]byte, error)
+}
+
+// KeyLister is an abstraction that can be leveraged to implement types which are capable of
+// enumerating the keys they manage
+type KeyLister interface {
+	// ListKeys returns the ids of all keys in sorted order
+	ListKeys() []string
+}
+
+// KeyDeleter is an abstraction that can be leveraged to implement types which are capable of
+// permanently removing keys
+type KeyDeleter interface {
+	DeleteKey(keyID string) error
+}
+
+// KeyMetadata describes a managed key without exposing any key material
+type KeyMetadata struct {
+	// AlgorithmID is the algorithm the key is used with. See [github.com/tbd54566975/web5-go/crypto/dsa.AlgorithmID]
+	// and [github.com/tbd54566975/web5-go/crypto/ecdh.AlgorithmID]
+	AlgorithmID string `json:"algorithmId"`
+	// CreatedAt is the time at which the key was generated or imported
+	CreatedAt time.Time `json:"createdAt"`
+	// Tags are arbitrary labels (e.g. intended purposes such as "assertionMethod") attached to the key
+	Tags []string `json:"tags,omitempty"`
+}
+
+// KeyMetadataProvider is an abstraction that can be leveraged to implement types which keep track of
+// metadata about the keys they manage
+type KeyMetadataProvider interface {
+	KeyMetadata(keyID string) (KeyMetadata, error)
+}
+
+// KeyTagger is an abstraction that can be leveraged to implement types which allow labeling the keys they manage
+type KeyTagger interface {
+	// SetKeyTags replaces the tags of the key with the given key id
+	SetKeyTags(keyID string, tags ...string) error
+}
+
+// Unwrapper is an abstraction that can be leveraged to implement types which wrap another key manager,
+// e.g. [SigningMiddleware]
+type Unwrapper interface {
+	// Unwrap returns the wrapped key manager
+	Unwrap() KeyManager
+}
+
+// As returns the first key manager that implements T, e.g. [KeyExporter], out of the given key manager and
+// the key managers it wraps (see [Unwrapper]). Use it instead of a type assertion to check whether a key
+// manager that might be wrapped supports an optional capability
+func As[T any](keyManager KeyManager) (T, bool) {
+	for keyManager != nil {
+		if capability, ok := keyManager.(T); ok {
+			return capability, true
+		}
+
+		unwrapper, ok := keyManager.(Unwrapper)
+		if !ok {
+			break
+		}
+
+		keyManager = unwrapper.Unwrap()
+	}
+
+	var zero T
+
+	return zero, false
+}
+
+// LocalKeyManager is an implementation of KeyManager that stores keys in memory.
+//
+// Private keys are held as raw bytes rather than as base64 encoded [jwk.JWK] strings, which can't be
+// wiped. Signing and key agreement use the raw bytes directly (see [dsa.SignWithPrivateKeyBytes] and
+// [ecdh.SharedSecretWithPrivateKeyBytes]). A private key is only encoded into a [jwk.JWK] when it's
+// exported. [LocalKeyManager.DeleteKey] and [LocalKeyManager.Close] wipe the stored bytes. Keys can't be exported if the key manager was created
+// with [NonExportable].
+//
+// A LocalKeyManager is safe for concurrent use by multiple goroutines.
 type LocalKeyManager struct {
-	keys map[string]jwk.JWK
+	mu         sync.RWMutex
+	keys       map[string]localKey
+	closed     bool
+	exportable bool
+
+	// randMu serializes reads from rand so that concurrent callers sharing a deterministic
+	// reader don't interleave their reads
+	randMu sync.Mutex
+	rand   io.Reader
+}
+
+type localKey struct {
+	// key is the imported key without d
+	key jwk.JWK
+	// d is the decoded private key
+	d         []byte
+	publicKey jwk.JWK
+	metadata  KeyMetadata
+}
+
+// options that [NewLocalKeyManager] can take
+type localKeyManagerOpts struct {
+	rand          io.Reader
+	nonExportable bool
+}
+
+// LocalKeyManagerOpt is a type that represents an option that can be passed to [NewLocalKeyManager]
+type LocalKeyManagerOpt func(opts *localKeyManagerOpts)
+
+// EntropySource is an option that can be passed to [NewLocalKeyManager]. It sets the source of randomness
+// used by [LocalKeyManager.GeneratePrivateKey]. Providing a deterministic reader results in the same
+// sequence of keys every time, which makes this useful for reproducible test fixtures. Defaults to
+// crypto/rand when nil or not provided
+func EntropySource(rand io.Reader) LocalKeyManagerOpt {
+	return func(opts *localKeyManagerOpts) {
+		opts.rand = rand
+	}
+}
+
+// NonExportable is an option that can be passed to [NewLocalKeyManager]. It prevents private keys from
+// ever leaving the key manager: [LocalKeyManager.ExportKey] returns [ErrKeyNotExportable] and, as a
+// result, [github.com/tbd54566975/web5-go/dids/did.BearerDID.ToPortableDID] fails instead of including
+// private keys.
+func NonExportable() LocalKeyManagerOpt {
+	return func(opts *localKeyManagerOpts) {
+		opts.nonExportable = true
+	}
 }
 
 // NewLocalKeyManager returns a new instance of InMemoryKeyManager
-func NewLocalKeyManager() *LocalKeyManager {
+func NewLocalKeyManager(opts ...LocalKeyManagerOpt) *LocalKeyManager {
+	o := localKeyManagerOpts{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	return &LocalKeyManager{
-		keys: make(map[string]jwk.JWK),
+		keys:       make(map[string]localKey),
+		exportable: !o.nonExportable,
+		rand:       o.rand,
 	}
 }
 
 // GeneratePrivateKey generates a new private key using the algorithm provided,
 // stores it in the key store and returns the key id
 // Supported algorithms are available in [github.com/tbd54566975/web5-go/crypto/dsa.AlgorithmID]
+// and [github.com/tbd54566975/web5-go/crypto/ecdh.AlgorithmID]
 func (k *LocalKeyManager) GeneratePrivateKey(algorithmID string) (string, error) {
-	var keyAlias string
-
-	key, err := dsa.GeneratePrivateKey(algorithmID)
+	k.randMu.Lock()
+	key, err := generatePrivateKey(algorithmID, k.rand)
+	k.randMu.Unlock()
 	if err != nil {
 		return "", fmt.Errorf("failed to generate private key: %w", err)
 	}
 
-	keyAlias, err = key.ComputeThumbprint()
-	if err != nil {
-		return "", fmt.Errorf("failed to compute key alias: %w", err)
-	}
-
-	k.keys[keyAlias] = key
-
-	return keyAlias, nil
+	return k.ImportKey(key)
 }
 
 // GetPublicKey returns the public key for the given key id
 func (k *LocalKeyManager) GetPublicKey(keyID string) (jwk.JWK, error) {
-	key, err := k.getPrivateJWK(keyID)
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, err := k.getKey(keyID)
 	if err != nil {
 		return jwk.JWK{}, err
 	}
 
-	return dsa.GetPublicKey(key), nil
-
+	return key.publicKey, nil
 }
 
 // Sign signs the payload with the private key for the given key id
 func (k *LocalKeyManager) Sign(keyID string, payload []byte) ([]byte, error) {
-	key, err := k.getPrivateJWK(keyID)
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, err := k.getKey(keyID)
 	if err != nil {
 		return nil, err
 	}
 
-	return dsa.Sign(payload, key)
+	return dsa.SignWithPrivateKeyBytes(payload, key.key, key.d)
 }
 
-func (k *LocalKeyManager) getPrivateJWK(keyID string) (jwk.JWK, error) {
-	key, ok := k.keys[keyID]
+// SharedSecret computes the raw ECDH shared secret between the private key for the given key id
+// and the given public key
+func (k *LocalKeyManager) SharedSecret(keyID string, publicKey jwk.JWK) ([]byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, err := k.getKey(keyID)
+	if err != nil {
+		return nil, err
+	}
 
+	return ecdh.SharedSecretWithPrivateKeyBytes(key.key, key.d, publicKey)
+}
+
+// getKey returns the key with the given key id. The caller must hold k.mu. Keys are used while holding
+// the lock so that [LocalKeyManager.Close] and [LocalKeyManager.DeleteKey] can't wipe them mid-operation
+func (k *LocalKeyManager) getKey(keyID string) (localKey, error) {
+	if k.closed {
+		return localKey{}, ErrKeyManagerClosed
+	}
+
+	key, ok := k.keys[keyID]
 	if !ok {
-		return jwk.JWK{}, fmt.Errorf("key with alias %s not found", keyID)
+		return localKey{}, fmt.Errorf("key with alias %s not found", keyID)
 	}
 
 	return key, nil
 }
 
-// ExportKey exports the key specific by the key ID from the [LocalKeyManager]
+// ExportKey exports the key specific by the key ID from the [LocalKeyManager]. Returns
+// [ErrKeyNotExportable] if the key manager was created with [NonExportable]
 func (k *LocalKeyManager) ExportKey(keyID string) (jwk.JWK, error) {
-	key, err := k.getPrivateJWK(keyID)
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, err := k.getKey(keyID)
 	if err != nil {
 		return jwk.JWK{}, err
 	}
 
-	return key, nil
+	if !k.exportable {
+		return jwk.JWK{}, ErrKeyNotExportable
+	}
+
+	privateKey := key.key
+	if key.d != nil {
+		privateKey.D = base64.RawURLEncoding.EncodeToString(key.d)
+	}
+
+	return privateKey, nil
 }
 
 // ImportKey imports the key into the [LocalKeyManager] and returns the key alias
@@ -111,7 +289,146 @@ func (k *LocalKeyManager) ImportKey(key jwk.JWK) (string, error) {
 		return "", fmt.Errorf("failed to compute key alias: %w", err)
 	}
 
-	k.keys[keyAlias] = key
+	var d []byte
+	if key.D != "" {
+		d, err = base64.RawURLEncoding.DecodeString(key.D)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode d: %w", err)
+		}
+	}
+
+	publicKey := getPublicKey(key)
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.closed {
+		clear(d)
+		return "", ErrKeyManagerClosed
+	}
+
+	// re-importing a key keeps its original metadata
+	metadata := KeyMetadata{AlgorithmID: keyAlgorithmID(key), CreatedAt: time.Now()}
+	if existing, ok := k.keys[keyAlias]; ok {
+		metadata = existing.metadata
+		clear(existing.d)
+	}
+
+	stripped := key
+	stripped.D = ""
+
+	k.keys[keyAlias] = localKey{key: stripped, d: d, publicKey: publicKey, metadata: metadata}
 
 	return keyAlias, nil
 }
+
+// ListKeys returns the ids of all keys stored in the [LocalKeyManager] in sorted order
+func (k *LocalKeyManager) ListKeys() []string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	keyIDs := make([]string, 0, len(k.keys))
+	for keyID := range k.keys {
+		keyIDs = append(keyIDs, keyID)
+	}
+
+	sort.Strings(keyIDs)
+
+	return keyIDs
+}
+
+// DeleteKey removes the key with the given key id from the [LocalKeyManager] and wipes its private key
+func (k *LocalKeyManager) DeleteKey(keyID string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	key, err := k.getKey(keyID)
+	if err != nil {
+		return err
+	}
+
+	clear(key.d)
+	delete(k.keys, keyID)
+
+	return nil
+}
+
+// Close wipes all private keys held by the [LocalKeyManager]. Every subsequent operation returns
+// [ErrKeyManagerClosed]. Calling Close more than once has no effect.
+func (k *LocalKeyManager) Close() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for _, key := range k.keys {
+		clear(key.d)
+	}
+
+	clear(k.keys)
+	k.closed = true
+
+	return nil
+}
+
+// KeyMetadata returns the metadata of the key with the given key id
+func (k *LocalKeyManager) KeyMetadata(keyID string) (KeyMetadata, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, err := k.getKey(keyID)
+	if err != nil {
+		return KeyMetadata{}, err
+	}
+
+	metadata := key.metadata
+	metadata.Tags = append([]string(nil), metadata.Tags...)
+
+	return metadata, nil
+}
+
+// SetKeyTags replaces the tags of the key with the given key id
+func (k *LocalKeyManager) SetKeyTags(keyID string, tags ...string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	key, err := k.getKey(keyID)
+	if err != nil {
+		return err
+	}
+
+	key.metadata.Tags = append([]string(nil), tags...)
+	k.keys[keyID] = key
+
+	return nil
+}
+
+// generatePrivateKey generates a signing key using dsa, or a key agreement key using ecdh
+// for algorithms that are exclusively used for key agreement (e.g. X25519). A nil rand uses crypto/rand
+func generatePrivateKey(algorithmID string, rand io.Reader) (jwk.JWK, error) {
+	if algorithmID == ecdh.X25519AlgorithmID {
+		return ecdh.GeneratePrivateKey(algorithmID, ecdh.EntropySource(rand))
+	}
+
+	return dsa.GeneratePrivateKey(algorithmID, dsa.EntropySource(rand))
+}
+
+func getPublicKey(privateKey jwk.JWK) jwk.JWK {
+	if privateKey.CRV == ecdh.X25519JWACurve {
+		return ecdh.GetPublicKey(privateKey)
+	}
+
+	return dsa.GetPublicKey(privateKey)
+}
+
+// keyAlgorithmID returns the algorithm ID of the given key or an empty string if the key's
+// algorithm is unknown
+func keyAlgorithmID(key jwk.JWK) string {
+	if algorithmID, err := dsa.AlgorithmID(&key); err == nil {
+		return algorithmID
+	}
+
+	if algorithmID, err := ecdh.AlgorithmID(&key); err == nil {
+		return algorithmID
+	}
+
+	return ""
+}
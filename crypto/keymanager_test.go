@@ -1,11 +1,14 @@
 package crypto_test
 
 import (
+	"bytes"
+	"sync"
 	"testing"
 
 	"github.com/alecthomas/assert/v2"
 	"github.com/tbd54566975/web5-go/crypto"
 	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/crypto/ecdh"
 )
 
 func TestGeneratePrivateKey(t *testing.T) {
@@ -48,3 +51,209 @@ func TestSign(t *testing.T) {
 
 	assert.True(t, signature != nil, "signature is nil")
 }
+
+func TestSharedSecret(t *testing.T) {
+	keyManager := crypto.NewLocalKeyManager()
+
+	aliceKeyID, err := keyManager.GeneratePrivateKey(ecdh.X25519AlgorithmID)
+	assert.NoError(t, err)
+
+	bobKeyID, err := keyManager.GeneratePrivateKey(ecdh.X25519AlgorithmID)
+	assert.NoError(t, err)
+
+	alicePublicKey, err := keyManager.GetPublicKey(aliceKeyID)
+	assert.NoError(t, err)
+	assert.Equal(t, "", alicePublicKey.D)
+
+	bobPublicKey, err := keyManager.GetPublicKey(bobKeyID)
+	assert.NoError(t, err)
+
+	aliceSecret, err := keyManager.SharedSecret(aliceKeyID, bobPublicKey)
+	assert.NoError(t, err)
+
+	bobSecret, err := keyManager.SharedSecret(bobKeyID, alicePublicKey)
+	assert.NoError(t, err)
+
+	assert.Equal(t, aliceSecret, bobSecret)
+
+	_, err = keyManager.Sign(aliceKeyID, []byte("hello world"))
+	assert.Error(t, err)
+}
+
+func TestListKeys(t *testing.T) {
+	keyManager := crypto.NewLocalKeyManager()
+	assert.Equal(t, []string{}, keyManager.ListKeys())
+
+	keyID1, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	keyID2, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDSECP256K1)
+	assert.NoError(t, err)
+
+	keyIDs := keyManager.ListKeys()
+	assert.Equal(t, 2, len(keyIDs))
+	assert.True(t, keyIDs[0] < keyIDs[1], "expected key ids to be sorted")
+	assert.True(t, (keyIDs[0] == keyID1 && keyIDs[1] == keyID2) || (keyIDs[0] == keyID2 && keyIDs[1] == keyID1))
+}
+
+func TestDeleteKey(t *testing.T) {
+	keyManager := crypto.NewLocalKeyManager()
+
+	keyID, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	err = keyManager.DeleteKey(keyID)
+	assert.NoError(t, err)
+
+	_, err = keyManager.GetPublicKey(keyID)
+	assert.Error(t, err)
+
+	err = keyManager.DeleteKey(keyID)
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{}, keyManager.ListKeys())
+}
+
+func TestKeyMetadata(t *testing.T) {
+	keyManager := crypto.NewLocalKeyManager()
+
+	keyID, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDSECP256R1)
+	assert.NoError(t, err)
+
+	metadata, err := keyManager.KeyMetadata(keyID)
+	assert.NoError(t, err)
+	assert.Equal(t, dsa.AlgorithmIDSECP256R1, metadata.AlgorithmID)
+	assert.False(t, metadata.CreatedAt.IsZero())
+	assert.Equal(t, 0, len(metadata.Tags))
+
+	err = keyManager.SetKeyTags(keyID, "assertionMethod", "authentication")
+	assert.NoError(t, err)
+
+	metadata, err = keyManager.KeyMetadata(keyID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"assertionMethod", "authentication"}, metadata.Tags)
+
+	// re-importing an existing key keeps its metadata
+	key, err := keyManager.ExportKey(keyID)
+	assert.NoError(t, err)
+
+	_, err = keyManager.ImportKey(key)
+	assert.NoError(t, err)
+
+	reimported, err := keyManager.KeyMetadata(keyID)
+	assert.NoError(t, err)
+	assert.Equal(t, metadata, reimported)
+
+	_, err = keyManager.KeyMetadata("doesnotexist")
+	assert.Error(t, err)
+
+	err = keyManager.SetKeyTags("doesnotexist", "assertionMethod")
+	assert.Error(t, err)
+}
+
+func TestLocalKeyManager_Concurrency(t *testing.T) {
+	keyManager := crypto.NewLocalKeyManager()
+
+	keyID, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(3)
+
+		go func() {
+			defer wg.Done()
+
+			newKeyID, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+			assert.NoError(t, err)
+
+			err = keyManager.DeleteKey(newKeyID)
+			assert.NoError(t, err)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			_, err := keyManager.Sign(keyID, []byte("hello world"))
+			assert.NoError(t, err)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			_ = keyManager.ListKeys()
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, []string{keyID}, keyManager.ListKeys())
+}
+
+func TestLocalKeyManager_EntropySource(t *testing.T) {
+	entropy := bytes.Repeat([]byte{0x07, 0x42}, 256)
+
+	first := crypto.NewLocalKeyManager(crypto.EntropySource(bytes.NewReader(entropy)))
+	second := crypto.NewLocalKeyManager(crypto.EntropySource(bytes.NewReader(entropy)))
+
+	for _, algorithmID := range []string{dsa.AlgorithmIDED25519, dsa.AlgorithmIDSECP256K1, ecdh.X25519AlgorithmID} {
+		firstKeyID, err := first.GeneratePrivateKey(algorithmID)
+		assert.NoError(t, err)
+
+		secondKeyID, err := second.GeneratePrivateKey(algorithmID)
+		assert.NoError(t, err)
+
+		assert.Equal(t, firstKeyID, secondKeyID)
+	}
+}
+
+func TestLocalKeyManager_ExportKey(t *testing.T) {
+	privateKey, err := dsa.GeneratePrivateKey(dsa.AlgorithmIDSECP256R1)
+	assert.NoError(t, err)
+
+	keyManager := crypto.NewLocalKeyManager()
+	keyID, err := keyManager.ImportKey(privateKey)
+	assert.NoError(t, err)
+
+	exported, err := keyManager.ExportKey(keyID)
+	assert.NoError(t, err)
+	assert.Equal(t, privateKey, exported)
+
+	publicKey, err := keyManager.GetPublicKey(keyID)
+	assert.NoError(t, err)
+	assert.Equal(t, "", publicKey.D)
+}
+
+func TestLocalKeyManager_NonExportable(t *testing.T) {
+	keyManager := crypto.NewLocalKeyManager(crypto.NonExportable())
+
+	keyID, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	_, err = keyManager.ExportKey(keyID)
+	assert.IsError(t, err, crypto.ErrKeyNotExportable)
+
+	_, err = keyManager.Sign(keyID, []byte("hi"))
+	assert.NoError(t, err)
+}
+
+func TestLocalKeyManager_Close(t *testing.T) {
+	keyManager := crypto.NewLocalKeyManager()
+
+	keyID, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	assert.NoError(t, keyManager.Close())
+	assert.NoError(t, keyManager.Close())
+
+	_, err = keyManager.Sign(keyID, []byte("hi"))
+	assert.IsError(t, err, crypto.ErrKeyManagerClosed)
+
+	_, err = keyManager.ExportKey(keyID)
+	assert.IsError(t, err, crypto.ErrKeyManagerClosed)
+
+	_, err = keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.IsError(t, err, crypto.ErrKeyManagerClosed)
+
+	assert.Equal(t, 0, len(keyManager.ListKeys()))
+}
@@ -0,0 +1,199 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+// ErrSigningDenied is returned by [SigningMiddleware.Sign] when a [SignPolicy] rejects a signing operation
+var ErrSigningDenied = errors.New("signing denied by policy")
+
+// SignRequest describes a signing operation. It is passed to every [SignPolicy] and [SignAuditor]
+type SignRequest struct {
+	// KeyID is the id of the key that is used to sign
+	KeyID string
+	// Payload is the payload to be signed
+	Payload []byte
+	// PayloadDigest is the hex encoded SHA-256 digest of the payload. Unlike the payload itself, it is
+	// safe to write to audit logs
+	PayloadDigest string
+	// Caller identifies who requested the signature. See [SigningMiddleware.WithCaller]
+	Caller string
+	// Time is the time at which the signing operation was requested
+	Time time.Time
+}
+
+// SignPolicy is checked before a payload is signed. Returning an error prevents the payload from being signed
+type SignPolicy func(req SignRequest) error
+
+// SignAuditor is called after every signing attempt, including attempts that were rejected by a [SignPolicy],
+// with the resulting signature or error
+type SignAuditor func(req SignRequest, signature []byte, err error)
+
+// SigningMiddleware is a [KeyManager] that wraps another [KeyManager] and runs policy checks before and
+// audit callbacks after every signing operation. It can be used anywhere a [KeyManager] is accepted, e.g.
+// [github.com/tbd54566975/web5-go/dids/did.BearerDID], in which case it applies to everything signed
+// with the DID (JWS, JWT, VC-JWT, DID DHT publishing etc.). Middlewares can be stacked by wrapping one
+// in another.
+//
+// A SigningMiddleware only implements [KeyManager], [Unwrapper] and [io.Closer], so that it never claims optional
+// capabilities such as [KeyExporter] that the wrapped key manager lacks. Use [As] to find the capabilities of the
+// wrapped key manager. A SigningMiddleware is safe for concurrent use by multiple goroutines if its hooks are.
+type SigningMiddleware struct {
+	keyManager KeyManager
+	policies   []SignPolicy
+	auditors   []SignAuditor
+	caller     string
+}
+
+// options that [NewSigningMiddleware] can take
+type signingMiddlewareOpts struct {
+	policies []SignPolicy
+	auditors []SignAuditor
+	caller   string
+}
+
+// SigningMiddlewareOpt is a type that represents an option that can be passed to [NewSigningMiddleware]
+type SigningMiddlewareOpt func(opts *signingMiddlewareOpts)
+
+// PreSign is an option that can be passed to [NewSigningMiddleware] to add a [SignPolicy]. Policies are
+// checked in the order they were added. Can be passed multiple times
+func PreSign(policy SignPolicy) SigningMiddlewareOpt {
+	return func(opts *signingMiddlewareOpts) {
+		opts.policies = append(opts.policies, policy)
+	}
+}
+
+// PostSign is an option that can be passed to [NewSigningMiddleware] to add a [SignAuditor]. Auditors are
+// called in the order they were added. Can be passed multiple times
+func PostSign(auditor SignAuditor) SigningMiddlewareOpt {
+	return func(opts *signingMiddlewareOpts) {
+		opts.auditors = append(opts.auditors, auditor)
+	}
+}
+
+// Caller is an option that can be passed to [NewSigningMiddleware] to set the caller reported in every
+// [SignRequest]. See [SigningMiddleware.WithCaller] to derive middlewares for other callers
+func Caller(caller string) SigningMiddlewareOpt {
+	return func(opts *signingMiddlewareOpts) {
+		opts.caller = caller
+	}
+}
+
+// NewSigningMiddleware wraps the given key manager
+func NewSigningMiddleware(keyManager KeyManager, opts ...SigningMiddlewareOpt) *SigningMiddleware {
+	o := signingMiddlewareOpts{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &SigningMiddleware{
+		keyManager: keyManager,
+		policies:   o.policies,
+		auditors:   o.auditors,
+		caller:     o.caller,
+	}
+}
+
+// WithCaller returns a copy of the middleware that reports the given caller in every [SignRequest].
+// The copy shares the wrapped key manager, policies and auditors with the original.
+func (m *SigningMiddleware) WithCaller(caller string) *SigningMiddleware {
+	clone := *m
+	clone.caller = caller
+
+	return &clone
+}
+
+// Unwrap returns the wrapped key manager. See [As]
+func (m *SigningMiddleware) Unwrap() KeyManager {
+	return m.keyManager
+}
+
+// GeneratePrivateKey generates a new private key using the wrapped key manager
+func (m *SigningMiddleware) GeneratePrivateKey(algorithmID string) (string, error) {
+	return m.keyManager.GeneratePrivateKey(algorithmID)
+}
+
+// GetPublicKey returns the public key for the given key id from the wrapped key manager
+func (m *SigningMiddleware) GetPublicKey(keyID string) (jwk.JWK, error) {
+	return m.keyManager.GetPublicKey(keyID)
+}
+
+// Sign checks all policies, signs the payload with the wrapped key manager if none of them reject the
+// operation and finally reports the outcome to all auditors
+func (m *SigningMiddleware) Sign(keyID string, payload []byte) ([]byte, error) {
+	digest := sha256.Sum256(payload)
+	req := SignRequest{
+		KeyID:         keyID,
+		Payload:       payload,
+		PayloadDigest: hex.EncodeToString(digest[:]),
+		Caller:        m.caller,
+		Time:          time.Now(),
+	}
+
+	signature, err := m.sign(req)
+
+	for _, auditor := range m.auditors {
+		auditor(req, signature, err)
+	}
+
+	return signature, err
+}
+
+func (m *SigningMiddleware) sign(req SignRequest) ([]byte, error) {
+	for _, policy := range m.policies {
+		if err := policy(req); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrSigningDenied, err)
+		}
+	}
+
+	return m.keyManager.Sign(req.KeyID, req.Payload)
+}
+
+// Close closes the wrapped key manager, if it implements [io.Closer]
+func (m *SigningMiddleware) Close() error {
+	closer, ok := m.keyManager.(io.Closer)
+	if !ok {
+		return nil
+	}
+
+	return closer.Close()
+}
+
+// RateLimit returns a [SignPolicy] that allows at most limit signing operations per key within every
+// interval. Operations exceeding the limit are rejected until the interval has passed.
+func RateLimit(limit int, interval time.Duration) SignPolicy {
+	type window struct {
+		start time.Time
+		count int
+	}
+
+	var mu sync.Mutex
+	windows := make(map[string]*window)
+
+	return func(req SignRequest) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		w, ok := windows[req.KeyID]
+		if !ok || req.Time.Sub(w.start) >= interval {
+			w = &window{start: req.Time}
+			windows[req.KeyID] = w
+		}
+
+		if w.count >= limit {
+			return fmt.Errorf("rate limit of %d signatures per %s exceeded for key %s", limit, interval, req.KeyID)
+		}
+
+		w.count++
+
+		return nil
+	}
+}
@@ -0,0 +1,139 @@
+package crypto_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/crypto"
+	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/dids/didjwk"
+	"github.com/tbd54566975/web5-go/jws"
+)
+
+func TestSigningMiddleware(t *testing.T) {
+	var audited []crypto.SignRequest
+	var auditedErrs []error
+
+	keyManager := crypto.NewSigningMiddleware(
+		crypto.NewLocalKeyManager(),
+		crypto.Caller("issuer-api"),
+		crypto.PreSign(func(req crypto.SignRequest) error {
+			if string(req.Payload) == "forbidden" {
+				return errors.New("payload not allowed")
+			}
+
+			return nil
+		}),
+		crypto.PostSign(func(req crypto.SignRequest, signature []byte, err error) {
+			audited = append(audited, req)
+			auditedErrs = append(auditedErrs, err)
+		}),
+	)
+
+	keyID, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	signature, err := keyManager.Sign(keyID, []byte("hello"))
+	assert.NoError(t, err)
+
+	publicKey, err := keyManager.GetPublicKey(keyID)
+	assert.NoError(t, err)
+
+	legit, err := dsa.Verify([]byte("hello"), signature, publicKey)
+	assert.NoError(t, err)
+	assert.True(t, legit)
+
+	_, err = keyManager.WithCaller("admin").Sign(keyID, []byte("forbidden"))
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, crypto.ErrSigningDenied))
+
+	digest := sha256.Sum256([]byte("hello"))
+
+	assert.Equal(t, 2, len(audited))
+	assert.Equal(t, keyID, audited[0].KeyID)
+	assert.Equal(t, hex.EncodeToString(digest[:]), audited[0].PayloadDigest)
+	assert.Equal(t, "issuer-api", audited[0].Caller)
+	assert.NoError(t, auditedErrs[0])
+	assert.Equal(t, "admin", audited[1].Caller)
+	assert.True(t, errors.Is(auditedErrs[1], crypto.ErrSigningDenied))
+}
+
+func TestSigningMiddleware_BearerDID(t *testing.T) {
+	var signed int
+
+	keyManager := crypto.NewSigningMiddleware(
+		crypto.NewLocalKeyManager(),
+		crypto.PostSign(func(req crypto.SignRequest, signature []byte, err error) {
+			signed++
+		}),
+	)
+
+	did, err := didjwk.Create(didjwk.KeyManager(keyManager))
+	assert.NoError(t, err)
+
+	compactJWS, err := jws.Sign([]byte("hi"), did)
+	assert.NoError(t, err)
+
+	_, err = jws.Verify(compactJWS)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, signed)
+
+	// optional key manager capabilities are forwarded
+	portableDID, err := did.ToPortableDID()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(portableDID.PrivateKeys))
+}
+
+// bareKeyManager hides every optional capability of the key manager it embeds
+type bareKeyManager struct {
+	crypto.KeyManager
+}
+
+func TestSigningMiddleware_Capabilities(t *testing.T) {
+	var keyManager crypto.KeyManager = crypto.NewSigningMiddleware(bareKeyManager{crypto.NewLocalKeyManager()})
+
+	_, ok := keyManager.(crypto.KeyExporter)
+	assert.False(t, ok)
+
+	_, ok = crypto.As[crypto.KeyExporter](keyManager)
+	assert.False(t, ok)
+
+	localKeyManager := crypto.NewLocalKeyManager()
+	keyManager = crypto.NewSigningMiddleware(crypto.NewSigningMiddleware(localKeyManager))
+
+	keyID, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	exporter, ok := crypto.As[crypto.KeyExporter](keyManager)
+	assert.True(t, ok)
+
+	privateKey, err := exporter.ExportKey(keyID)
+	assert.NoError(t, err)
+	assert.NotZero(t, privateKey.D)
+}
+
+func TestRateLimit(t *testing.T) {
+	keyManager := crypto.NewSigningMiddleware(crypto.NewLocalKeyManager(), crypto.PreSign(crypto.RateLimit(2, time.Hour)))
+
+	keyID, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	otherKeyID, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDSECP256K1)
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = keyManager.Sign(keyID, []byte("hi"))
+		assert.NoError(t, err)
+	}
+
+	_, err = keyManager.Sign(keyID, []byte("hi"))
+	assert.True(t, errors.Is(err, crypto.ErrSigningDenied))
+
+	// limits are tracked per key
+	_, err = keyManager.Sign(otherKeyID, []byte("hi"))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,70 @@
+package multikey
+
+import (
+	"errors"
+	"math/big"
+)
+
+// base58btcAlphabet is the Bitcoin base58 alphabet (https://datatracker.ietf.org/doc/html/draft-msporny-base58-03)
+const base58btcAlphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var base58btcIndex = func() [256]int {
+	var index [256]int
+	for i := range index {
+		index[i] = -1
+	}
+
+	for i := 0; i < len(base58btcAlphabet); i++ {
+		index[base58btcAlphabet[i]] = i
+	}
+
+	return index
+}()
+
+func base58btcEncode(input []byte) string {
+	zeros := 0
+	for zeros < len(input) && input[zeros] == 0 {
+		zeros++
+	}
+
+	value := new(big.Int).SetBytes(input)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+
+	encoded := make([]byte, 0, len(input)*138/100+1)
+	for value.Sign() > 0 {
+		value.DivMod(value, radix, mod)
+		encoded = append(encoded, base58btcAlphabet[mod.Int64()])
+	}
+
+	for i := 0; i < zeros; i++ {
+		encoded = append(encoded, base58btcAlphabet[0])
+	}
+
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+
+	return string(encoded)
+}
+
+func base58btcDecode(input string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(input) && input[zeros] == base58btcAlphabet[0] {
+		zeros++
+	}
+
+	value := new(big.Int)
+	radix := big.NewInt(58)
+	for i := 0; i < len(input); i++ {
+		digit := base58btcIndex[input[i]]
+		if digit < 0 {
+			return nil, errors.New("invalid base58btc character")
+		}
+
+		value.Mul(value, radix)
+		value.Add(value, big.NewInt(int64(digit)))
+	}
+
+	return append(make([]byte, zeros), value.Bytes()...), nil
+}
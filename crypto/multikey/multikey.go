@@ -0,0 +1,104 @@
+// Package multikey implements encoding and decoding of public keys as multicodec prefixed
+// (https://github.com/multiformats/multicodec), multibase (https://github.com/multiformats/multibase)
+// base58btc strings as used by the publicKeyMultibase property of Multikey verification methods
+// (https://www.w3.org/TR/controller-document/#multikey).
+//
+// Supported keys are Ed25519, secp256k1, P-256 and X25519. EC keys are encoded in compressed form.
+package multikey
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/tbd54566975/web5-go/crypto/dsa/ecdsa"
+	"github.com/tbd54566975/web5-go/crypto/dsa/eddsa"
+	"github.com/tbd54566975/web5-go/crypto/ecdh"
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+// multicodec codes of the supported public key types as defined in
+// https://github.com/multiformats/multicodec/blob/master/table.csv
+const (
+	CodecED25519Pub   uint64 = 0xed
+	CodecX25519Pub    uint64 = 0xec
+	CodecSECP256K1Pub uint64 = 0xe7
+	CodecP256Pub      uint64 = 0x1200
+)
+
+// MultibaseBase58BTC is the multibase prefix of base58btc encoded strings
+const MultibaseBase58BTC = 'z'
+
+// PublicKeyToMultibase encodes the given public key as a multicodec prefixed, base58btc multibase string
+func PublicKeyToMultibase(publicKey jwk.JWK) (string, error) {
+	var codec uint64
+	var keyBytes []byte
+	var err error
+
+	switch publicKey.CRV {
+	case eddsa.ED25519JWACurve:
+		codec = CodecED25519Pub
+		keyBytes, err = eddsa.ED25519PublicKeyToBytes(publicKey)
+	case ecdh.X25519JWACurve:
+		codec = CodecX25519Pub
+		keyBytes, err = ecdh.X25519PublicKeyToBytes(publicKey)
+	case ecdsa.SECP256K1JWACurve:
+		codec = CodecSECP256K1Pub
+		keyBytes, err = ecdsa.SECP256K1PublicKeyToCompressedBytes(publicKey)
+	case ecdsa.SECP256R1JWACurve:
+		codec = CodecP256Pub
+		keyBytes, err = ecdsa.SECP256R1PublicKeyToCompressedBytes(publicKey)
+	default:
+		return "", fmt.Errorf("unsupported curve: %s", publicKey.CRV)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to convert public key to bytes: %w", err)
+	}
+
+	prefixed := binary.AppendUvarint(nil, codec)
+	prefixed = append(prefixed, keyBytes...)
+
+	return string(MultibaseBase58BTC) + base58btcEncode(prefixed), nil
+}
+
+// MultibaseToPublicKey decodes the given multicodec prefixed, base58btc multibase string into a public key
+func MultibaseToPublicKey(input string) (jwk.JWK, error) {
+	codec, keyBytes, err := Decode(input)
+	if err != nil {
+		return jwk.JWK{}, err
+	}
+
+	switch codec {
+	case CodecED25519Pub:
+		return eddsa.ED25519BytesToPublicKey(keyBytes)
+	case CodecX25519Pub:
+		return ecdh.X25519BytesToPublicKey(keyBytes)
+	case CodecSECP256K1Pub:
+		return ecdsa.SECP256K1BytesToPublicKey(keyBytes)
+	case CodecP256Pub:
+		return ecdsa.SECP256R1BytesToPublicKey(keyBytes)
+	default:
+		return jwk.JWK{}, fmt.Errorf("unsupported multicodec: 0x%x", codec)
+	}
+}
+
+// Decode decodes the given base58btc multibase string and splits it into its multicodec code and the
+// remaining bytes
+func Decode(input string) (uint64, []byte, error) {
+	if len(input) == 0 || input[0] != MultibaseBase58BTC {
+		return 0, nil, errors.New("unsupported multibase encoding: expected base58btc (z)")
+	}
+
+	decoded, err := base58btcDecode(input[1:])
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to decode multibase: %w", err)
+	}
+
+	codec, n := binary.Uvarint(decoded)
+	if n <= 0 {
+		return 0, nil, errors.New("failed to decode multicodec prefix")
+	}
+
+	return codec, decoded[n:], nil
+}
@@ -0,0 +1,80 @@
+package multikey
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/crypto/ecdh"
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+func TestMultibaseToPublicKey_Vector(t *testing.T) {
+	// https://w3c-ccg.github.io/did-method-key/#ed25519-x25519
+	codec, keyBytes, err := Decode("z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp")
+	assert.NoError(t, err)
+	assert.Equal(t, CodecED25519Pub, codec)
+	assert.Equal(t, "4zvwRjXUKGfvwnParsHAS3HuSVzV5cA4McphgmoCtajS", base58btcEncode(keyBytes))
+}
+
+func TestPublicKeyToMultibase_RoundTrip(t *testing.T) {
+	tests := map[string]string{
+		dsa.AlgorithmIDED25519:   "z6Mk",
+		dsa.AlgorithmIDSECP256K1: "zQ3s",
+		dsa.AlgorithmIDSECP256R1: "zDn",
+		ecdh.X25519AlgorithmID:   "z6LS",
+	}
+
+	for algorithmID, prefix := range tests {
+		t.Run(algorithmID, func(t *testing.T) {
+			publicKey := generatePublicKey(t, algorithmID)
+
+			encoded, err := PublicKeyToMultibase(publicKey)
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(encoded, prefix), encoded)
+
+			decoded, err := MultibaseToPublicKey(encoded)
+			assert.NoError(t, err)
+			assert.Equal(t, publicKey, decoded)
+		})
+	}
+}
+
+func TestPublicKeyToMultibase_Unsupported(t *testing.T) {
+	publicKey := generatePublicKey(t, dsa.AlgorithmIDSECP384R1)
+
+	_, err := PublicKeyToMultibase(publicKey)
+	assert.Error(t, err)
+}
+
+func TestMultibaseToPublicKey_Bad(t *testing.T) {
+	for _, input := range []string{"", "f0123", "z0OIl", "z2"} {
+		_, err := MultibaseToPublicKey(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestBase58BTC_LeadingZeros(t *testing.T) {
+	input := []byte{0, 0, 1, 2, 3}
+
+	decoded, err := base58btcDecode(base58btcEncode(input))
+	assert.NoError(t, err)
+	assert.Equal(t, input, decoded)
+}
+
+func generatePublicKey(t *testing.T, algorithmID string) jwk.JWK {
+	t.Helper()
+
+	if algorithmID == ecdh.X25519AlgorithmID {
+		privateKey, err := ecdh.GeneratePrivateKey(algorithmID)
+		assert.NoError(t, err)
+
+		return ecdh.GetPublicKey(privateKey)
+	}
+
+	privateKey, err := dsa.GeneratePrivateKey(algorithmID)
+	assert.NoError(t, err)
+
+	return dsa.GetPublicKey(privateKey)
+}
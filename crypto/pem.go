@@ -0,0 +1,420 @@
+package crypto
+
+import (
+	_ecdh "crypto/ecdh"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/tbd54566975/web5-go/crypto/dsa/ecdsa"
+	"github.com/tbd54566975/web5-go/crypto/dsa/eddsa"
+	"github.com/tbd54566975/web5-go/crypto/ecdh"
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+// PEM block types produced and accepted by [PEMToJWK] and [JWKToPEM]
+const (
+	PEMTypePublicKey    = "PUBLIC KEY"
+	PEMTypePrivateKey   = "PRIVATE KEY"
+	PEMTypeECPrivateKey = "EC PRIVATE KEY"
+)
+
+var (
+	oidPublicKeyEC  = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidPublicKeyOKP = map[string]asn1.ObjectIdentifier{
+		ecdh.X25519JWACurve:   {1, 3, 101, 110},
+		eddsa.ED25519JWACurve: {1, 3, 101, 112},
+	}
+)
+
+// ecCurve describes how keys on an EC curve are represented in ASN.1
+type ecCurve struct {
+	oid        asn1.ObjectIdentifier
+	scalarSize int
+	// stdlib is nil for curves that are not supported by the standard library (i.e. secp256k1)
+	stdlib _ecdh.Curve
+}
+
+var ecCurves = map[string]ecCurve{
+	ecdsa.SECP256K1JWACurve: {oid: asn1.ObjectIdentifier{1, 3, 132, 0, 10}, scalarSize: 32},
+	ecdsa.SECP256R1JWACurve: {oid: asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}, scalarSize: 32, stdlib: _ecdh.P256()},
+	ecdsa.SECP384R1JWACurve: {oid: asn1.ObjectIdentifier{1, 3, 132, 0, 34}, scalarSize: 48, stdlib: _ecdh.P384()},
+	ecdsa.SECP521R1JWACurve: {oid: asn1.ObjectIdentifier{1, 3, 132, 0, 35}, scalarSize: 66, stdlib: _ecdh.P521()},
+}
+
+// algorithmIdentifier as defined in https://datatracker.ietf.org/doc/html/rfc5280#section-4.1.1.2
+type algorithmIdentifier struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters asn1.RawValue `asn1:"optional"`
+}
+
+// subjectPublicKeyInfo as defined in https://datatracker.ietf.org/doc/html/rfc5280#section-4.1.2.7
+type subjectPublicKeyInfo struct {
+	Algorithm algorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// oneAsymmetricKey as defined in https://datatracker.ietf.org/doc/html/rfc5958#section-2.
+// The optional attributes and public key are not represented.
+type oneAsymmetricKey struct {
+	Version    int
+	Algorithm  algorithmIdentifier
+	PrivateKey []byte
+}
+
+// ecPrivateKey as defined in https://datatracker.ietf.org/doc/html/rfc5915#section-3
+type ecPrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+// PEMToJWK decodes the first PEM block in the given data into a JWK. Supported block types are
+// PUBLIC KEY (SPKI), PRIVATE KEY (PKCS#8) and EC PRIVATE KEY (SEC1)
+func PEMToJWK(data []byte) (jwk.JWK, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return jwk.JWK{}, errors.New("failed to decode PEM block")
+	}
+
+	switch block.Type {
+	case PEMTypePublicKey:
+		return DERToPublicKey(block.Bytes)
+	case PEMTypePrivateKey:
+		return DERToPrivateKey(block.Bytes)
+	case PEMTypeECPrivateKey:
+		return SEC1DERToPrivateKey(block.Bytes)
+	default:
+		return jwk.JWK{}, fmt.Errorf("unsupported PEM block type: %s", block.Type)
+	}
+}
+
+// JWKToPEM encodes the given JWK as a PEM block. Private keys are encoded as PKCS#8 (PRIVATE KEY)
+// and public keys as SPKI (PUBLIC KEY)
+func JWKToPEM(key jwk.JWK) ([]byte, error) {
+	block := &pem.Block{}
+
+	var err error
+	if key.D != "" {
+		block.Type = PEMTypePrivateKey
+		block.Bytes, err = PrivateKeyToDER(key)
+	} else {
+		block.Type = PEMTypePublicKey
+		block.Bytes, err = PublicKeyToDER(key)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(block), nil
+}
+
+// ImportPEM decodes the given PEM encoded private key and imports it using the given [KeyImporter]
+func ImportPEM(importer KeyImporter, data []byte) (string, error) {
+	key, err := PEMToJWK(data)
+	if err != nil {
+		return "", err
+	}
+
+	if key.D == "" {
+		return "", errors.New("PEM does not contain a private key")
+	}
+
+	return importer.ImportKey(key)
+}
+
+// ExportPEM exports the key with the given key id using the given [KeyExporter] as a PKCS#8 PEM block
+func ExportPEM(exporter KeyExporter, keyID string) ([]byte, error) {
+	key, err := exporter.ExportKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return JWKToPEM(key)
+}
+
+// PublicKeyToDER encodes the given public key as a DER encoded SubjectPublicKeyInfo (SPKI)
+func PublicKeyToDER(publicKey jwk.JWK) ([]byte, error) {
+	var spki subjectPublicKeyInfo
+
+	var keyBytes []byte
+	var err error
+	if curve, ok := ecCurves[publicKey.CRV]; ok && publicKey.KTY == ecdsa.KeyType {
+		spki.Algorithm, err = ecAlgorithmIdentifier(curve)
+		if err != nil {
+			return nil, err
+		}
+
+		keyBytes, err = ecdh.PublicKeyToBytes(publicKey)
+	} else if oid, ok := oidPublicKeyOKP[publicKey.CRV]; ok && publicKey.KTY == eddsa.KeyType {
+		spki.Algorithm = algorithmIdentifier{Algorithm: oid}
+		keyBytes, err = base64.RawURLEncoding.DecodeString(publicKey.X)
+	} else {
+		return nil, fmt.Errorf("unsupported key type and curve: %s %s", publicKey.KTY, publicKey.CRV)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert public key to bytes: %w", err)
+	}
+
+	spki.PublicKey = asn1.BitString{Bytes: keyBytes, BitLength: 8 * len(keyBytes)}
+
+	return asn1.Marshal(spki)
+}
+
+// DERToPublicKey decodes the given DER encoded SubjectPublicKeyInfo (SPKI) into a public key
+func DERToPublicKey(der []byte) (jwk.JWK, error) {
+	var spki subjectPublicKeyInfo
+	if rest, err := asn1.Unmarshal(der, &spki); err != nil {
+		return jwk.JWK{}, fmt.Errorf("failed to parse SubjectPublicKeyInfo: %w", err)
+	} else if len(rest) > 0 {
+		return jwk.JWK{}, errors.New("failed to parse SubjectPublicKeyInfo: trailing data")
+	}
+
+	keyBytes := spki.PublicKey.RightAlign()
+
+	if spki.Algorithm.Algorithm.Equal(oidPublicKeyEC) {
+		crv, err := parseECParameters(spki.Algorithm.Parameters.FullBytes)
+		if err != nil {
+			return jwk.JWK{}, err
+		}
+
+		return ecdh.BytesToPublicKey(crv, keyBytes)
+	}
+
+	switch crv := okpCurve(spki.Algorithm.Algorithm); crv {
+	case eddsa.ED25519JWACurve:
+		return eddsa.ED25519BytesToPublicKey(keyBytes)
+	case ecdh.X25519JWACurve:
+		return ecdh.X25519BytesToPublicKey(keyBytes)
+	default:
+		return jwk.JWK{}, fmt.Errorf("unsupported public key algorithm: %s", spki.Algorithm.Algorithm)
+	}
+}
+
+// PrivateKeyToDER encodes the given private key as a DER encoded PKCS#8 private key
+func PrivateKeyToDER(privateKey jwk.JWK) ([]byte, error) {
+	d, err := base64.RawURLEncoding.DecodeString(privateKey.D)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode d: %w", err)
+	}
+
+	pkcs8 := oneAsymmetricKey{}
+
+	if curve, ok := ecCurves[privateKey.CRV]; ok && privateKey.KTY == ecdsa.KeyType {
+		pkcs8.Algorithm, err = ecAlgorithmIdentifier(curve)
+		if err != nil {
+			return nil, err
+		}
+
+		// the curve is already identified by the algorithm identifier so it is omitted from ECPrivateKey
+		pkcs8.PrivateKey, err = marshalECPrivateKey(privateKey, curve, d, nil)
+		if err != nil {
+			return nil, err
+		}
+	} else if oid, ok := oidPublicKeyOKP[privateKey.CRV]; ok && privateKey.KTY == eddsa.KeyType {
+		// Ed25519 private keys are stored as seed || public key
+		if privateKey.CRV == eddsa.ED25519JWACurve && len(d) == 64 {
+			d = d[:32]
+		}
+
+		pkcs8.Algorithm = algorithmIdentifier{Algorithm: oid}
+		pkcs8.PrivateKey, err = asn1.Marshal(d)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal private key: %w", err)
+		}
+	} else {
+		return nil, fmt.Errorf("unsupported key type and curve: %s %s", privateKey.KTY, privateKey.CRV)
+	}
+
+	return asn1.Marshal(pkcs8)
+}
+
+// DERToPrivateKey decodes the given DER encoded PKCS#8 private key into a JWK
+func DERToPrivateKey(der []byte) (jwk.JWK, error) {
+	var pkcs8 oneAsymmetricKey
+	if _, err := asn1.Unmarshal(der, &pkcs8); err != nil {
+		return jwk.JWK{}, fmt.Errorf("failed to parse PKCS#8 private key: %w", err)
+	}
+
+	if pkcs8.Algorithm.Algorithm.Equal(oidPublicKeyEC) {
+		crv, err := parseECParameters(pkcs8.Algorithm.Parameters.FullBytes)
+		if err != nil {
+			return jwk.JWK{}, err
+		}
+
+		return parseECPrivateKey(pkcs8.PrivateKey, crv)
+	}
+
+	crv := okpCurve(pkcs8.Algorithm.Algorithm)
+	if crv == "" {
+		return jwk.JWK{}, fmt.Errorf("unsupported private key algorithm: %s", pkcs8.Algorithm.Algorithm)
+	}
+
+	var seed []byte
+	if _, err := asn1.Unmarshal(pkcs8.PrivateKey, &seed); err != nil {
+		return jwk.JWK{}, fmt.Errorf("failed to parse %s private key: %w", crv, err)
+	}
+
+	if crv == eddsa.ED25519JWACurve {
+		return eddsa.ED25519BytesToPrivateKey(seed)
+	}
+
+	key, err := _ecdh.X25519().NewPrivateKey(seed)
+	if err != nil {
+		return jwk.JWK{}, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	return jwk.JWK{
+		KTY: ecdh.KeyTypeOKP,
+		CRV: ecdh.X25519JWACurve,
+		D:   base64.RawURLEncoding.EncodeToString(key.Bytes()),
+		X:   base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+	}, nil
+}
+
+// PrivateKeyToSEC1DER encodes the given EC private key as a DER encoded SEC1 ECPrivateKey
+// (https://datatracker.ietf.org/doc/html/rfc5915)
+func PrivateKeyToSEC1DER(privateKey jwk.JWK) ([]byte, error) {
+	curve, ok := ecCurves[privateKey.CRV]
+	if !ok || privateKey.KTY != ecdsa.KeyType {
+		return nil, fmt.Errorf("unsupported key type and curve: %s %s", privateKey.KTY, privateKey.CRV)
+	}
+
+	d, err := base64.RawURLEncoding.DecodeString(privateKey.D)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode d: %w", err)
+	}
+
+	return marshalECPrivateKey(privateKey, curve, d, curve.oid)
+}
+
+// SEC1DERToPrivateKey decodes the given DER encoded SEC1 ECPrivateKey into a JWK. The curve must be
+// included in the ECPrivateKey
+func SEC1DERToPrivateKey(der []byte) (jwk.JWK, error) {
+	return parseECPrivateKey(der, "")
+}
+
+func marshalECPrivateKey(privateKey jwk.JWK, curve ecCurve, d []byte, oid asn1.ObjectIdentifier) ([]byte, error) {
+	if len(d) > curve.scalarSize {
+		return nil, fmt.Errorf("d must be at most %d bytes", curve.scalarSize)
+	}
+
+	publicKey, err := ecdh.PublicKeyToBytes(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert public key to bytes: %w", err)
+	}
+
+	padded := make([]byte, curve.scalarSize-len(d), curve.scalarSize)
+
+	return asn1.Marshal(ecPrivateKey{
+		Version:       1,
+		PrivateKey:    append(padded, d...),
+		NamedCurveOID: oid,
+		PublicKey:     asn1.BitString{Bytes: publicKey, BitLength: 8 * len(publicKey)},
+	})
+}
+
+// parseECPrivateKey parses a SEC1 ECPrivateKey. crv is the curve identified outside of the ECPrivateKey
+// (e.g. by a PKCS#8 algorithm identifier) or empty if the ECPrivateKey must identify the curve itself.
+func parseECPrivateKey(der []byte, crv string) (jwk.JWK, error) {
+	var key ecPrivateKey
+	if _, err := asn1.Unmarshal(der, &key); err != nil {
+		return jwk.JWK{}, fmt.Errorf("failed to parse EC private key: %w", err)
+	}
+
+	if key.Version != 1 {
+		return jwk.JWK{}, fmt.Errorf("unsupported EC private key version: %d", key.Version)
+	}
+
+	if len(key.NamedCurveOID) > 0 {
+		namedCurve := ecCurveByOID(key.NamedCurveOID)
+		if namedCurve == "" {
+			return jwk.JWK{}, fmt.Errorf("unsupported curve: %s", key.NamedCurveOID)
+		}
+
+		if crv != "" && crv != namedCurve {
+			return jwk.JWK{}, errors.New("EC private key curve does not match algorithm parameters")
+		}
+
+		crv = namedCurve
+	}
+
+	if crv == "" {
+		return jwk.JWK{}, errors.New("EC private key does not identify its curve")
+	}
+
+	curve := ecCurves[crv]
+	if len(key.PrivateKey) > curve.scalarSize {
+		return jwk.JWK{}, fmt.Errorf("private key must be at most %d bytes", curve.scalarSize)
+	}
+
+	d := append(make([]byte, curve.scalarSize-len(key.PrivateKey)), key.PrivateKey...)
+
+	if curve.stdlib == nil {
+		return ecdsa.SECP256K1BytesToPrivateKey(d)
+	}
+
+	stdlibKey, err := curve.stdlib.NewPrivateKey(d)
+	if err != nil {
+		return jwk.JWK{}, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	privateKey, err := ecdh.BytesToPublicKey(crv, stdlibKey.PublicKey().Bytes())
+	if err != nil {
+		return jwk.JWK{}, err
+	}
+
+	privateKey.D = base64.RawURLEncoding.EncodeToString(d)
+
+	return privateKey, nil
+}
+
+// ecAlgorithmIdentifier returns the id-ecPublicKey algorithm identifier with the given named curve as its parameters
+func ecAlgorithmIdentifier(curve ecCurve) (algorithmIdentifier, error) {
+	params, err := asn1.Marshal(curve.oid)
+	if err != nil {
+		return algorithmIdentifier{}, fmt.Errorf("failed to marshal curve: %w", err)
+	}
+
+	return algorithmIdentifier{Algorithm: oidPublicKeyEC, Parameters: asn1.RawValue{FullBytes: params}}, nil
+}
+
+// parseECParameters returns the curve identified by the given DER encoded namedCurve parameters
+func parseECParameters(params []byte) (string, error) {
+	var oid asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(params, &oid); err != nil {
+		return "", errors.New("EC parameters must be a named curve")
+	}
+
+	crv := ecCurveByOID(oid)
+	if crv == "" {
+		return "", fmt.Errorf("unsupported curve: %s", oid)
+	}
+
+	return crv, nil
+}
+
+func ecCurveByOID(oid asn1.ObjectIdentifier) string {
+	for crv, curve := range ecCurves {
+		if curve.oid.Equal(oid) {
+			return crv
+		}
+	}
+
+	return ""
+}
+
+func okpCurve(oid asn1.ObjectIdentifier) string {
+	for crv, okpOID := range oidPublicKeyOKP {
+		if okpOID.Equal(oid) {
+			return crv
+		}
+	}
+
+	return ""
+}
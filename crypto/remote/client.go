@@ -0,0 +1,197 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+// KeyManager is an implementation of [github.com/tbd54566975/web5-go/crypto.KeyManager] that delegates
+// all operations to a remote signer. Private keys never leave the remote signer.
+type KeyManager struct {
+	baseURL string
+	client  *http.Client
+	editors []func(ctx context.Context, req *http.Request) error
+}
+
+// options that [NewKeyManager] can take
+type keyManagerOpts struct {
+	client  *http.Client
+	editors []func(ctx context.Context, req *http.Request) error
+}
+
+// KeyManagerOpt is a type that represents an option that can be passed to [NewKeyManager]
+type KeyManagerOpt func(opts *keyManagerOpts)
+
+// HTTPClient is an option that can be passed to [NewKeyManager]. It is used to set the http.Client
+// used to reach the remote signer. Defaults to [http.DefaultClient]
+func HTTPClient(client *http.Client) KeyManagerOpt {
+	return func(opts *keyManagerOpts) {
+		opts.client = client
+	}
+}
+
+// RequestEditor is an option that can be passed to [NewKeyManager]. The provided function is called with
+// every outgoing request before it is sent and can be used to add authentication headers. Returning an
+// error aborts the request.
+func RequestEditor(editor func(ctx context.Context, req *http.Request) error) KeyManagerOpt {
+	return func(opts *keyManagerOpts) {
+		opts.editors = append(opts.editors, editor)
+	}
+}
+
+// BearerToken is an option that can be passed to [NewKeyManager]. It is used to set the Authorization
+// header of every outgoing request to "Bearer <token>"
+func BearerToken(token string) KeyManagerOpt {
+	return RequestEditor(func(_ context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// NewKeyManager returns a new [KeyManager] that talks to the remote signer at the given base URL
+func NewKeyManager(baseURL string, opts ...KeyManagerOpt) *KeyManager {
+	o := keyManagerOpts{client: http.DefaultClient}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &KeyManager{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  o.client,
+		editors: o.editors,
+	}
+}
+
+// GeneratePrivateKey asks the remote signer to generate a new private key and returns its key id
+func (k *KeyManager) GeneratePrivateKey(algorithmID string) (string, error) {
+	return k.GeneratePrivateKeyWithContext(context.Background(), algorithmID)
+}
+
+// GeneratePrivateKeyWithContext asks the remote signer to generate a new private key and returns its key id
+func (k *KeyManager) GeneratePrivateKeyWithContext(ctx context.Context, algorithmID string) (string, error) {
+	var res generateKeyResponse
+	err := k.do(ctx, http.MethodPost, "/keys", generateKeyRequest{AlgorithmID: algorithmID}, &res)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate private key: %w", err)
+	}
+
+	return res.KeyID, nil
+}
+
+// GetPublicKey returns the public key for the given key id
+func (k *KeyManager) GetPublicKey(keyID string) (jwk.JWK, error) {
+	return k.GetPublicKeyWithContext(context.Background(), keyID)
+}
+
+// GetPublicKeyWithContext returns the public key for the given key id
+func (k *KeyManager) GetPublicKeyWithContext(ctx context.Context, keyID string) (jwk.JWK, error) {
+	var res getPublicKeyResponse
+	err := k.do(ctx, http.MethodGet, "/keys/"+url.PathEscape(keyID), nil, &res)
+	if err != nil {
+		return jwk.JWK{}, fmt.Errorf("failed to get public key: %w", err)
+	}
+
+	return res.PublicKey, nil
+}
+
+// Sign asks the remote signer to sign the payload with the private key for the given key id
+func (k *KeyManager) Sign(keyID string, payload []byte) ([]byte, error) {
+	return k.SignWithContext(context.Background(), keyID, payload)
+}
+
+// SignWithContext asks the remote signer to sign the payload with the private key for the given key id
+func (k *KeyManager) SignWithContext(ctx context.Context, keyID string, payload []byte) ([]byte, error) {
+	req := signRequest{Payload: base64.RawURLEncoding.EncodeToString(payload)}
+
+	var res signResponse
+	err := k.do(ctx, http.MethodPost, "/keys/"+url.PathEscape(keyID)+"/sign", req, &res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(res.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signature: %w", err)
+	}
+
+	return signature, nil
+}
+
+// ResponseError is returned when the remote signer responds with a non 2xx status code
+type ResponseError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e ResponseError) Error() string {
+	return fmt.Sprintf("remote signer responded with %d: %s", e.StatusCode, e.Message)
+}
+
+func (k *KeyManager) do(ctx context.Context, method string, path string, body any, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to serialize request: %w", err)
+		}
+
+		reqBody = bytes.NewReader(bodyBytes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, k.baseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	for _, editor := range k.editors {
+		if err := editor(ctx, req); err != nil {
+			return fmt.Errorf("failed to edit request: %w", err)
+		}
+	}
+
+	res, err := k.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach remote signer: %w", err)
+	}
+
+	defer res.Body.Close()
+
+	// read one byte past the limit to tell a response that's exactly at the limit from one that exceeds it
+	resBody, err := io.ReadAll(io.LimitReader(res.Body, maxMessageSize+1))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if len(resBody) > maxMessageSize {
+		return fmt.Errorf("response exceeds %d bytes", maxMessageSize)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		var errRes errorResponse
+		if err := json.Unmarshal(resBody, &errRes); err != nil || errRes.Error == "" {
+			errRes.Error = http.StatusText(res.StatusCode)
+		}
+
+		return ResponseError{StatusCode: res.StatusCode, Message: errRes.Error}
+	}
+
+	if err := json.Unmarshal(resBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+// Package remote implements a [github.com/tbd54566975/web5-go/crypto.KeyManager] that delegates key
+// generation and signing to a remote signing service over HTTP, along with a reference server
+// ([NewHandler]) that exposes any existing KeyManager over the same protocol.
+//
+// # Protocol
+//
+// All request and response bodies are JSON and at most 1 MiB. Binary values are base64url encoded without
+// padding.
+//
+//	POST /keys                  {"algorithmId": "Ed25519"}   -> 201 {"keyId": "..."}
+//	GET  /keys/{keyId}                                       -> 200 {"publicKey": {JWK}}
+//	POST /keys/{keyId}/sign     {"payload": "<base64url>"}   -> 200 {"signature": "<base64url>"}
+//
+// Non 2xx responses carry a body of {"error": "message"}. Authentication is left to the deployment:
+// clients can decorate every request using [RequestEditor] (e.g. [BearerToken]) and servers can reject
+// requests using [Authorizer].
+package remote
+
+import "github.com/tbd54566975/web5-go/jwk"
+
+type generateKeyRequest struct {
+	AlgorithmID string `json:"algorithmId"`
+}
+
+type generateKeyResponse struct {
+	KeyID string `json:"keyId"`
+}
+
+type getPublicKeyResponse struct {
+	PublicKey jwk.JWK `json:"publicKey"`
+}
+
+type signRequest struct {
+	Payload string `json:"payload"`
+}
+
+type signResponse struct {
+	Signature string `json:"signature"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
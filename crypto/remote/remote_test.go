@@ -0,0 +1,149 @@
+package remote_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/crypto"
+	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/crypto/remote"
+	"github.com/tbd54566975/web5-go/dids/didjwk"
+	"github.com/tbd54566975/web5-go/jws"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	authorize := remote.Authorizer(func(r *http.Request) error {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			return errors.New("invalid token")
+		}
+
+		return nil
+	})
+
+	server := httptest.NewServer(remote.NewHandler(crypto.NewLocalKeyManager(), authorize))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestKeyManager(t *testing.T) {
+	server := newTestServer(t)
+	keyManager := remote.NewKeyManager(server.URL, remote.BearerToken("secret"), remote.HTTPClient(server.Client()))
+
+	keyID, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	publicKey, err := keyManager.GetPublicKey(keyID)
+	assert.NoError(t, err)
+	assert.Equal(t, "", publicKey.D)
+
+	thumbprint, err := publicKey.ComputeThumbprint()
+	assert.NoError(t, err)
+	assert.Equal(t, keyID, thumbprint)
+
+	payload := []byte("hello world")
+	signature, err := keyManager.Sign(keyID, payload)
+	assert.NoError(t, err)
+
+	legit, err := dsa.Verify(payload, signature, publicKey)
+	assert.NoError(t, err)
+	assert.True(t, legit)
+}
+
+func TestKeyManager_DID(t *testing.T) {
+	server := newTestServer(t)
+	keyManager := remote.NewKeyManager(server.URL, remote.BearerToken("secret"))
+
+	did, err := didjwk.Create(didjwk.KeyManager(keyManager))
+	assert.NoError(t, err)
+
+	compactJWS, err := jws.Sign([]byte("hi"), did)
+	assert.NoError(t, err)
+
+	_, err = jws.Verify(compactJWS)
+	assert.NoError(t, err)
+}
+
+func TestKeyManager_Unauthorized(t *testing.T) {
+	server := newTestServer(t)
+	keyManager := remote.NewKeyManager(server.URL, remote.BearerToken("wrong"))
+
+	_, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.Error(t, err)
+
+	var responseErr remote.ResponseError
+	assert.True(t, errors.As(err, &responseErr))
+	assert.Equal(t, http.StatusUnauthorized, responseErr.StatusCode)
+	assert.Equal(t, "invalid token", responseErr.Message)
+}
+
+func TestKeyManager_Errors(t *testing.T) {
+	server := newTestServer(t)
+	keyManager := remote.NewKeyManager(server.URL+"/", remote.BearerToken("secret"))
+
+	_, err := keyManager.GeneratePrivateKey("brainpoolP256r1")
+	assert.Error(t, err)
+
+	_, err = keyManager.GetPublicKey("doesnotexist")
+	var responseErr remote.ResponseError
+	assert.True(t, errors.As(err, &responseErr))
+	assert.Equal(t, http.StatusNotFound, responseErr.StatusCode)
+
+	_, err = keyManager.Sign("doesnotexist", []byte("hello world"))
+	assert.Error(t, err)
+}
+
+func TestKeyManager_RequestEditorError(t *testing.T) {
+	server := newTestServer(t)
+	keyManager := remote.NewKeyManager(server.URL, remote.RequestEditor(func(ctx context.Context, req *http.Request) error {
+		return errors.New("no credentials available")
+	}))
+
+	_, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.Error(t, err)
+}
+
+func TestKeyManager_ContextCanceled(t *testing.T) {
+	server := newTestServer(t)
+	keyManager := remote.NewKeyManager(server.URL, remote.BearerToken("secret"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := keyManager.GeneratePrivateKeyWithContext(ctx, dsa.AlgorithmIDED25519)
+	assert.IsError(t, err, context.Canceled)
+}
+
+func TestKeyManager_OversizedResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"keyId":"` + strings.Repeat("a", 2<<20) + `"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	keyManager := remote.NewKeyManager(server.URL)
+
+	_, err := keyManager.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.Error(t, err)
+}
+
+func TestHandler_MalformedRequest(t *testing.T) {
+	handler := remote.NewHandler(crypto.NewLocalKeyManager())
+
+	req := httptest.NewRequest(http.MethodPost, "/keys/abc/sign", nil)
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/keys/abc", nil)
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+}
@@ -0,0 +1,125 @@
+package remote
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+
+	"github.com/tbd54566975/web5-go/crypto"
+)
+
+// maxMessageSize caps request and response bodies to keep a misbehaving client or remote signer
+// from exhausting memory
+const maxMessageSize = 1 << 20
+
+// options that [NewHandler] can take
+type handlerOpts struct {
+	authorize func(r *http.Request) error
+}
+
+// HandlerOpt is a type that represents an option that can be passed to [NewHandler]
+type HandlerOpt func(opts *handlerOpts)
+
+// Authorizer is an option that can be passed to [NewHandler]. The provided function is called with every
+// incoming request. Returning an error rejects the request with 401 Unauthorized
+func Authorizer(authorize func(r *http.Request) error) HandlerOpt {
+	return func(opts *handlerOpts) {
+		opts.authorize = authorize
+	}
+}
+
+// NewHandler returns an http.Handler that exposes the given KeyManager using the protocol described in
+// the package documentation. It is intended as a reference implementation of a remote signer and as a
+// test double for [KeyManager].
+func NewHandler(keyManager crypto.KeyManager, opts ...HandlerOpt) http.Handler {
+	o := handlerOpts{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	h := handler{keyManager: keyManager}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /keys", h.generatePrivateKey)
+	mux.HandleFunc("GET /keys/{keyID}", h.getPublicKey)
+	mux.HandleFunc("POST /keys/{keyID}/sign", h.sign)
+
+	if o.authorize == nil {
+		return mux
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := o.authorize(r); err != nil {
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	})
+}
+
+type handler struct {
+	keyManager crypto.KeyManager
+}
+
+func (h handler) generatePrivateKey(w http.ResponseWriter, r *http.Request) {
+	var req generateKeyRequest
+	if err := readJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "malformed request body")
+		return
+	}
+
+	keyID, err := h.keyManager.GeneratePrivateKey(req.AlgorithmID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, generateKeyResponse{KeyID: keyID})
+}
+
+func (h handler) getPublicKey(w http.ResponseWriter, r *http.Request) {
+	publicKey, err := h.keyManager.GetPublicKey(r.PathValue("keyID"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, getPublicKeyResponse{PublicKey: publicKey})
+}
+
+func (h handler) sign(w http.ResponseWriter, r *http.Request) {
+	var req signRequest
+	if err := readJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "malformed request body")
+		return
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(req.Payload)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "payload must be base64url encoded")
+		return
+	}
+
+	signature, err := h.keyManager.Sign(r.PathValue("keyID"), payload)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, signResponse{Signature: base64.RawURLEncoding.EncodeToString(signature)})
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	return json.NewDecoder(http.MaxBytesReader(w, r.Body, maxMessageSize)).Decode(v)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
@@ -1,6 +1,7 @@
 package did
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/tbd54566975/web5-go/crypto"
@@ -15,31 +16,45 @@ type BearerDID struct {
 	DID
 	crypto.KeyManager
 	Document didcore.Document
+	// RotatedKeys are the keys replaced by [BearerDID.RotateKey] that are retained for a grace period
+	RotatedKeys []RotatedKey
 }
 
 // DIDSigner is a function returned by GetSigner that can be used to sign a payload with a key
 // associated to a BearerDID.
 type DIDSigner func(payload []byte) ([]byte, error)
 
-// ToPortableDID exports a BearerDID to a portable format
+// ToPortableDID exports a BearerDID to a portable format. An error wrapping [crypto.ErrKeyNotExportable]
+// is returned if the BearerDID's key manager refuses to export its private keys
+// (see [crypto.NonExportable]).
 func (d *BearerDID) ToPortableDID() (PortableDID, error) {
 	portableDID := PortableDID{
-		URI:      d.URI,
-		Document: d.Document,
+		URI:         d.URI,
+		Document:    d.Document,
+		RotatedKeys: d.RotatedKeys,
 	}
 
-	exporter, ok := d.KeyManager.(crypto.KeyExporter)
+	exporter, ok := crypto.As[crypto.KeyExporter](d.KeyManager)
 	if ok {
 		privateKeys := make([]jwk.JWK, 0)
 
 		for _, vm := range d.Document.VerificationMethod {
-			keyAlias, err := vm.PublicKeyJwk.ComputeThumbprint()
+			publicKey, err := vm.PublicKey()
+			if err != nil {
+				continue
+			}
+
+			keyAlias, err := publicKey.ComputeThumbprint()
 			if err != nil {
 				continue
 			}
 
 			key, err := exporter.ExportKey(keyAlias)
 			if err != nil {
+				if errors.Is(err, crypto.ErrKeyNotExportable) {
+					return PortableDID{}, fmt.Errorf("failed to export private key for %s: %w", vm.ID, err)
+				}
+
 				// TODO: decide if we want to blow up or continue
 				continue
 			}
@@ -77,7 +92,12 @@ func (d *BearerDID) GetSigner(selector didcore.VMSelector) (DIDSigner, didcore.V
 		return nil, didcore.VerificationMethod{}, err
 	}
 
-	keyAlias, err := vm.PublicKeyJwk.ComputeThumbprint()
+	publicKey, err := vm.PublicKey()
+	if err != nil {
+		return nil, didcore.VerificationMethod{}, err
+	}
+
+	keyAlias, err := publicKey.ComputeThumbprint()
 	if err != nil {
 		return nil, didcore.VerificationMethod{}, fmt.Errorf("failed to compute key alias: %s", err.Error())
 	}
@@ -106,8 +126,9 @@ func FromPortableDID(portableDID PortableDID) (BearerDID, error) {
 	}
 
 	return BearerDID{
-		DID:        did,
-		KeyManager: keyManager,
-		Document:   portableDID.Document,
+		DID:         did,
+		KeyManager:  keyManager,
+		Document:    portableDID.Document,
+		RotatedKeys: portableDID.RotatedKeys,
 	}, nil
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/crypto"
 	"github.com/tbd54566975/web5-go/crypto/dsa"
 	"github.com/tbd54566975/web5-go/dids/did"
 	"github.com/tbd54566975/web5-go/dids/didcore"
@@ -27,6 +28,17 @@ func TestToPortableDID(t *testing.T) {
 	assert.NotEqual(t, jwk.JWK{}, key, "expected key to not be empty")
 }
 
+func TestToPortableDID_NonExportable(t *testing.T) {
+	bearerDID, err := didjwk.Create(didjwk.KeyManager(crypto.NewLocalKeyManager(crypto.NonExportable())))
+	assert.NoError(t, err)
+
+	_, err = bearerDID.ToPortableDID()
+	assert.IsError(t, err, crypto.ErrKeyNotExportable)
+
+	_, err = jws.Sign([]byte("hi"), bearerDID)
+	assert.NoError(t, err)
+}
+
 func TestFromPortableDID(t *testing.T) {
 	bearerDID, err := didjwk.Create()
 	assert.NoError(t, err)
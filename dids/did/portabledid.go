@@ -19,4 +19,7 @@ type PortableDID struct {
 	// Metadata is a map that can be used to store additional method specific data
 	// that is necessary to inflate a BearerDID from a PortableDID
 	Metadata map[string]interface{} `json:"metadata"`
+	// RotatedKeys are the keys that are retained for a grace period after being rotated.
+	// See [BearerDID.RotateKey]
+	RotatedKeys []RotatedKey `json:"rotatedKeys,omitempty"`
 }
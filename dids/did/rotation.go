@@ -0,0 +1,263 @@
+package did
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/tbd54566975/web5-go/crypto"
+	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/crypto/ecdh"
+	"github.com/tbd54566975/web5-go/dids/didcore"
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+// DefaultGracePeriod is how long [BearerDID.RotateKey] retains a replaced key if no [GracePeriod] is provided
+const DefaultGracePeriod = 30 * 24 * time.Hour
+
+// RotatedKey is a verification method that has been replaced by [BearerDID.RotateKey]. Until RetireAt, the
+// verification method remains in the DID Document without any purposes and its private key remains in the
+// key manager, so that signatures made and data encrypted for it before the rotation can still be verified
+// and decrypted.
+type RotatedKey struct {
+	// VerificationMethodID is the ID of the replaced verification method
+	VerificationMethodID string `json:"verificationMethodId"`
+	// SuccessorID is the ID of the verification method that replaced it
+	SuccessorID string `json:"successorId"`
+	// RetireAt is the time after which [BearerDID.RetireRotatedKeys] removes the key
+	RetireAt time.Time `json:"retireAt"`
+}
+
+// options that [BearerDID.RotateKey] can take
+type rotateKeyOpts struct {
+	algorithmID string
+	successorID string
+	gracePeriod time.Duration
+}
+
+// RotateKeyOpt is a type that represents an option that can be passed to [BearerDID.RotateKey]
+type RotateKeyOpt func(opts *rotateKeyOpts)
+
+// Algorithm is an option that can be passed to [BearerDID.RotateKey] to generate the successor key using
+// a different algorithm than the key being replaced
+func Algorithm(algorithmID string) RotateKeyOpt {
+	return func(opts *rotateKeyOpts) {
+		opts.algorithmID = algorithmID
+	}
+}
+
+// SuccessorID is an option that can be passed to [BearerDID.RotateKey] to set the ID of the successor's
+// verification method. Defaults to the DID URI followed by the successor key's JWK thumbprint as fragment.
+func SuccessorID(id string) RotateKeyOpt {
+	return func(opts *rotateKeyOpts) {
+		opts.successorID = id
+	}
+}
+
+// GracePeriod is an option that can be passed to [BearerDID.RotateKey] to set how long the replaced key
+// is retained. Defaults to [DefaultGracePeriod]
+func GracePeriod(gracePeriod time.Duration) RotateKeyOpt {
+	return func(opts *rotateKeyOpts) {
+		opts.gracePeriod = gracePeriod
+	}
+}
+
+// RotateKey replaces the key of the verification method with the given ID. A successor key is generated
+// using the BearerDID's key manager and added to the DID Document with the same purposes as the replaced
+// verification method, taking its place in the document. The replaced verification method is retained
+// without any purposes for a grace period (see [RotatedKey]) and then removed by [BearerDID.RetireRotatedKeys].
+//
+// RotateKey only updates the BearerDID. The updated DID Document has to be republished for the rotation to
+// take effect for others, e.g. using [github.com/tbd54566975/web5-go/dids/diddht.Publish] or
+// [github.com/tbd54566975/web5-go/dids/didweb.MarshalDocument].
+//
+// The successor's verification method is returned.
+func (d *BearerDID) RotateKey(vmID string, opts ...RotateKeyOpt) (didcore.VerificationMethod, error) {
+	o := rotateKeyOpts{gracePeriod: DefaultGracePeriod}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	switch {
+	case d.Method == "jwk":
+		return didcore.VerificationMethod{}, errors.New("did:jwk keys can't be rotated because the DID is derived from its key")
+	case d.Method == "dht" && d.Document.GetAbsoluteResourceID(vmID) == d.URI+"#0":
+		return didcore.VerificationMethod{}, errors.New("did:dht identity key can't be rotated because the DID is derived from it")
+	}
+
+	if slices.ContainsFunc(d.RotatedKeys, func(k RotatedKey) bool { return k.VerificationMethodID == vmID }) {
+		return didcore.VerificationMethod{}, fmt.Errorf("verification method %s has already been rotated", vmID)
+	}
+
+	vm, err := d.Document.SelectVerificationMethod(didcore.ID(vmID))
+	if err != nil {
+		return didcore.VerificationMethod{}, err
+	}
+
+	publicKey, err := vm.PublicKey()
+	if err != nil {
+		return didcore.VerificationMethod{}, err
+	}
+
+	if o.algorithmID == "" {
+		o.algorithmID, err = d.keyAlgorithmID(publicKey)
+		if err != nil {
+			return didcore.VerificationMethod{}, err
+		}
+	}
+
+	// check as much as possible before generating the successor key so that it isn't left behind in the
+	// key manager
+	if o.successorID != "" {
+		if _, err := d.Document.SelectVerificationMethod(didcore.ID(o.successorID)); err == nil {
+			return didcore.VerificationMethod{}, fmt.Errorf("verification method %s already exists", o.successorID)
+		}
+	}
+
+	keyID, err := d.GeneratePrivateKey(o.algorithmID)
+	if err != nil {
+		return didcore.VerificationMethod{}, fmt.Errorf("failed to generate successor key: %w", err)
+	}
+
+	successor, err := d.addSuccessor(vm, keyID, o.successorID)
+	if err != nil {
+		if deleter, ok := crypto.As[crypto.KeyDeleter](d.KeyManager); ok {
+			if deleteErr := deleter.DeleteKey(keyID); deleteErr != nil {
+				err = errors.Join(err, fmt.Errorf("failed to delete successor key: %w", deleteErr))
+			}
+		}
+
+		return didcore.VerificationMethod{}, err
+	}
+
+	d.RotatedKeys = append(d.RotatedKeys, RotatedKey{
+		VerificationMethodID: vm.ID,
+		SuccessorID:          successor.ID,
+		RetireAt:             time.Now().Add(o.gracePeriod),
+	})
+
+	return successor, nil
+}
+
+// addSuccessor replaces the given verification method with one for the key with the given key id and
+// retains the replaced verification method without any purposes
+func (d *BearerDID) addSuccessor(vm didcore.VerificationMethod, keyID string, successorID string) (didcore.VerificationMethod, error) {
+	successorKey, err := d.GetPublicKey(keyID)
+	if err != nil {
+		return didcore.VerificationMethod{}, fmt.Errorf("failed to get successor public key: %w", err)
+	}
+
+	if successorID == "" {
+		thumbprint, err := successorKey.ComputeThumbprint()
+		if err != nil {
+			return didcore.VerificationMethod{}, fmt.Errorf("failed to compute successor key thumbprint: %w", err)
+		}
+
+		successorID = d.URI + "#" + thumbprint
+
+		if _, err := d.Document.SelectVerificationMethod(didcore.ID(successorID)); err == nil {
+			return didcore.VerificationMethod{}, fmt.Errorf("verification method %s already exists", successorID)
+		}
+	}
+
+	successor := didcore.VerificationMethod{
+		ID:           successorID,
+		Type:         didcore.VerificationMethodTypeJSONWebKey,
+		Controller:   vm.Controller,
+		PublicKeyJwk: &successorKey,
+	}
+
+	if err := d.Document.ReplaceVerificationMethod(vm.ID, successor); err != nil {
+		return didcore.VerificationMethod{}, err
+	}
+
+	d.Document.AddVerificationMethod(vm)
+
+	return successor, nil
+}
+
+// RetireRotatedKeys removes all rotated keys whose grace period is over from the DID Document. Their private
+// keys are deleted from the key manager if it implements [crypto.KeyDeleter]. The retired keys are returned.
+// Keys that fail to be retired are kept and retried on the next call.
+//
+// Like [BearerDID.RotateKey], RetireRotatedKeys only updates the BearerDID. The updated DID Document has to be
+// republished afterwards.
+func (d *BearerDID) RetireRotatedKeys() ([]RotatedKey, error) {
+	now := time.Now()
+	retired := make([]RotatedKey, 0)
+	retained := make([]RotatedKey, 0, len(d.RotatedKeys))
+
+	var errs []error
+	for _, rotated := range d.RotatedKeys {
+		if now.Before(rotated.RetireAt) {
+			retained = append(retained, rotated)
+			continue
+		}
+
+		if err := d.retireKey(rotated.VerificationMethodID); err != nil {
+			// keep track of the key so that retiring it can be retried
+			retained = append(retained, rotated)
+			errs = append(errs, fmt.Errorf("failed to retire %s: %w", rotated.VerificationMethodID, err))
+			continue
+		}
+
+		retired = append(retired, rotated)
+	}
+
+	d.RotatedKeys = retained
+
+	return retired, errors.Join(errs...)
+}
+
+func (d *BearerDID) retireKey(vmID string) error {
+	idx := slices.IndexFunc(d.Document.VerificationMethod, func(vm didcore.VerificationMethod) bool { return vm.ID == vmID })
+	if idx == -1 {
+		// already removed from the document
+		return nil
+	}
+
+	// the private key is deleted before the verification method is removed, which is what the key alias is
+	// derived from. if deleting fails, the verification method is kept so that the deletion can be retried
+	if deleter, ok := crypto.As[crypto.KeyDeleter](d.KeyManager); ok {
+		publicKey, err := d.Document.VerificationMethod[idx].PublicKey()
+		if err != nil {
+			return err
+		}
+
+		keyAlias, err := publicKey.ComputeThumbprint()
+		if err != nil {
+			return fmt.Errorf("failed to compute key alias: %w", err)
+		}
+
+		if err := deleter.DeleteKey(keyAlias); err != nil {
+			return fmt.Errorf("failed to delete private key: %w", err)
+		}
+	}
+
+	return d.Document.RemoveVerificationMethod(vmID)
+}
+
+// keyAlgorithmID returns the algorithm of the given public key, preferring the key manager's metadata if available
+func (d *BearerDID) keyAlgorithmID(publicKey jwk.JWK) (string, error) {
+	if provider, ok := crypto.As[crypto.KeyMetadataProvider](d.KeyManager); ok {
+		keyAlias, err := publicKey.ComputeThumbprint()
+		if err != nil {
+			return "", fmt.Errorf("failed to compute key alias: %w", err)
+		}
+
+		if metadata, err := provider.KeyMetadata(keyAlias); err == nil && metadata.AlgorithmID != "" {
+			return metadata.AlgorithmID, nil
+		}
+	}
+
+	if algorithmID, err := dsa.AlgorithmID(&publicKey); err == nil {
+		return algorithmID, nil
+	}
+
+	if algorithmID, err := ecdh.AlgorithmID(&publicKey); err == nil {
+		return algorithmID, nil
+	}
+
+	return "", errors.New("unable to determine algorithm of the key to rotate. use the Algorithm option")
+}
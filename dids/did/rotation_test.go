@@ -0,0 +1,197 @@
+package did_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/crypto"
+	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/dids/did"
+	"github.com/tbd54566975/web5-go/dids/didcore"
+	"github.com/tbd54566975/web5-go/dids/didjwk"
+	"github.com/tbd54566975/web5-go/dids/didweb"
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+func TestRotateKey(t *testing.T) {
+	keyManager := crypto.NewLocalKeyManager()
+	bearerDID, err := didweb.Create(
+		"localhost:8080",
+		didweb.KeyManager(keyManager),
+		didweb.PrivateKey(dsa.AlgorithmIDSECP256K1, didcore.PurposeAssertion, didcore.PurposeAuthentication),
+	)
+	assert.NoError(t, err)
+
+	oldVM, err := bearerDID.Document.SelectVerificationMethod(didcore.PurposeAssertion)
+	assert.NoError(t, err)
+
+	successor, err := bearerDID.RotateKey(oldVM.ID)
+	assert.NoError(t, err)
+	assert.NotEqual(t, oldVM.ID, successor.ID)
+
+	algorithmID, err := dsa.AlgorithmID(successor.PublicKeyJwk)
+	assert.NoError(t, err)
+	assert.Equal(t, dsa.AlgorithmIDSECP256K1, algorithmID)
+
+	// the successor takes the place of the rotated verification method
+	document := bearerDID.Document
+	assert.Equal(t, 3, len(document.VerificationMethod))
+	assert.Equal(t, successor.ID, document.VerificationMethod[1].ID)
+	assert.Equal(t, oldVM.ID, document.VerificationMethod[2].ID)
+	assert.Equal(t, []didcore.Purpose{didcore.PurposeAssertion, didcore.PurposeAuthentication}, document.VerificationMethodPurposes(successor.ID))
+	assert.Equal(t, 0, len(document.VerificationMethodPurposes(oldVM.ID)))
+
+	sign, vm, err := bearerDID.GetSigner(didcore.PurposeAssertion)
+	assert.NoError(t, err)
+	assert.Equal(t, successor.ID, vm.ID)
+
+	signature, err := sign([]byte("hi"))
+	assert.NoError(t, err)
+
+	legit, err := dsa.Verify([]byte("hi"), signature, *successor.PublicKeyJwk)
+	assert.NoError(t, err)
+	assert.True(t, legit)
+
+	// the rotated key can still be used during the grace period
+	_, _, err = bearerDID.GetSigner(didcore.ID(oldVM.ID))
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, len(bearerDID.RotatedKeys))
+	assert.Equal(t, oldVM.ID, bearerDID.RotatedKeys[0].VerificationMethodID)
+	assert.Equal(t, successor.ID, bearerDID.RotatedKeys[0].SuccessorID)
+
+	retired, err := bearerDID.RetireRotatedKeys()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(retired))
+
+	_, err = bearerDID.RotateKey(oldVM.ID)
+	assert.Error(t, err)
+}
+
+func TestRetireRotatedKeys(t *testing.T) {
+	keyManager := crypto.NewLocalKeyManager()
+	bearerDID, err := didweb.Create("localhost:8080", didweb.KeyManager(keyManager))
+	assert.NoError(t, err)
+
+	oldVM := bearerDID.Document.VerificationMethod[0]
+
+	_, err = bearerDID.RotateKey(oldVM.ID, did.GracePeriod(0))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(keyManager.ListKeys()))
+
+	retired, err := bearerDID.RetireRotatedKeys()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(retired))
+	assert.Equal(t, oldVM.ID, retired[0].VerificationMethodID)
+
+	assert.Equal(t, 0, len(bearerDID.RotatedKeys))
+	assert.Equal(t, 1, len(bearerDID.Document.VerificationMethod))
+	assert.Equal(t, 1, len(keyManager.ListKeys()))
+
+	_, err = bearerDID.Document.SelectVerificationMethod(didcore.ID(oldVM.ID))
+	assert.Error(t, err)
+}
+
+// flakyKeyDeleter fails to delete keys while fail is set
+type flakyKeyDeleter struct {
+	*crypto.LocalKeyManager
+	fail bool
+}
+
+func (k *flakyKeyDeleter) DeleteKey(keyID string) error {
+	if k.fail {
+		return errors.New("key store unavailable")
+	}
+
+	return k.LocalKeyManager.DeleteKey(keyID)
+}
+
+func TestRetireRotatedKeys_Retry(t *testing.T) {
+	keyManager := &flakyKeyDeleter{LocalKeyManager: crypto.NewLocalKeyManager(), fail: true}
+	bearerDID, err := didweb.Create("localhost:8080", didweb.KeyManager(keyManager))
+	assert.NoError(t, err)
+
+	oldVM := bearerDID.Document.VerificationMethod[0]
+
+	_, err = bearerDID.RotateKey(oldVM.ID, did.GracePeriod(0))
+	assert.NoError(t, err)
+
+	retired, err := bearerDID.RetireRotatedKeys()
+	assert.Error(t, err)
+	assert.Equal(t, 0, len(retired))
+
+	// the verification method is kept until its private key has been deleted
+	assert.Equal(t, 1, len(bearerDID.RotatedKeys))
+	assert.Equal(t, 2, len(bearerDID.Document.VerificationMethod))
+	assert.Equal(t, 2, len(keyManager.ListKeys()))
+
+	keyManager.fail = false
+
+	retired, err = bearerDID.RetireRotatedKeys()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(retired))
+	assert.Equal(t, 0, len(bearerDID.RotatedKeys))
+	assert.Equal(t, 1, len(bearerDID.Document.VerificationMethod))
+	assert.Equal(t, 1, len(keyManager.ListKeys()))
+}
+
+// flakyPublicKeys is a key manager that fails to return public keys when fail is set
+type flakyPublicKeys struct {
+	*crypto.LocalKeyManager
+	fail bool
+}
+
+func (k *flakyPublicKeys) GetPublicKey(keyID string) (jwk.JWK, error) {
+	if k.fail {
+		return jwk.JWK{}, errors.New("key manager unavailable")
+	}
+
+	return k.LocalKeyManager.GetPublicKey(keyID)
+}
+
+func TestRotateKey_Failure(t *testing.T) {
+	keyManager := &flakyPublicKeys{LocalKeyManager: crypto.NewLocalKeyManager()}
+	bearerDID, err := didweb.Create("localhost:8080", didweb.KeyManager(keyManager))
+	assert.NoError(t, err)
+
+	vm := bearerDID.Document.VerificationMethod[0]
+
+	// an existing successor ID is rejected before a key is generated
+	_, err = bearerDID.RotateKey(vm.ID, did.SuccessorID(vm.ID))
+	assert.Error(t, err)
+	assert.Equal(t, 1, len(keyManager.ListKeys()))
+
+	// a successor key is deleted if the rotation fails after generating it
+	keyManager.fail = true
+
+	_, err = bearerDID.RotateKey(vm.ID)
+	assert.Error(t, err)
+	assert.Equal(t, 1, len(keyManager.ListKeys()))
+	assert.Equal(t, 1, len(bearerDID.Document.VerificationMethod))
+	assert.Equal(t, 0, len(bearerDID.RotatedKeys))
+}
+
+func TestRotateKey_PortableDID(t *testing.T) {
+	bearerDID, err := didweb.Create("localhost:8080")
+	assert.NoError(t, err)
+
+	_, err = bearerDID.RotateKey(bearerDID.Document.VerificationMethod[0].ID)
+	assert.NoError(t, err)
+
+	portableDID, err := bearerDID.ToPortableDID()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(portableDID.PrivateKeys))
+
+	importedDID, err := did.FromPortableDID(portableDID)
+	assert.NoError(t, err)
+	assert.Equal(t, bearerDID.RotatedKeys, importedDID.RotatedKeys)
+}
+
+func TestRotateKey_DIDJWK(t *testing.T) {
+	bearerDID, err := didjwk.Create()
+	assert.NoError(t, err)
+
+	_, err = bearerDID.RotateKey(bearerDID.Document.VerificationMethod[0].ID)
+	assert.Error(t, err)
+}
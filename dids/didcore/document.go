@@ -3,7 +3,10 @@ package didcore
 import (
 	"errors"
 	"fmt"
+	"slices"
 
+	"github.com/tbd54566975/web5-go/crypto/dsa/eddsa"
+	"github.com/tbd54566975/web5-go/crypto/multikey"
 	"github.com/tbd54566975/web5-go/jwk"
 )
 
@@ -15,6 +18,14 @@ const (
 	PurposeKeyAgreement         Purpose = "keyAgreement"
 )
 
+// Verification method types. See https://www.w3.org/TR/did-spec-registries/#verification-method-types
+const (
+	VerificationMethodTypeJSONWebKey                 = "JsonWebKey"
+	VerificationMethodTypeJSONWebKey2020             = "JsonWebKey2020"
+	VerificationMethodTypeMultikey                   = "Multikey"
+	VerificationMethodTypeEd25519VerificationKey2020 = "Ed25519VerificationKey2020"
+)
+
 // Document represents a set of data describing the DID subject including mechanisms such as:
 //   - cryptographic public keys - used to authenticate itself and prove
 //     association with the DID
@@ -111,6 +122,73 @@ func (d *Document) AddVerificationMethod(method VerificationMethod, opts ...AddV
 	}
 }
 
+// VerificationMethodPurposes returns the purposes the verification method with the given ID is referenced by
+func (d *Document) VerificationMethodPurposes(id string) []Purpose {
+	purposes := make([]Purpose, 0)
+	for _, p := range d.purposeLists() {
+		if slices.Contains(*p.ids, id) {
+			purposes = append(purposes, p.purpose)
+		}
+	}
+
+	return purposes
+}
+
+// ReplaceVerificationMethod replaces the verification method with the given ID with the provided
+// verification method. The replacement takes the place of the original in the list of verification
+// methods and in every purpose the original was referenced by.
+func (d *Document) ReplaceVerificationMethod(id string, method VerificationMethod) error {
+	idx := slices.IndexFunc(d.VerificationMethod, func(vm VerificationMethod) bool { return vm.ID == id })
+	if idx == -1 {
+		return fmt.Errorf("no verification method found for id: %s", id)
+	}
+
+	d.VerificationMethod[idx] = method
+
+	for _, p := range d.purposeLists() {
+		for i, vmID := range *p.ids {
+			if vmID == id {
+				(*p.ids)[i] = method.ID
+			}
+		}
+	}
+
+	return nil
+}
+
+// RemoveVerificationMethod removes the verification method with the given ID from the document,
+// along with every purpose that references it
+func (d *Document) RemoveVerificationMethod(id string) error {
+	idx := slices.IndexFunc(d.VerificationMethod, func(vm VerificationMethod) bool { return vm.ID == id })
+	if idx == -1 {
+		return fmt.Errorf("no verification method found for id: %s", id)
+	}
+
+	d.VerificationMethod = slices.Delete(d.VerificationMethod, idx, idx+1)
+
+	for _, p := range d.purposeLists() {
+		*p.ids = slices.DeleteFunc(*p.ids, func(vmID string) bool { return vmID == id })
+	}
+
+	return nil
+}
+
+type purposeList struct {
+	purpose Purpose
+	ids     *[]string
+}
+
+// purposeLists returns the document's verification relationships, keyed by purpose
+func (d *Document) purposeLists() []purposeList {
+	return []purposeList{
+		{PurposeAssertion, &d.AssertionMethod},
+		{PurposeAuthentication, &d.Authentication},
+		{PurposeKeyAgreement, &d.KeyAgreement},
+		{PurposeCapabilityDelegation, &d.CapabilityDelegation},
+		{PurposeCapabilityInvocation, &d.CapabilityInvocation},
+	}
+}
+
 // VMSelector is an interface that can be implemented to provide a means to select
 // a specific verification method from a DID Document.
 type VMSelector interface {
@@ -294,4 +372,30 @@ type VerificationMethod struct {
 	Controller string `json:"controller"`
 	// specification reference: https://www.w3.org/TR/did-core/#dfn-publickeyjwk
 	PublicKeyJwk *jwk.JWK `json:"publicKeyJwk,omitempty"`
+	// a multicodec prefixed, multibase encoded public key as used by Multikey and Ed25519VerificationKey2020
+	// verification methods. specification reference: https://www.w3.org/TR/did-core/#dfn-publickeymultibase
+	PublicKeyMultibase string `json:"publicKeyMultibase,omitempty"`
+}
+
+// PublicKey returns the public key of the verification method as a JWK regardless of whether it is
+// expressed as publicKeyJwk or publicKeyMultibase
+func (vm VerificationMethod) PublicKey() (jwk.JWK, error) {
+	if vm.PublicKeyJwk != nil {
+		return *vm.PublicKeyJwk, nil
+	}
+
+	if vm.PublicKeyMultibase == "" {
+		return jwk.JWK{}, fmt.Errorf("verification method %s does not contain a public key", vm.ID)
+	}
+
+	publicKey, err := multikey.MultibaseToPublicKey(vm.PublicKeyMultibase)
+	if err != nil {
+		return jwk.JWK{}, fmt.Errorf("failed to decode publicKeyMultibase of verification method %s: %w", vm.ID, err)
+	}
+
+	if vm.Type == VerificationMethodTypeEd25519VerificationKey2020 && publicKey.CRV != eddsa.ED25519JWACurve {
+		return jwk.JWK{}, fmt.Errorf("verification method %s of type %s contains a %s key", vm.ID, vm.Type, publicKey.CRV)
+	}
+
+	return publicKey, nil
 }
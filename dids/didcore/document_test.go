@@ -41,3 +41,68 @@ func TestWoo(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "did:example:123456789abcdefghi#keys-1", vm.ID)
 }
+
+func TestReplaceVerificationMethod(t *testing.T) {
+	doc := didcore.Document{ID: "did:example:123"}
+	doc.AddVerificationMethod(didcore.VerificationMethod{ID: "did:example:123#0"}, didcore.Purposes(didcore.PurposeAuthentication, didcore.PurposeAssertion))
+	doc.AddVerificationMethod(didcore.VerificationMethod{ID: "did:example:123#1"}, didcore.Purposes(didcore.PurposeKeyAgreement))
+
+	err := doc.ReplaceVerificationMethod("did:example:123#0", didcore.VerificationMethod{ID: "did:example:123#2"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "did:example:123#2", doc.VerificationMethod[0].ID)
+	assert.Equal(t, []string{"did:example:123#2"}, doc.Authentication)
+	assert.Equal(t, []string{"did:example:123#2"}, doc.AssertionMethod)
+	assert.Equal(t, []didcore.Purpose{didcore.PurposeAssertion, didcore.PurposeAuthentication}, doc.VerificationMethodPurposes("did:example:123#2"))
+	assert.Equal(t, 0, len(doc.VerificationMethodPurposes("did:example:123#0")))
+
+	err = doc.ReplaceVerificationMethod("did:example:123#0", didcore.VerificationMethod{ID: "did:example:123#3"})
+	assert.Error(t, err)
+}
+
+func TestRemoveVerificationMethod(t *testing.T) {
+	doc := didcore.Document{ID: "did:example:123"}
+	doc.AddVerificationMethod(didcore.VerificationMethod{ID: "did:example:123#0"}, didcore.Purposes(didcore.PurposeAuthentication))
+	doc.AddVerificationMethod(didcore.VerificationMethod{ID: "did:example:123#1"}, didcore.Purposes(didcore.PurposeAuthentication))
+
+	err := doc.RemoveVerificationMethod("did:example:123#0")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, len(doc.VerificationMethod))
+	assert.Equal(t, []string{"did:example:123#1"}, doc.Authentication)
+
+	err = doc.RemoveVerificationMethod("did:example:123#0")
+	assert.Error(t, err)
+}
+
+func TestVerificationMethodPublicKey_Multibase(t *testing.T) {
+	vm := didcore.VerificationMethod{
+		ID:                 "did:example:123#key-1",
+		Type:               didcore.VerificationMethodTypeEd25519VerificationKey2020,
+		Controller:         "did:example:123",
+		PublicKeyMultibase: "z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp",
+	}
+
+	publicKey, err := vm.PublicKey()
+	assert.NoError(t, err)
+	assert.Equal(t, "OKP", publicKey.KTY)
+	assert.Equal(t, "Ed25519", publicKey.CRV)
+
+	vm.Type = didcore.VerificationMethodTypeMultikey
+	vm.PublicKeyMultibase = "z6LSeu9HkTHSfLLeUs2nnzUSNedgDUevfNQgQjQC23ZCit6F"
+
+	publicKey, err = vm.PublicKey()
+	assert.NoError(t, err)
+	assert.Equal(t, "X25519", publicKey.CRV)
+
+	vm.Type = didcore.VerificationMethodTypeEd25519VerificationKey2020
+	_, err = vm.PublicKey()
+	assert.Error(t, err)
+}
+
+func TestVerificationMethodPublicKey_Missing(t *testing.T) {
+	vm := didcore.VerificationMethod{ID: "did:example:123#key-1", Type: didcore.VerificationMethodTypeMultikey}
+
+	_, err := vm.PublicKey()
+	assert.Error(t, err)
+}
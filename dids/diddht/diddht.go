@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"sync"
@@ -54,6 +55,7 @@ type createOptions struct {
 	alsoKnownAs []string
 	controllers []string
 	gateway     gateway
+	rand        io.Reader
 }
 
 // verificationMethodOption is a struct to hold options for creating a new private key.
@@ -107,6 +109,15 @@ func KeyManager(km crypto.KeyManager) CreateOption {
 	}
 }
 
+// EntropySource is used to set the source of randomness used to generate the DID's keys
+// when no [KeyManager] is provided. Providing a deterministic reader results in the same DID every
+// time, which makes this useful for reproducible test fixtures.
+func EntropySource(rand io.Reader) CreateOption {
+	return func(o *createOptions) {
+		o.rand = rand
+	}
+}
+
 // AlsoKnownAs is used to set the 'alsoKnownAs' property of the DID Document.
 // more details here: https://www.w3.org/TR/did-core/#also-known-as
 func AlsoKnownAs(aka ...string) CreateOption {
@@ -145,7 +156,6 @@ func CreateWithContext(ctx context.Context, opts ...CreateOption) (did.BearerDID
 	// 0. Set default options
 	o := createOptions{
 		gateway:     getDefaultGateway(),
-		keyManager:  crypto.NewLocalKeyManager(),
 		privateKeys: []verificationMethodOption{},
 	}
 
@@ -153,6 +163,10 @@ func CreateWithContext(ctx context.Context, opts ...CreateOption) (did.BearerDID
 		opt(&o)
 	}
 
+	if o.keyManager == nil {
+		o.keyManager = crypto.NewLocalKeyManager(crypto.EntropySource(o.rand))
+	}
+
 	if o.gateway == nil {
 		return did.BearerDID{}, errors.New("no gateway provided")
 	}
@@ -198,7 +212,7 @@ func CreateWithContext(ctx context.Context, opts ...CreateOption) (did.BearerDID
 	// create identitfier verification method
 	identifierVM := didcore.VerificationMethod{
 		ID:           "did:dht:" + zbase32Encoded + "#0",
-		Type:         "JsonWebKey",
+		Type:         didcore.VerificationMethodTypeJSONWebKey,
 		Controller:   "did:dht:" + zbase32Encoded,
 		PublicKeyJwk: &publicKey,
 	}
@@ -239,7 +253,7 @@ func CreateWithContext(ctx context.Context, opts ...CreateOption) (did.BearerDID
 		vmZbase32Encoded := zbase32.EncodeToString(vmPublicKeyBytes)
 		newVM := didcore.VerificationMethod{
 			ID:           "did:dht:" + vmZbase32Encoded + "#" + vmKeyID,
-			Type:         "JsonWebKey",
+			Type:         didcore.VerificationMethodTypeJSONWebKey,
 			Controller:   controller,
 			PublicKeyJwk: &vmPublicKey,
 		}
@@ -251,29 +265,128 @@ func CreateWithContext(ctx context.Context, opts ...CreateOption) (did.BearerDID
 		document.AddService(service)
 	}
 
-	// 5. Map the output DID Document to a DNS packet
-	msgBytes, err := dns.MarshalDIDDocument(&document)
+	bdid.Document = document
+
+	// 5. - 7. Map the DID Document to a DNS packet, sign it and publish it
+	if err := publish(ctx, o.gateway, bdid); err != nil {
+		return did.BearerDID{}, err
+	}
+
+	return bdid, nil
+}
+
+// PublishOption is the type returned from each individual option function that can be passed to [Publish]
+type PublishOption func(*publishOptions)
+
+// publishOptions is a struct to hold options for publishing a 'did:dht' DID Document
+type publishOptions struct {
+	gateway gateway
+}
+
+// PublishGateway sets the gateway to use for publishing the DID Document to the DHT. If no gateway is passed,
+// [Publish] uses the default Pkarr gateway.
+func PublishGateway(gatewayURL string, client *http.Client) PublishOption {
+	return func(o *publishOptions) {
+		o.gateway = pkarr.NewClient(gatewayURL, client)
+	}
+}
+
+// Publish publishes the DID Document of the given `did:dht` BearerDID to the DHT network via a Pkarr
+// gateway, replacing the previously published document. Use this to republish a DID Document after
+// updating it, e.g. after rotating a key with [did.BearerDID.RotateKey]. The document is signed with the
+// DID's identity key, which must still be present in the BearerDID's key manager.
+func Publish(bdid did.BearerDID, opts ...PublishOption) error {
+	return PublishWithContext(context.Background(), bdid, opts...)
+}
+
+// PublishWithContext publishes the DID Document of the given `did:dht` BearerDID to the DHT network via a
+// Pkarr gateway. This is the context aware version of [Publish].
+func PublishWithContext(ctx context.Context, bdid did.BearerDID, opts ...PublishOption) error {
+	o := publishOptions{gateway: getDefaultGateway()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.gateway == nil {
+		return errors.New("no gateway provided")
+	}
+
+	if bdid.Method != "dht" {
+		return fmt.Errorf("expected did:dht, got did:%s", bdid.Method)
+	}
+
+	return publish(ctx, o.gateway, bdid)
+}
+
+// lastSeq holds the sequence number of the last message published for DIDs that have published a message
+// within the current second. See [nextSeq]
+var (
+	lastSeq   = map[string]int64{}
+	lastSeqMu sync.Mutex
+)
+
+// nextSeq returns the sequence number for the next message published for the given DID. The sequence number
+// has to increase with every update, hence the timestamp in seconds, unless a message has already been
+// published for the DID within the same second.
+func nextSeq(didID string) int64 {
+	lastSeqMu.Lock()
+	defer lastSeqMu.Unlock()
+
+	now := time.Now().Unix()
+
+	// sequence numbers older than the current timestamp no longer affect the next sequence number, so they're
+	// evicted to keep the map from growing with every DID ever published
+	for id, seq := range lastSeq {
+		if seq < now {
+			delete(lastSeq, id)
+		}
+	}
+
+	seq := max(lastSeq[didID]+1, now)
+	lastSeq[didID] = seq
+
+	return seq
+}
+
+// publish signs the BearerDID's document with its identity key and submits it to the given gateway
+func publish(ctx context.Context, gw gateway, bdid did.BearerDID) error {
+	publicKeyBytes, err := zbase32.DecodeString(bdid.ID)
 	if err != nil {
-		return did.BearerDID{}, fmt.Errorf("failed to marshal did document to dns packet: %w", err)
+		return fmt.Errorf("failed to decode identity key: %w", err)
 	}
 
-	// 6. Construct a signed BEP44 put message with the v value as a bencoded DNS packet from the prior step.
-	seq := time.Now().Unix() / 1000
+	identityKey, err := dsa.BytesToPublicKey(dsa.AlgorithmIDED25519, publicKeyBytes)
+	if err != nil {
+		return fmt.Errorf("failed to decode identity key: %w", err)
+	}
+
+	keyID, err := identityKey.ComputeThumbprint()
+	if err != nil {
+		return fmt.Errorf("failed to compute identity key alias: %w", err)
+	}
+
+	// Map the DID Document to a DNS packet
+	msgBytes, err := dns.MarshalDIDDocument(&bdid.Document)
+	if err != nil {
+		return fmt.Errorf("failed to marshal did document to dns packet: %w", err)
+	}
+
+	// Construct a signed BEP44 put message with the v value as a bencoded DNS packet from the prior step.
+	seq := nextSeq(bdid.ID)
 
 	signer := func(payload []byte) ([]byte, error) {
-		return keyMgr.Sign(keyID, payload)
+		return bdid.Sign(keyID, payload)
 	}
 
 	bep44Msg, err := bep44.NewMessage(msgBytes, seq, publicKeyBytes, signer)
 	if err != nil {
-		return did.BearerDID{}, fmt.Errorf("failed to create signed bep44 message: %w", err)
+		return fmt.Errorf("failed to create signed bep44 message: %w", err)
 	}
 
-	// 7. Submit the result of to the DHT via a Pkarr relay, or a Gateway, with the identifier created in step 1.
-	if err := o.gateway.PutWithContext(ctx, bdid.ID, bep44Msg); err != nil {
-		return did.BearerDID{}, fmt.Errorf("failed to punlish bep44 message to relay: %w", err)
+	// Submit the result to the DHT via a Pkarr relay, or a Gateway, with the identifier
+	if err := gw.PutWithContext(ctx, bdid.ID, bep44Msg); err != nil {
+		return fmt.Errorf("failed to publish bep44 message to relay: %w", err)
 	}
 
-	bdid.Document = document
-	return bdid, nil
+	return nil
 }
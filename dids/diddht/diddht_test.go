@@ -275,3 +275,129 @@ func TestCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestPublish(t *testing.T) {
+	relay := newFakeRelay(t)
+	defer relay.Close()
+
+	bearerDID, err := Create(
+		Gateway(relay.URL, http.DefaultClient),
+		PrivateKey(dsa.AlgorithmIDSECP256K1, didcore.PurposeAssertion),
+	)
+	assert.NoError(t, err)
+
+	// the identity key can't be rotated
+	_, err = bearerDID.RotateKey(bearerDID.URI + "#0")
+	assert.Error(t, err)
+
+	successor, err := bearerDID.RotateKey(bearerDID.Document.VerificationMethod[1].ID)
+	assert.NoError(t, err)
+
+	// published within the same second as the DID was created
+	err = Publish(bearerDID, PublishGateway(relay.URL, http.DefaultClient))
+	assert.NoError(t, err)
+
+	resolver := NewResolver(relay.URL, http.DefaultClient)
+	result, err := resolver.Resolve(bearerDID.URI)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(result.Document.VerificationMethod))
+
+	vm, err := result.Document.SelectVerificationMethod(didcore.ID(successor.ID))
+	assert.NoError(t, err)
+	assert.Equal(t, successor.PublicKeyJwk.X, vm.PublicKeyJwk.X)
+	assert.Equal(t, successor.PublicKeyJwk.Y, vm.PublicKeyJwk.Y)
+	assert.Equal(t, []didcore.Purpose{didcore.PurposeAssertion}, result.Document.VerificationMethodPurposes(successor.ID))
+}
+
+func TestNextSeq(t *testing.T) {
+	lastSeqMu.Lock()
+	lastSeq["stale"] = 1
+	lastSeqMu.Unlock()
+
+	first := nextSeq("did")
+	second := nextSeq("did")
+	assert.True(t, second > first)
+
+	lastSeqMu.Lock()
+	defer lastSeqMu.Unlock()
+
+	// sequence numbers that no longer matter are evicted
+	_, ok := lastSeq["stale"]
+	assert.False(t, ok)
+	assert.Equal(t, second, lastSeq["did"])
+}
+
+func TestCreate_SigningMiddleware(t *testing.T) {
+	relay := newFakeRelay(t)
+	defer relay.Close()
+
+	var signed []string
+	keyMgr := crypto.NewSigningMiddleware(
+		crypto.NewLocalKeyManager(),
+		crypto.PostSign(func(req crypto.SignRequest, signature []byte, err error) {
+			assert.NoError(t, err)
+			signed = append(signed, req.KeyID)
+		}),
+	)
+
+	bearerDID, err := Create(Gateway(relay.URL, http.DefaultClient), KeyManager(keyMgr))
+	assert.NoError(t, err)
+
+	// the bep44 message is signed with the identity key through the middleware
+	identityKey, err := bearerDID.Document.VerificationMethod[0].PublicKeyJwk.ComputeThumbprint()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{identityKey}, signed)
+
+	resolver := NewResolver(relay.URL, http.DefaultClient)
+	result, err := resolver.Resolve(bearerDID.URI)
+	assert.NoError(t, err)
+	assert.Equal(t, bearerDID.URI, result.Document.ID)
+
+	// capabilities of the wrapped key manager are still found
+	portableDID, err := bearerDID.ToPortableDID()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(portableDID.PrivateKeys))
+}
+
+// newFakeRelay returns a pkarr relay that stores the latest bep44 message published for every DID. Like a
+// real relay, it rejects messages whose sequence number isn't greater than the stored message's
+func newFakeRelay(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	published := map[string][]byte{}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+
+		if r.Method != http.MethodGet {
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+
+			var msg bep44.Message
+			assert.NoError(t, bep44.UnmarshalMessage(body, &msg))
+
+			if prev, ok := published[r.URL.Path[1:]]; ok {
+				var prevMsg bep44.Message
+				assert.NoError(t, bep44.UnmarshalMessage(prev, &prevMsg))
+
+				if msg.Seq <= prevMsg.Seq {
+					http.Error(w, "sequence number must increase", http.StatusConflict)
+					return
+				}
+			}
+
+			published[r.URL.Path[1:]] = body
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		body, ok := published[r.URL.Path[1:]]
+		if !ok {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+
+		_, err := w.Write(body)
+		assert.NoError(t, err)
+	}))
+}
@@ -9,7 +9,9 @@ import (
 	"strings"
 
 	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/crypto/ecdh"
 	"github.com/tbd54566975/web5-go/dids/didcore"
+	"github.com/tbd54566975/web5-go/jwk"
 	"golang.org/x/net/dns/dnsmessage"
 )
 
@@ -124,17 +126,15 @@ func UnmarshalDIDDocument(payload []byte) (*didcore.Document, error) {
 	return doc, nil
 }
 
-// MarshalVerificationMethod packs a verification method into a TXT DNS resource record and adds to the DNS message Answers
+// MarshalVerificationMethod packs a verification method into a TXT DNS resource record and adds to the DNS message Answers.
+// Only the key material of the JWK is packed. Members like kid, use or key_ops have no representation in the
+// record and are dropped; the fragment of the verification method's id identifies the key instead
 func MarshalVerificationMethod(vm *didcore.VerificationMethod) (string, error) {
-	keyBytes, err := dsa.PublicKeyToBytes(*vm.PublicKeyJwk)
+	algID, keyBytes, err := publicKeyToBytes(vm.PublicKeyJwk)
 	if err != nil {
 		return "", err
 	}
 
-	algID, err := dsa.AlgorithmID(vm.PublicKeyJwk)
-	if err != nil {
-		return "", err
-	}
 	t, ok := algToDhtIndex[algID]
 	if !ok {
 		return "", errors.New("unsupported algorithm")
@@ -183,7 +183,7 @@ func UnmarshalVerificationMethod(data string, did string, vm *didcore.Verificati
 		return err
 	}
 
-	vm.Type = "JsonWebKey"
+	vm.Type = didcore.VerificationMethodTypeJSONWebKey
 
 	var key string
 	var algorithmID string
@@ -223,7 +223,7 @@ func UnmarshalVerificationMethod(data string, did string, vm *didcore.Verificati
 		return errors.New("malformed public key")
 	}
 
-	j, err := dsa.BytesToPublicKey(algorithmID, keyBytes)
+	j, err := bytesToPublicKey(algorithmID, keyBytes)
 	if err != nil {
 		return err
 	}
@@ -266,6 +266,32 @@ func UnmarshalService(data string, s *didcore.Service) error {
 	return nil
 }
 
+// publicKeyToBytes returns the algorithm ID and byte representation of the given public key. signing keys
+// are handled by dsa whereas keys that can only be used for key agreement (e.g. X25519) are handled by ecdh
+func publicKeyToBytes(publicKey *jwk.JWK) (string, []byte, error) {
+	if algID, err := dsa.AlgorithmID(publicKey); err == nil {
+		keyBytes, err := dsa.PublicKeyToBytes(*publicKey)
+		return algID, keyBytes, err
+	}
+
+	algID, err := ecdh.AlgorithmID(publicKey)
+	if err != nil {
+		return "", nil, err
+	}
+
+	keyBytes, err := ecdh.PublicKeyToBytes(*publicKey)
+	return algID, keyBytes, err
+}
+
+// bytesToPublicKey is the inverse of publicKeyToBytes
+func bytesToPublicKey(algorithmID string, input []byte) (jwk.JWK, error) {
+	if algorithmID == ecdh.X25519AlgorithmID {
+		return ecdh.BytesToPublicKey(algorithmID, input)
+	}
+
+	return dsa.BytesToPublicKey(algorithmID, input)
+}
+
 func pluckSort(hayStack []didcore.VerificationMethod) []string {
 	var ids []string
 	for _, v := range hayStack {
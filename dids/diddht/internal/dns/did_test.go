@@ -6,6 +6,7 @@ import (
 
 	"github.com/alecthomas/assert/v2"
 	"github.com/tbd54566975/web5-go/dids/didcore"
+	"github.com/tbd54566975/web5-go/jwk"
 )
 
 func Test_MarshalDIDDocument(t *testing.T) {
@@ -52,5 +53,54 @@ func Test_MarshalDIDDocument(t *testing.T) {
 	reParsedDoc, err := rec.DIDDocument()
 	assert.NoError(t, err)
 	assert.NotZero(t, reParsedDoc)
+
+	// the kid can't be represented in the DNS packet. the verification method's id identifies the key instead
+	assert.Zero(t, reParsedDoc.VerificationMethod[0].PublicKeyJwk.KID)
+	didDoc.VerificationMethod[0].PublicKeyJwk.KID = ""
 	assert.Equal(t, &didDoc, reParsedDoc)
 }
+
+func Test_MarshalVerificationMethod_SECP256R1(t *testing.T) {
+	vm := didcore.VerificationMethod{
+		ID:         "did:dht:cwxob5rbhhu3z9x3gfqy6cthqgm6ngrh4k8s615n7pw11czoq4fy#1",
+		Type:       "JsonWebKey",
+		Controller: "did:dht:cwxob5rbhhu3z9x3gfqy6cthqgm6ngrh4k8s615n7pw11czoq4fy",
+		PublicKeyJwk: &jwk.JWK{
+			KTY: "EC",
+			CRV: "P-256",
+			X:   "f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU",
+			Y:   "x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0",
+		},
+	}
+
+	txt, err := MarshalVerificationMethod(&vm)
+	assert.NoError(t, err)
+	assert.Contains(t, txt, "t=2")
+
+	var reParsed didcore.VerificationMethod
+	err = UnmarshalVerificationMethod(txt, "did:dht:cwxob5rbhhu3z9x3gfqy6cthqgm6ngrh4k8s615n7pw11czoq4fy", &reParsed)
+	assert.NoError(t, err)
+	assert.Equal(t, vm, reParsed)
+}
+
+func Test_MarshalVerificationMethod_X25519(t *testing.T) {
+	vm := didcore.VerificationMethod{
+		ID:         "did:dht:cwxob5rbhhu3z9x3gfqy6cthqgm6ngrh4k8s615n7pw11czoq4fy#1",
+		Type:       "JsonWebKey",
+		Controller: "did:dht:cwxob5rbhhu3z9x3gfqy6cthqgm6ngrh4k8s615n7pw11czoq4fy",
+		PublicKeyJwk: &jwk.JWK{
+			KTY: "OKP",
+			CRV: "X25519",
+			X:   "hSDwCYkwp1R0i33ctD73Wg2_Og0mOBr066SpjqqbTmo",
+		},
+	}
+
+	txt, err := MarshalVerificationMethod(&vm)
+	assert.NoError(t, err)
+	assert.Contains(t, txt, "t=3")
+
+	var reParsed didcore.VerificationMethod
+	err = UnmarshalVerificationMethod(txt, "did:dht:cwxob5rbhhu3z9x3gfqy6cthqgm6ngrh4k8s615n7pw11czoq4fy", &reParsed)
+	assert.NoError(t, err)
+	assert.Equal(t, vm, reParsed)
+}
@@ -144,13 +144,9 @@ func parseVerificationRelationships(rootRecord string) (map[string][]string, err
 	// reverse the map to get the relationships
 	var relationshipMap = make(map[string][]string)
 	for k, values := range rootRecordProps {
-		v := strings.Join(values, "")
-		rel, ok := relationshipMap[v]
-		if !ok {
-			rel = []string{}
+		for _, v := range values {
+			relationshipMap[v] = append(relationshipMap[v], k)
 		}
-		rel = append(rel, k)
-		relationshipMap[v] = rel
 	}
 
 	return relationshipMap, nil
@@ -2,6 +2,7 @@ package dns
 
 import (
 	"reflect"
+	"slices"
 	"testing"
 
 	"github.com/alecthomas/assert"
@@ -80,3 +81,16 @@ func Test_parseDNSDID(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseVerificationRelationships(t *testing.T) {
+	relationships, err := parseVerificationRelationships("vm=k0,k1,k2;auth=k0;asm=k0,k1")
+	assert.NoError(t, err)
+
+	for _, rel := range relationships {
+		slices.Sort(rel)
+	}
+
+	assert.Equal(t, []string{"asm", "auth", "vm"}, relationships["k0"])
+	assert.Equal(t, []string{"asm", "vm"}, relationships["k1"])
+	assert.Equal(t, []string{"vm"}, relationships["k2"])
+}
@@ -2,6 +2,7 @@ package dns
 
 import (
 	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/crypto/ecdh"
 	"github.com/tbd54566975/web5-go/dids/didcore"
 )
 
@@ -24,6 +25,8 @@ var vmPurposeDNStoDID = map[string]didcore.Purpose{
 var dhtIndexToAlg = map[string]string{
 	"0": dsa.AlgorithmIDED25519,
 	"1": dsa.AlgorithmIDSECP256K1,
+	"2": dsa.AlgorithmIDSECP256R1,
+	"3": ecdh.X25519AlgorithmID,
 }
 
 // algToDhtIndex maps the DID representation of the key type (algorithm)
@@ -33,4 +36,6 @@ var dhtIndexToAlg = map[string]string{
 var algToDhtIndex = map[string]string{
 	dsa.AlgorithmIDED25519:   "0",
 	dsa.AlgorithmIDSECP256K1: "1",
+	dsa.AlgorithmIDSECP256R1: "2",
+	ecdh.X25519AlgorithmID:   "3",
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 
 	"github.com/tbd54566975/web5-go/crypto"
 	"github.com/tbd54566975/web5-go/crypto/dsa"
@@ -17,6 +18,7 @@ import (
 type createOptions struct {
 	keyManager  crypto.KeyManager
 	algorithmID string
+	rand        io.Reader
 }
 
 // CreateOption is a type returned by all [Create] options for variadic parameter support
@@ -29,6 +31,15 @@ func KeyManager(k crypto.KeyManager) CreateOption {
 	}
 }
 
+// EntropySource is an option that can be passed to Create to set the source of randomness used to generate the DID's keys
+// when no [KeyManager] is provided. Providing a deterministic reader results in the same DID every
+// time, which makes this useful for reproducible test fixtures.
+func EntropySource(rand io.Reader) CreateOption {
+	return func(o *createOptions) {
+		o.rand = rand
+	}
+}
+
 // AlgorithmID is an option that can be passed to Create to specify a specific
 // cryptographic algorithm to use to generate the private key
 func AlgorithmID(id string) CreateOption {
@@ -45,7 +56,6 @@ func AlgorithmID(id string) CreateOption {
 // Spec: https://github.com/quartzjer/did-jwk/blob/main/spec.md
 func Create(opts ...CreateOption) (did.BearerDID, error) {
 	o := createOptions{
-		keyManager:  crypto.NewLocalKeyManager(),
 		algorithmID: dsa.AlgorithmIDED25519,
 	}
 
@@ -53,6 +63,10 @@ func Create(opts ...CreateOption) (did.BearerDID, error) {
 		opt(&o)
 	}
 
+	if o.keyManager == nil {
+		o.keyManager = crypto.NewLocalKeyManager(crypto.EntropySource(o.rand))
+	}
+
 	keyMgr := o.keyManager
 
 	keyID, err := keyMgr.GeneratePrivateKey(o.algorithmID)
@@ -127,11 +141,17 @@ func createDocument(did did.DID, publicKey jwk.JWK) didcore.Document {
 
 	vm := didcore.VerificationMethod{
 		ID:           did.URI + "#0",
-		Type:         "JsonWebKey",
+		Type:         didcore.VerificationMethodTypeJSONWebKey,
 		Controller:   did.URI,
 		PublicKeyJwk: &publicKey,
 	}
 
+	// keys that can't be used to sign (e.g. X25519) can only be used for key agreement
+	if _, err := dsa.AlgorithmID(&publicKey); err != nil {
+		doc.AddVerificationMethod(vm, didcore.Purposes(didcore.PurposeKeyAgreement))
+		return doc
+	}
+
 	doc.AddVerificationMethod(
 		vm,
 		didcore.Purposes("assertionMethod", "authentication", "capabilityInvocation", "capabilityDelegation"),
@@ -1,11 +1,14 @@
 package didjwk_test
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 
 	"github.com/alecthomas/assert/v2"
 	"github.com/tbd54566975/web5-go"
+	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/crypto/ecdh"
 	"github.com/tbd54566975/web5-go/dids/didcore"
 	"github.com/tbd54566975/web5-go/dids/didjwk"
 	"github.com/tbd54566975/web5-go/jwk"
@@ -60,3 +63,40 @@ func TestVector_Resolve(t *testing.T) {
 		})
 	}
 }
+
+func TestCreate_SECP256R1(t *testing.T) {
+	did, err := didjwk.Create(didjwk.AlgorithmID(dsa.AlgorithmIDSECP256R1))
+	assert.NoError(t, err)
+
+	result, err := didjwk.Resolver{}.Resolve(did.URI)
+	assert.NoError(t, err)
+
+	vm := result.Document.VerificationMethod[0]
+	assert.Equal(t, "P-256", vm.PublicKeyJwk.CRV)
+	assert.Equal(t, did.Document, result.Document)
+}
+
+func TestCreate_X25519(t *testing.T) {
+	did, err := didjwk.Create(didjwk.AlgorithmID(ecdh.X25519AlgorithmID))
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, len(did.Document.KeyAgreement))
+	assert.Equal(t, 0, len(did.Document.AssertionMethod))
+	assert.Equal(t, 0, len(did.Document.Authentication))
+
+	vm, err := did.Document.SelectVerificationMethod(didcore.PurposeKeyAgreement)
+	assert.NoError(t, err)
+	assert.Equal(t, ecdh.X25519JWACurve, vm.PublicKeyJwk.CRV)
+}
+
+func TestCreate_EntropySource(t *testing.T) {
+	entropy := bytes.Repeat([]byte{0x42}, 32)
+
+	first, err := didjwk.Create(didjwk.EntropySource(bytes.NewReader(entropy)))
+	assert.NoError(t, err)
+
+	second, err := didjwk.Create(didjwk.EntropySource(bytes.NewReader(entropy)))
+	assert.NoError(t, err)
+
+	assert.Equal(t, first.URI, second.URI)
+}
@@ -28,6 +28,7 @@ type createOptions struct {
 	keyManager  crypto.KeyManager
 	alsoKnownAs []string
 	controllers []string
+	rand        io.Reader
 }
 
 // privateKeyOption is a struct to hold options for creating a new private key.
@@ -80,6 +81,15 @@ func KeyManager(km crypto.KeyManager) CreateOption {
 	}
 }
 
+// EntropySource is used to set the source of randomness used to generate the DID's keys
+// when no [KeyManager] is provided. Providing a deterministic reader results in the same DID every
+// time, which makes this useful for reproducible test fixtures.
+func EntropySource(rand io.Reader) CreateOption {
+	return func(o *createOptions) {
+		o.rand = rand
+	}
+}
+
 // AlsoKnownAs is used to set the 'alsoKnownAs' property of the DID Document.
 // more details here: https://www.w3.org/TR/did-core/#also-known-as
 func AlsoKnownAs(aka ...string) CreateOption {
@@ -102,7 +112,6 @@ func Controllers(controllers ...string) CreateOption {
 // More information regarding did:web can be found here: https://w3c-ccg.github.io/did-method-web/
 func Create(domain string, opts ...CreateOption) (_did.BearerDID, error) {
 	options := &createOptions{
-		keyManager: crypto.NewLocalKeyManager(),
 		privateKeys: []privateKeyOption{
 			{
 				algorithmID: dsa.AlgorithmIDED25519,
@@ -114,6 +123,10 @@ func Create(domain string, opts ...CreateOption) (_did.BearerDID, error) {
 		opt(options)
 	}
 
+	if options.keyManager == nil {
+		options.keyManager = crypto.NewLocalKeyManager(crypto.EntropySource(options.rand))
+	}
+
 	// normalize domain by adding scheme if not present. otherwise [url.Parse] won't error but we also won't get
 	// necessary part separation.
 	var normalizedDomain string
@@ -168,7 +181,7 @@ func Create(domain string, opts ...CreateOption) (_did.BearerDID, error) {
 
 		vm := didcore.VerificationMethod{
 			ID:           did.URI + "#" + strconv.Itoa(idx),
-			Type:         "JsonWebKey",
+			Type:         didcore.VerificationMethodTypeJSONWebKey,
 			Controller:   did.URI,
 			PublicKeyJwk: &publicKeyJWK,
 		}
@@ -188,6 +201,23 @@ func Create(domain string, opts ...CreateOption) (_did.BearerDID, error) {
 	}, nil
 }
 
+// MarshalDocument returns the DID Document of the given `did:web` BearerDID as JSON. did:web DIDs are
+// published by serving this as did.json at the URL returned by [TransformID]. After updating the DID
+// Document, e.g. after rotating a key with [_did.BearerDID.RotateKey], the served did.json has to be
+// replaced with the new output for the update to take effect.
+func MarshalDocument(bdid _did.BearerDID) ([]byte, error) {
+	if bdid.Method != "web" {
+		return nil, fmt.Errorf("expected did:web, got did:%s", bdid.Method)
+	}
+
+	document, err := json.MarshalIndent(bdid.Document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize document: %w", err)
+	}
+
+	return document, nil
+}
+
 // TransformID takes a did:web's identifier (the third part, after the method) and returns the web URL per the [spec]
 //
 // [spec]: https://w3c-ccg.github.io/did-method-web/#read-resolve
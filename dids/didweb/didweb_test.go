@@ -1,6 +1,8 @@
 package didweb_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"testing"
 
 	"github.com/alecthomas/assert/v2"
@@ -55,6 +57,18 @@ func TestCreate_WithOptions(t *testing.T) {
 
 }
 
+func TestCreate_EntropySource(t *testing.T) {
+	entropy := bytes.Repeat([]byte{0x42}, 32)
+
+	first, err := didweb.Create("localhost:8080", didweb.EntropySource(bytes.NewReader(entropy)))
+	assert.NoError(t, err)
+
+	second, err := didweb.Create("localhost:8080", didweb.EntropySource(bytes.NewReader(entropy)))
+	assert.NoError(t, err)
+
+	assert.Equal(t, first.Document, second.Document)
+}
+
 func TestTransformID(t *testing.T) {
 	var vectors = []struct {
 		input  string
@@ -91,3 +105,19 @@ func TestTransformID(t *testing.T) {
 		})
 	}
 }
+
+func TestMarshalDocument(t *testing.T) {
+	bearerDID, err := didweb.Create("localhost:8080")
+	assert.NoError(t, err)
+
+	_, err = bearerDID.RotateKey(bearerDID.Document.VerificationMethod[0].ID)
+	assert.NoError(t, err)
+
+	output, err := didweb.MarshalDocument(bearerDID)
+	assert.NoError(t, err)
+
+	var document didcore.Document
+	err = json.Unmarshal(output, &document)
+	assert.NoError(t, err)
+	assert.Equal(t, bearerDID.Document, document)
+}
@@ -3,6 +3,7 @@ module github.com/tbd54566975/web5-go
 go 1.22.0
 
 require (
+	filippo.io/edwards25519 v1.1.0
 	github.com/PaesslerAG/jsonpath v0.1.1
 	github.com/alecthomas/assert v1.0.0
 	github.com/alecthomas/assert/v2 v2.5.0
@@ -12,7 +13,9 @@ require (
 	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1
 	github.com/stretchr/testify v1.9.0
 	github.com/tv42/zbase32 v0.0.0-20220222190657-f76a9fc892fa
+	golang.org/x/crypto v0.22.0
 	golang.org/x/net v0.24.0
+	golang.org/x/text v0.14.0
 )
 
 require (
@@ -0,0 +1,481 @@
+// Package jcs implements the JSON Canonicalization Scheme (JCS) as per [RFC 8785].
+//
+// Canonical JSON has no whitespace, object members sorted by the UTF-16 code units of their names,
+// numbers serialized like ECMAScript's Number.prototype.toString and strings escaped like ECMAScript's
+// JSON.stringify. Two JSON texts that represent the same data produce the same canonical form, which
+// makes it suitable for hashing and signing JSON.
+//
+// [RFC 8785]: https://www.rfc-editor.org/rfc/rfc8785
+package jcs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// maxDepth is the maximum nesting depth of arrays and objects
+const maxDepth = 10000
+
+// Marshal returns the canonical JSON encoding of v. v is first encoded using [json.Marshal], so the
+// usual struct tags and [json.Marshaler] implementations apply.
+func Marshal(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return Transform(data)
+}
+
+// Transform returns the canonical form of the given JSON text. An error is returned if the input is not
+// valid [I-JSON], e.g. if it contains duplicate object member names, invalid Unicode or numbers that
+// can't be represented as IEEE 754 double precision values.
+//
+// [I-JSON]: https://www.rfc-editor.org/rfc/rfc7493
+func Transform(data []byte) ([]byte, error) {
+	if !utf8.Valid(data) {
+		return nil, errors.New("input is not valid UTF-8")
+	}
+
+	p := parser{data: data}
+
+	p.skipWhitespace()
+	canonical, err := p.value(nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipWhitespace()
+	if p.pos != len(p.data) {
+		return nil, p.errorf("unexpected data after top-level value")
+	}
+
+	return canonical, nil
+}
+
+// parser is a JSON parser that writes the canonical form of each value as it goes
+type parser struct {
+	data []byte
+	pos  int
+}
+
+// member is a parsed object member
+type member struct {
+	name  []uint16
+	key   string
+	value []byte
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid JSON at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) skipWhitespace() {
+	for p.pos < len(p.data) {
+		switch p.data[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// value parses the value at the current position and appends its canonical form to dst
+func (p *parser) value(dst []byte, depth int) ([]byte, error) {
+	if depth > maxDepth {
+		return nil, p.errorf("exceeded max depth of %d", maxDepth)
+	}
+
+	if p.pos >= len(p.data) {
+		return nil, p.errorf("unexpected end of input")
+	}
+
+	switch c := p.data[p.pos]; {
+	case c == '{':
+		return p.object(dst, depth)
+	case c == '[':
+		return p.array(dst, depth)
+	case c == '"':
+		s, err := p.string()
+		if err != nil {
+			return nil, err
+		}
+
+		return appendString(dst, s), nil
+	case c == 't':
+		return p.literal(dst, "true")
+	case c == 'f':
+		return p.literal(dst, "false")
+	case c == 'n':
+		return p.literal(dst, "null")
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.number(dst)
+	default:
+		return nil, p.errorf("unexpected character %q", c)
+	}
+}
+
+func (p *parser) object(dst []byte, depth int) ([]byte, error) {
+	p.pos++ // {
+
+	var members []member
+	seen := make(map[string]bool)
+
+	p.skipWhitespace()
+	if p.pos < len(p.data) && p.data[p.pos] == '}' {
+		p.pos++
+		return append(dst, "{}"...), nil
+	}
+
+	for {
+		p.skipWhitespace()
+		if p.pos >= len(p.data) || p.data[p.pos] != '"' {
+			return nil, p.errorf("expected object member name")
+		}
+
+		key, err := p.string()
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[key] {
+			return nil, p.errorf("duplicate object member name %q", key)
+		}
+		seen[key] = true
+
+		p.skipWhitespace()
+		if p.pos >= len(p.data) || p.data[p.pos] != ':' {
+			return nil, p.errorf("expected ':' after object member name")
+		}
+		p.pos++
+
+		p.skipWhitespace()
+		value, err := p.value(nil, depth+1)
+		if err != nil {
+			return nil, err
+		}
+
+		members = append(members, member{name: utf16.Encode([]rune(key)), key: key, value: value})
+
+		p.skipWhitespace()
+		if p.pos >= len(p.data) {
+			return nil, p.errorf("unexpected end of input")
+		}
+
+		if p.data[p.pos] == '}' {
+			p.pos++
+			break
+		}
+
+		if p.data[p.pos] != ',' {
+			return nil, p.errorf("expected ',' or '}' after object member")
+		}
+		p.pos++
+	}
+
+	// member names are sorted by their UTF-16 code units, as per https://www.rfc-editor.org/rfc/rfc8785#section-3.2.3
+	slices.SortFunc(members, func(a, b member) int {
+		return slices.Compare(a.name, b.name)
+	})
+
+	dst = append(dst, '{')
+	for i, m := range members {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+
+		dst = appendString(dst, m.key)
+		dst = append(dst, ':')
+		dst = append(dst, m.value...)
+	}
+
+	return append(dst, '}'), nil
+}
+
+func (p *parser) array(dst []byte, depth int) ([]byte, error) {
+	p.pos++ // [
+
+	dst = append(dst, '[')
+
+	p.skipWhitespace()
+	if p.pos < len(p.data) && p.data[p.pos] == ']' {
+		p.pos++
+		return append(dst, ']'), nil
+	}
+
+	for i := 0; ; i++ {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+
+		p.skipWhitespace()
+
+		var err error
+		dst, err = p.value(dst, depth+1)
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipWhitespace()
+		if p.pos >= len(p.data) {
+			return nil, p.errorf("unexpected end of input")
+		}
+
+		if p.data[p.pos] == ']' {
+			p.pos++
+			break
+		}
+
+		if p.data[p.pos] != ',' {
+			return nil, p.errorf("expected ',' or ']' after array element")
+		}
+		p.pos++
+	}
+
+	return append(dst, ']'), nil
+}
+
+func (p *parser) literal(dst []byte, literal string) ([]byte, error) {
+	if !bytes.HasPrefix(p.data[p.pos:], []byte(literal)) {
+		return nil, p.errorf("invalid literal")
+	}
+
+	p.pos += len(literal)
+
+	return append(dst, literal...), nil
+}
+
+// number parses a number as per https://www.rfc-editor.org/rfc/rfc8259#section-6 and appends it in
+// canonical form
+func (p *parser) number(dst []byte) ([]byte, error) {
+	start := p.pos
+
+	p.consume('-')
+
+	switch {
+	case p.consume('0'):
+	case p.digits() == 0:
+		return nil, p.errorf("invalid number")
+	}
+
+	if p.consume('.') && p.digits() == 0 {
+		return nil, p.errorf("invalid number")
+	}
+
+	if p.consume('e') || p.consume('E') {
+		if !p.consume('+') {
+			p.consume('-')
+		}
+
+		if p.digits() == 0 {
+			return nil, p.errorf("invalid number")
+		}
+	}
+
+	f, err := strconv.ParseFloat(string(p.data[start:p.pos]), 64)
+	if err != nil {
+		return nil, p.errorf("number %s can't be represented as an IEEE 754 double", p.data[start:p.pos])
+	}
+
+	formatted, err := formatNumber(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(dst, formatted...), nil
+}
+
+// consume advances past the given character if it is next
+func (p *parser) consume(c byte) bool {
+	if p.pos < len(p.data) && p.data[p.pos] == c {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+// digits advances past a sequence of digits and returns its length
+func (p *parser) digits() int {
+	start := p.pos
+	for p.pos < len(p.data) && p.data[p.pos] >= '0' && p.data[p.pos] <= '9' {
+		p.pos++
+	}
+
+	return p.pos - start
+}
+
+// string parses a string and returns its decoded value
+func (p *parser) string() (string, error) {
+	p.pos++ // opening quote
+
+	var sb strings.Builder
+	for {
+		if p.pos >= len(p.data) {
+			return "", p.errorf("unterminated string")
+		}
+
+		c := p.data[p.pos]
+		switch {
+		case c == '"':
+			p.pos++
+			return sb.String(), nil
+		case c < 0x20:
+			return "", p.errorf("control character in string")
+		case c == '\\':
+			if err := p.escape(&sb); err != nil {
+				return "", err
+			}
+		default:
+			// input is valid UTF-8, so copying byte by byte copies whole characters
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+// escape decodes the escape sequence at the current position
+func (p *parser) escape(sb *strings.Builder) error {
+	if p.pos+1 >= len(p.data) {
+		return p.errorf("unterminated escape sequence")
+	}
+
+	c := p.data[p.pos+1]
+	p.pos += 2
+
+	switch c {
+	case '"', '\\', '/':
+		sb.WriteByte(c)
+	case 'b':
+		sb.WriteByte('\b')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'r':
+		sb.WriteByte('\r')
+	case 't':
+		sb.WriteByte('\t')
+	case 'u':
+		r, err := p.hex4()
+		if err != nil {
+			return err
+		}
+
+		if utf16.IsSurrogate(r) {
+			// a high surrogate must be followed by an escaped low surrogate
+			if r >= 0xdc00 || !bytes.HasPrefix(p.data[p.pos:], []byte(`\u`)) {
+				return p.errorf("lone surrogate in string")
+			}
+
+			p.pos += 2
+			low, err := p.hex4()
+			if err != nil {
+				return err
+			}
+
+			r = utf16.DecodeRune(r, low)
+			if r == utf8.RuneError {
+				return p.errorf("lone surrogate in string")
+			}
+		}
+
+		sb.WriteRune(r)
+	default:
+		return p.errorf("invalid escape sequence \\%c", c)
+	}
+
+	return nil
+}
+
+// hex4 parses the 4 hex digits of a \u escape sequence
+func (p *parser) hex4() (rune, error) {
+	if p.pos+4 > len(p.data) {
+		return 0, p.errorf("invalid unicode escape sequence")
+	}
+
+	v, err := strconv.ParseUint(string(p.data[p.pos:p.pos+4]), 16, 16)
+	if err != nil {
+		return 0, p.errorf("invalid unicode escape sequence")
+	}
+
+	p.pos += 4
+
+	return rune(v), nil
+}
+
+// appendString appends the given string in canonical form, as per https://www.rfc-editor.org/rfc/rfc8785#section-3.2.2.2
+func appendString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+
+	for _, r := range s {
+		switch r {
+		case '"':
+			dst = append(dst, `\"`...)
+		case '\\':
+			dst = append(dst, `\\`...)
+		case '\b':
+			dst = append(dst, `\b`...)
+		case '\f':
+			dst = append(dst, `\f`...)
+		case '\n':
+			dst = append(dst, `\n`...)
+		case '\r':
+			dst = append(dst, `\r`...)
+		case '\t':
+			dst = append(dst, `\t`...)
+		default:
+			if r < 0x20 {
+				dst = fmt.Appendf(dst, `\u%04x`, r)
+			} else {
+				dst = utf8.AppendRune(dst, r)
+			}
+		}
+	}
+
+	return append(dst, '"')
+}
+
+// formatNumber serializes the given number like ECMAScript's Number.prototype.toString, as per
+// https://www.rfc-editor.org/rfc/rfc8785#section-3.2.2.3
+func formatNumber(f float64) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("%v can't be represented in JSON", f)
+	}
+
+	// also covers negative zero
+	if f == 0 {
+		return "0", nil
+	}
+
+	var sign string
+	if f < 0 {
+		sign = "-"
+		f = -f
+	}
+
+	// ECMAScript uses exponential notation below 1e-6 and from 1e21 on
+	format := byte('f')
+	if f < 1e-6 || f >= 1e21 {
+		format = 'e'
+	}
+
+	// both produce the shortest representation that round trips, like ECMAScript
+	formatted := strconv.FormatFloat(f, format, -1, 64)
+
+	// Go pads exponents to two digits (e.g. 1e+07) where ECMAScript doesn't (e.g. 1e+7)
+	if i := strings.IndexByte(formatted, 'e'); i != -1 && formatted[i+2] == '0' {
+		formatted = formatted[:i+2] + formatted[i+3:]
+	}
+
+	return sign + formatted, nil
+}
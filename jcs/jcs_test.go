@@ -0,0 +1,111 @@
+package jcs_test
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/jcs"
+)
+
+// test vectors from https://github.com/cyberphone/json-canonicalization/tree/master/testdata
+func TestTransform_Vectors(t *testing.T) {
+	names := []string{"arrays", "french", "structures", "unicode", "values", "weird"}
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			input, err := os.ReadFile(filepath.Join("testdata", "input", name+".json"))
+			assert.NoError(t, err)
+
+			expected, err := os.ReadFile(filepath.Join("testdata", "output", name+".json"))
+			assert.NoError(t, err)
+
+			canonical, err := jcs.Transform(input)
+			assert.NoError(t, err)
+			assert.Equal(t, string(expected), string(canonical))
+		})
+	}
+}
+
+// https://www.rfc-editor.org/rfc/rfc8785#appendix-B
+func TestTransform_Numbers(t *testing.T) {
+	vectors := []struct {
+		bits     uint64
+		expected string
+	}{
+		{0x0000000000000000, "0"},
+		{0x8000000000000000, "0"},
+		{0x0000000000000001, "5e-324"},
+		{0x8000000000000001, "-5e-324"},
+		{0x7fefffffffffffff, "1.7976931348623157e+308"},
+		{0xffefffffffffffff, "-1.7976931348623157e+308"},
+		{0x4340000000000000, "9007199254740992"},
+		{0xc340000000000000, "-9007199254740992"},
+		{0x4430000000000000, "295147905179352830000"},
+		{0x44b52d02c7e14af5, "9.999999999999997e+22"},
+		{0x44b52d02c7e14af6, "1e+23"},
+		{0x44b52d02c7e14af7, "1.0000000000000001e+23"},
+		{0x444b1ae4d6e2ef4e, "999999999999999700000"},
+		{0x444b1ae4d6e2ef4f, "999999999999999900000"},
+		{0x444b1ae4d6e2ef50, "1e+21"},
+		{0x3eb0c6f7a0b5ed8c, "9.999999999999997e-7"},
+		{0x3eb0c6f7a0b5ed8d, "0.000001"},
+		{0x41b3de4355555553, "333333333.3333332"},
+		{0x41b3de4355555554, "333333333.33333325"},
+		{0x41b3de4355555555, "333333333.3333333"},
+		{0x41b3de4355555556, "333333333.3333334"},
+		{0x41b3de4355555557, "333333333.33333343"},
+		{0xbecbf647612f3696, "-0.0000033333333333333333"},
+		{0x43143ff3c1cb0959, "1424953923781206.2"},
+	}
+
+	for _, v := range vectors {
+		input := strconv.FormatFloat(math.Float64frombits(v.bits), 'g', -1, 64)
+
+		t.Run(input, func(t *testing.T) {
+			canonical, err := jcs.Transform([]byte(input))
+			assert.NoError(t, err)
+			assert.Equal(t, v.expected, string(canonical))
+		})
+	}
+}
+
+func TestTransform_Invalid(t *testing.T) {
+	vectors := map[string]string{
+		"duplicate member":     `{"a": 1, "a": 2}`,
+		"lone high surrogate":  `"\ud83d"`,
+		"lone low surrogate":   `"\ude00"`,
+		"number out of range":  `1e400`,
+		"trailing data":        `{} {}`,
+		"trailing comma":       `[1,]`,
+		"leading zero":         `01`,
+		"invalid utf-8":        "\"\xff\"",
+		"unescaped control":    "\"\x01\"",
+		"unterminated object":  `{"a": 1`,
+		"invalid literal":      `nul`,
+		"invalid escape":       `"\x"`,
+		"missing member value": `{"a"}`,
+	}
+
+	for name, input := range vectors {
+		t.Run(name, func(t *testing.T) {
+			_, err := jcs.Transform([]byte(input))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	v := map[string]any{
+		"b": []any{1.0, " ", nil},
+		"a": true,
+		"€": 1e21,
+	}
+
+	canonical, err := jcs.Marshal(v)
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"a\":true,\"b\":[1,\" \",null],\"€\":1e+21}", string(canonical))
+}
@@ -0,0 +1,85 @@
+package jwe
+
+import (
+	"crypto/aes"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// defaultIV is the initial value described in https://datatracker.ietf.org/doc/html/rfc3394#section-2.2.3.1
+var defaultIV = []byte{0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6}
+
+// aesKeyWrap wraps the given key with the given key encryption key as per https://datatracker.ietf.org/doc/html/rfc3394#section-2.2.1
+func aesKeyWrap(kek []byte, key []byte) ([]byte, error) {
+	if len(key) < 16 || len(key)%8 != 0 {
+		return nil, errors.New("key to wrap must be a multiple of 8 bytes and at least 16 bytes")
+	}
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	n := len(key) / 8
+	wrapped := make([]byte, 8+len(key))
+	copy(wrapped, defaultIV)
+	copy(wrapped[8:], key)
+
+	a := wrapped[:8]
+	b := make([]byte, 16)
+	for j := 0; j < 6; j++ {
+		for i := 1; i <= n; i++ {
+			r := wrapped[i*8 : (i+1)*8]
+
+			copy(b, a)
+			copy(b[8:], r)
+			block.Encrypt(b, b)
+
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(a, binary.BigEndian.Uint64(b[:8])^t)
+			copy(r, b[8:])
+		}
+	}
+
+	return wrapped, nil
+}
+
+// aesKeyUnwrap unwraps the given wrapped key with the given key encryption key as per https://datatracker.ietf.org/doc/html/rfc3394#section-2.2.2
+func aesKeyUnwrap(kek []byte, wrapped []byte) ([]byte, error) {
+	if len(wrapped) < 24 || len(wrapped)%8 != 0 {
+		return nil, errors.New("wrapped key must be a multiple of 8 bytes and at least 24 bytes")
+	}
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	n := len(wrapped)/8 - 1
+	unwrapped := make([]byte, len(wrapped))
+	copy(unwrapped, wrapped)
+
+	a := unwrapped[:8]
+	b := make([]byte, 16)
+	for j := 5; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			r := unwrapped[i*8 : (i+1)*8]
+
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(b, binary.BigEndian.Uint64(a)^t)
+			copy(b[8:], r)
+			block.Decrypt(b, b)
+
+			copy(a, b[:8])
+			copy(r, b[8:])
+		}
+	}
+
+	if subtle.ConstantTimeCompare(a, defaultIV) != 1 {
+		return nil, errors.New("integrity check failed")
+	}
+
+	return unwrapped[8:], nil
+}
@@ -0,0 +1,63 @@
+package jwe
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestAESKeyWrap(t *testing.T) {
+	// vectors taken from https://datatracker.ietf.org/doc/html/rfc3394#section-4
+	vectors := []struct {
+		name     string
+		kek      string
+		key      string
+		expected string
+	}{
+		{
+			name:     "128 bits of key data with a 256-bit KEK",
+			kek:      "000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+			key:      "00112233445566778899AABBCCDDEEFF",
+			expected: "64E8C3F9CE0F5BA263E9777905818A2A93C8191E7D6E8AE7",
+		},
+		{
+			name:     "256 bits of key data with a 256-bit KEK",
+			kek:      "000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+			key:      "00112233445566778899AABBCCDDEEFF000102030405060708090A0B0C0D0E0F",
+			expected: "28C9F404C4B810F4CBCCB35CFB87F8263F5786E2D80ED326CBC7F0E71A99F43BFB988B9B7A02DD21",
+		},
+	}
+
+	for _, v := range vectors {
+		t.Run(v.name, func(t *testing.T) {
+			kek, err := hex.DecodeString(v.kek)
+			assert.NoError(t, err)
+
+			key, err := hex.DecodeString(v.key)
+			assert.NoError(t, err)
+
+			wrapped, err := aesKeyWrap(kek, key)
+			assert.NoError(t, err)
+			assert.Equal(t, strings.ToLower(v.expected), hex.EncodeToString(wrapped))
+
+			unwrapped, err := aesKeyUnwrap(kek, wrapped)
+			assert.NoError(t, err)
+			assert.Equal(t, key, unwrapped)
+		})
+	}
+}
+
+func TestAESKeyUnwrap_IntegrityCheck(t *testing.T) {
+	kek := make([]byte, 32)
+	key := make([]byte, 32)
+
+	wrapped, err := aesKeyWrap(kek, key)
+	assert.NoError(t, err)
+
+	wrapped[len(wrapped)-1] ^= 0x01
+
+	_, err = aesKeyUnwrap(kek, wrapped)
+	assert.Error(t, err)
+}
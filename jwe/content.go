@@ -0,0 +1,78 @@
+package jwe
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// contentKeySize returns the size in bytes of the content encryption key for the given enc value
+func contentKeySize(enc string) (int, error) {
+	switch enc {
+	case EncryptionA256GCM:
+		return 32, nil
+	case EncryptionXC20P:
+		return chacha20poly1305.KeySize, nil
+	default:
+		return 0, fmt.Errorf("unsupported enc: %s", enc)
+	}
+}
+
+func newAEAD(enc string, cek []byte) (cipher.AEAD, error) {
+	switch enc {
+	case EncryptionA256GCM:
+		block, err := aes.NewCipher(cek)
+		if err != nil {
+			return nil, err
+		}
+
+		return cipher.NewGCM(block)
+	case EncryptionXC20P:
+		return chacha20poly1305.NewX(cek)
+	default:
+		return nil, fmt.Errorf("unsupported enc: %s", enc)
+	}
+}
+
+// encryptContent encrypts the given plaintext with a random iv and returns the iv, ciphertext and
+// authentication tag separately as required by https://datatracker.ietf.org/doc/html/rfc7516#section-5.1
+func encryptContent(enc string, cek []byte, plaintext []byte, aad []byte) ([]byte, []byte, []byte, error) {
+	aead, err := newAEAD(enc, cek)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	iv := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(iv); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to generate iv: %w", err)
+	}
+
+	sealed := aead.Seal(nil, iv, plaintext, aad)
+	tagStart := len(sealed) - aead.Overhead()
+
+	return iv, sealed[:tagStart], sealed[tagStart:], nil
+}
+
+func decryptContent(enc string, cek []byte, iv []byte, ciphertext []byte, tag []byte, aad []byte) ([]byte, error) {
+	aead, err := newAEAD(enc, cek)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(iv) != aead.NonceSize() {
+		return nil, fmt.Errorf("iv must be %d bytes", aead.NonceSize())
+	}
+
+	if len(tag) != aead.Overhead() {
+		return nil, fmt.Errorf("tag must be %d bytes", aead.Overhead())
+	}
+
+	sealed := make([]byte, 0, len(ciphertext)+len(tag))
+	sealed = append(sealed, ciphertext...)
+	sealed = append(sealed, tag...)
+
+	return aead.Open(nil, iv, sealed, aad)
+}
@@ -0,0 +1,422 @@
+package jwe
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tbd54566975/web5-go/crypto"
+	"github.com/tbd54566975/web5-go/crypto/ecdh"
+	"github.com/tbd54566975/web5-go/dids"
+	_did "github.com/tbd54566975/web5-go/dids/did"
+	"github.com/tbd54566975/web5-go/dids/didcore"
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+// Key management algorithms supported by this package. See [Specification] for more details.
+//
+// [Specification]: https://datatracker.ietf.org/doc/html/rfc7518#section-4.6
+const (
+	// AlgorithmECDHES uses the output of the Concat KDF directly as the content encryption key
+	AlgorithmECDHES string = "ECDH-ES"
+	// AlgorithmECDHESA256KW wraps a random content encryption key with A256KW using the output of the Concat KDF
+	AlgorithmECDHESA256KW string = "ECDH-ES+A256KW"
+)
+
+// Content encryption algorithms supported by this package.
+const (
+	// EncryptionA256GCM is AES GCM using a 256-bit key. See https://datatracker.ietf.org/doc/html/rfc7518#section-5.3
+	EncryptionA256GCM string = "A256GCM"
+	// EncryptionXC20P is XChaCha20-Poly1305 using a 256-bit key. See https://datatracker.ietf.org/doc/html/draft-amringer-jose-chacha-02
+	EncryptionXC20P string = "XC20P"
+)
+
+// Decode decodes the given JWE string into a [Decoded] type
+//
+// # Note
+//
+// The given JWE input is assumed to be a [compact JWE]
+//
+// [compact JWE]: https://datatracker.ietf.org/doc/html/rfc7516#section-7.1
+func Decode(jwe string) (Decoded, error) {
+	parts := strings.Split(jwe, ".")
+	if len(parts) != 5 {
+		return Decoded{}, fmt.Errorf("malformed JWE. Expected 5 parts, got %d", len(parts))
+	}
+
+	header, err := DecodeHeader(parts[0])
+	if err != nil {
+		return Decoded{}, fmt.Errorf("malformed JWE. Failed to decode header: %w", err)
+	}
+
+	encryptedKey, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Decoded{}, fmt.Errorf("malformed JWE. Failed to decode encrypted key: %w", err)
+	}
+
+	iv, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Decoded{}, fmt.Errorf("malformed JWE. Failed to decode iv: %w", err)
+	}
+
+	ciphertext, err := base64.RawURLEncoding.DecodeString(parts[3])
+	if err != nil {
+		return Decoded{}, fmt.Errorf("malformed JWE. Failed to decode ciphertext: %w", err)
+	}
+
+	tag, err := base64.RawURLEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Decoded{}, fmt.Errorf("malformed JWE. Failed to decode tag: %w", err)
+	}
+
+	return Decoded{
+		Header:       header,
+		EncryptedKey: encryptedKey,
+		IV:           iv,
+		Ciphertext:   ciphertext,
+		Tag:          tag,
+		Parts:        parts,
+	}, nil
+}
+
+// DecodeHeader decodes the base64url encoded JWE header into a [Header]
+func DecodeHeader(base64UrlEncodedHeader string) (Header, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(base64UrlEncodedHeader)
+	if err != nil {
+		return Header{}, err
+	}
+
+	var header Header
+	err = json.Unmarshal(bytes, &header)
+	if err != nil {
+		return Header{}, err
+	}
+
+	return header, nil
+}
+
+// options that encrypt function can take
+type encryptOpts struct {
+	alg string
+	enc string
+	typ string
+	cty string
+	apu []byte
+	apv []byte
+}
+
+// EncryptOpt is a type that represents an option that can be passed to [github.com/tbd54566975/web5-go/jwe.Encrypt].
+type EncryptOpt func(opts *encryptOpts)
+
+// Algorithm is an option that can be passed to [github.com/tbd54566975/web5-go/jwe.Encrypt].
+// It is used to set the key management algorithm. Defaults to [AlgorithmECDHES]
+func Algorithm(alg string) EncryptOpt {
+	return func(opts *encryptOpts) {
+		opts.alg = alg
+	}
+}
+
+// Encryption is an option that can be passed to [github.com/tbd54566975/web5-go/jwe.Encrypt].
+// It is used to set the content encryption algorithm. Defaults to [EncryptionA256GCM]
+func Encryption(enc string) EncryptOpt {
+	return func(opts *encryptOpts) {
+		opts.enc = enc
+	}
+}
+
+// Type is an option that can be passed to [github.com/tbd54566975/web5-go/jwe.Encrypt].
+// It is used to set the `typ` JWE header value
+func Type(typ string) EncryptOpt {
+	return func(opts *encryptOpts) {
+		opts.typ = typ
+	}
+}
+
+// ContentType is an option that can be passed to [github.com/tbd54566975/web5-go/jwe.Encrypt].
+// It is used to set the `cty` JWE header value
+func ContentType(cty string) EncryptOpt {
+	return func(opts *encryptOpts) {
+		opts.cty = cty
+	}
+}
+
+// PartyUInfo is an option that can be passed to [github.com/tbd54566975/web5-go/jwe.Encrypt].
+// It is used to set the `apu` JWE header value which is fed into the key derivation function
+func PartyUInfo(apu []byte) EncryptOpt {
+	return func(opts *encryptOpts) {
+		opts.apu = apu
+	}
+}
+
+// PartyVInfo is an option that can be passed to [github.com/tbd54566975/web5-go/jwe.Encrypt].
+// It is used to set the `apv` JWE header value which is fed into the key derivation function
+func PartyVInfo(apv []byte) EncryptOpt {
+	return func(opts *encryptOpts) {
+		opts.apv = apv
+	}
+}
+
+// Encrypt encrypts the provided plaintext to the recipient DID and returns a compact JWE.
+// The recipient's DID Document is resolved and its first keyAgreement verification method is
+// used unless recipientDIDURI is a DID URL whose fragment references a specific verification method.
+func Encrypt(plaintext []byte, recipientDIDURI string, opts ...EncryptOpt) (string, error) {
+	o := encryptOpts{alg: AlgorithmECDHES, enc: EncryptionA256GCM}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.alg != AlgorithmECDHES && o.alg != AlgorithmECDHESA256KW {
+		return "", fmt.Errorf("unsupported alg: %s", o.alg)
+	}
+
+	keySize, err := contentKeySize(o.enc)
+	if err != nil {
+		return "", err
+	}
+
+	recipientDID, err := _did.Parse(recipientDIDURI)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse recipient DID: %w", err)
+	}
+
+	resolutionResult, err := dids.Resolve(recipientDID.URI)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve DID: %w", err)
+	}
+
+	var vmSelector didcore.VMSelector = didcore.PurposeKeyAgreement
+	if recipientDID.Fragment != "" {
+		vmSelector = didcore.ID(recipientDID.URL)
+	}
+
+	verificationMethod, err := resolutionResult.Document.SelectVerificationMethod(vmSelector)
+	if err != nil {
+		return "", fmt.Errorf("failed to select recipient key: %w", err)
+	}
+
+	recipientKey, err := verificationMethod.PublicKey()
+	if err != nil {
+		return "", fmt.Errorf("failed to get recipient key: %w", err)
+	}
+
+	algorithmID, err := ecdh.AlgorithmID(&recipientKey)
+	if err != nil {
+		return "", fmt.Errorf("recipient key cannot be used for key agreement: %w", err)
+	}
+
+	ephemeralKey, err := ecdh.GeneratePrivateKey(algorithmID)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate ephemeral key: %w", err)
+	}
+
+	sharedSecret, err := ecdh.SharedSecret(ephemeralKey, recipientKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute shared secret: %w", err)
+	}
+
+	epk := ecdh.GetPublicKey(ephemeralKey)
+	header := Header{
+		ALG: o.alg,
+		ENC: o.enc,
+		KID: resolutionResult.Document.GetAbsoluteResourceID(verificationMethod.ID),
+		TYP: o.typ,
+		CTY: o.cty,
+		EPK: &epk,
+		APU: base64.RawURLEncoding.EncodeToString(o.apu),
+		APV: base64.RawURLEncoding.EncodeToString(o.apv),
+	}
+
+	var cek, encryptedKey []byte
+	switch o.alg {
+	case AlgorithmECDHES:
+		cek = concatKDF(sharedSecret, o.enc, o.apu, o.apv, keySize)
+	case AlgorithmECDHESA256KW:
+		kek := concatKDF(sharedSecret, o.alg, o.apu, o.apv, 32)
+
+		cek = make([]byte, keySize)
+		if _, err := rand.Read(cek); err != nil {
+			return "", fmt.Errorf("failed to generate content encryption key: %w", err)
+		}
+
+		encryptedKey, err = aesKeyWrap(kek, cek)
+		if err != nil {
+			return "", fmt.Errorf("failed to wrap content encryption key: %w", err)
+		}
+	}
+
+	base64UrlEncodedHeader, err := header.Encode()
+	if err != nil {
+		return "", fmt.Errorf("failed to base64 url encode header: %w", err)
+	}
+
+	iv, ciphertext, tag, err := encryptContent(o.enc, cek, plaintext, []byte(base64UrlEncodedHeader))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt content: %w", err)
+	}
+
+	compactJWE := strings.Join([]string{
+		base64UrlEncodedHeader,
+		base64.RawURLEncoding.EncodeToString(encryptedKey),
+		base64.RawURLEncoding.EncodeToString(iv),
+		base64.RawURLEncoding.EncodeToString(ciphertext),
+		base64.RawURLEncoding.EncodeToString(tag),
+	}, ".")
+
+	return compactJWE, nil
+}
+
+// Decrypt decodes the given compact JWE and decrypts it using the keyAgreement key of the provided DID.
+// The DID's KeyManager must implement [crypto.KeyDeriver]
+func Decrypt(compactJWE string, did _did.BearerDID) ([]byte, error) {
+	decodedJWE, err := Decode(compactJWE)
+	if err != nil {
+		return nil, fmt.Errorf("decryption failed: %w", err)
+	}
+
+	return decodedJWE.Decrypt(did)
+}
+
+// Decoded is a compact JWE decoded into its parts
+type Decoded struct {
+	Header       Header
+	EncryptedKey []byte
+	IV           []byte
+	Ciphertext   []byte
+	Tag          []byte
+	Parts        []string
+}
+
+// Decrypt decrypts the JWE using the verification method referenced by the kid header value, or the
+// first keyAgreement verification method if kid is not present. The DID's KeyManager must implement
+// [crypto.KeyDeriver]
+func (jwe Decoded) Decrypt(did _did.BearerDID) ([]byte, error) {
+	if jwe.Header.ALG == "" || jwe.Header.ENC == "" || jwe.Header.EPK == nil {
+		return nil, errors.New("malformed JWE header. alg, enc and epk are required")
+	}
+
+	keySize, err := contentKeySize(jwe.Header.ENC)
+	if err != nil {
+		return nil, err
+	}
+
+	deriver, ok := crypto.As[crypto.KeyDeriver](did.KeyManager)
+	if !ok {
+		return nil, errors.New("key manager does not support key agreement")
+	}
+
+	verificationMethod, err := selectRecipientKey(did.Document, jwe.Header.KID)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := verificationMethod.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	keyAlias, err := publicKey.ComputeThumbprint()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute key alias: %w", err)
+	}
+
+	sharedSecret, err := deriver.SharedSecret(keyAlias, *jwe.Header.EPK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute shared secret: %w", err)
+	}
+
+	apu, err := base64.RawURLEncoding.DecodeString(jwe.Header.APU)
+	if err != nil {
+		return nil, fmt.Errorf("malformed JWE header. Failed to decode apu: %w", err)
+	}
+
+	apv, err := base64.RawURLEncoding.DecodeString(jwe.Header.APV)
+	if err != nil {
+		return nil, fmt.Errorf("malformed JWE header. Failed to decode apv: %w", err)
+	}
+
+	var cek []byte
+	switch jwe.Header.ALG {
+	case AlgorithmECDHES:
+		if len(jwe.EncryptedKey) != 0 {
+			return nil, errors.New("malformed JWE. encrypted key must be empty when using ECDH-ES")
+		}
+
+		cek = concatKDF(sharedSecret, jwe.Header.ENC, apu, apv, keySize)
+	case AlgorithmECDHESA256KW:
+		kek := concatKDF(sharedSecret, jwe.Header.ALG, apu, apv, 32)
+
+		cek, err = aesKeyUnwrap(kek, jwe.EncryptedKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unwrap content encryption key: %w", err)
+		}
+
+		if len(cek) != keySize {
+			return nil, fmt.Errorf("content encryption key must be %d bytes", keySize)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported alg: %s", jwe.Header.ALG)
+	}
+
+	plaintext, err := decryptContent(jwe.Header.ENC, cek, jwe.IV, jwe.Ciphertext, jwe.Tag, []byte(jwe.Parts[0]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt content: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// selectRecipientKey returns the verification method referenced by kid or the first keyAgreement
+// verification method if kid is empty
+func selectRecipientKey(document didcore.Document, kid string) (didcore.VerificationMethod, error) {
+	if kid == "" {
+		vm, err := document.SelectVerificationMethod(didcore.PurposeKeyAgreement)
+		if err != nil {
+			return didcore.VerificationMethod{}, fmt.Errorf("failed to select recipient key: %w", err)
+		}
+
+		return vm, nil
+	}
+
+	for _, vm := range document.VerificationMethod {
+		if vm.ID == kid || document.GetAbsoluteResourceID(vm.ID) == kid {
+			return vm, nil
+		}
+	}
+
+	return didcore.VerificationMethod{}, fmt.Errorf("kid does not match any verification method: %s", kid)
+}
+
+// Header represents a JWE (JSON Web Encryption) protected header. See [Specification] for more details.
+//
+// [Specification]: https://datatracker.ietf.org/doc/html/rfc7516#section-4
+type Header struct {
+	// Algorithm used to encrypt or determine the content encryption key https://datatracker.ietf.org/doc/html/rfc7516#section-4.1.1
+	ALG string `json:"alg,omitempty"`
+	// Content encryption algorithm https://datatracker.ietf.org/doc/html/rfc7516#section-4.1.2
+	ENC string `json:"enc,omitempty"`
+	// Key ID Header Parameter https://datatracker.ietf.org/doc/html/rfc7516#section-4.1.6
+	KID string `json:"kid,omitempty"`
+	// Type Header Parameter https://datatracker.ietf.org/doc/html/rfc7516#section-4.1.11
+	TYP string `json:"typ,omitempty"`
+	// Content Type Header Parameter https://datatracker.ietf.org/doc/html/rfc7516#section-4.1.12
+	CTY string `json:"cty,omitempty"`
+	// Ephemeral Public Key Header Parameter https://datatracker.ietf.org/doc/html/rfc7518#section-4.6.1.1
+	EPK *jwk.JWK `json:"epk,omitempty"`
+	// Agreement PartyUInfo Header Parameter (base64url encoded) https://datatracker.ietf.org/doc/html/rfc7518#section-4.6.1.2
+	APU string `json:"apu,omitempty"`
+	// Agreement PartyVInfo Header Parameter (base64url encoded) https://datatracker.ietf.org/doc/html/rfc7518#section-4.6.1.3
+	APV string `json:"apv,omitempty"`
+}
+
+// Encode returns the base64url encoded header.
+func (j Header) Encode() (string, error) {
+	bytes, err := json.Marshal(j)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
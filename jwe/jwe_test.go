@@ -0,0 +1,139 @@
+package jwe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/crypto/ecdh"
+	"github.com/tbd54566975/web5-go/dids/didjwk"
+	"github.com/tbd54566975/web5-go/jwe"
+)
+
+func TestEncrypt(t *testing.T) {
+	did, err := didjwk.Create(didjwk.AlgorithmID(ecdh.X25519AlgorithmID))
+	assert.NoError(t, err)
+
+	plaintext := []byte("hi")
+
+	for _, alg := range []string{jwe.AlgorithmECDHES, jwe.AlgorithmECDHESA256KW} {
+		for _, enc := range []string{jwe.EncryptionA256GCM, jwe.EncryptionXC20P} {
+			t.Run(alg+"/"+enc, func(t *testing.T) {
+				compactJWE, err := jwe.Encrypt(plaintext, did.URI, jwe.Algorithm(alg), jwe.Encryption(enc))
+				assert.NoError(t, err)
+
+				decoded, err := jwe.Decode(compactJWE)
+				assert.NoError(t, err)
+
+				assert.Equal(t, alg, decoded.Header.ALG)
+				assert.Equal(t, enc, decoded.Header.ENC)
+				assert.Equal(t, did.URI+"#0", decoded.Header.KID)
+				assert.Equal(t, ecdh.X25519JWACurve, decoded.Header.EPK.CRV)
+				assert.Equal(t, "", decoded.Header.EPK.D)
+
+				decrypted, err := jwe.Decrypt(compactJWE, did)
+				assert.NoError(t, err)
+				assert.Equal(t, plaintext, decrypted)
+			})
+		}
+	}
+}
+
+func TestEncrypt_Defaults(t *testing.T) {
+	did, err := didjwk.Create(didjwk.AlgorithmID(ecdh.X25519AlgorithmID))
+	assert.NoError(t, err)
+
+	compactJWE, err := jwe.Encrypt([]byte("hi"), did.URI, jwe.Type("JWT"), jwe.PartyUInfo([]byte("Alice")), jwe.PartyVInfo([]byte("Bob")))
+	assert.NoError(t, err)
+
+	decoded, err := jwe.Decode(compactJWE)
+	assert.NoError(t, err)
+
+	assert.Equal(t, jwe.AlgorithmECDHES, decoded.Header.ALG)
+	assert.Equal(t, jwe.EncryptionA256GCM, decoded.Header.ENC)
+	assert.Equal(t, "JWT", decoded.Header.TYP)
+	assert.Equal(t, "QWxpY2U", decoded.Header.APU)
+	assert.Equal(t, "Qm9i", decoded.Header.APV)
+	assert.Equal(t, 0, len(decoded.EncryptedKey))
+
+	decrypted, err := decoded.Decrypt(did)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hi"), decrypted)
+}
+
+func TestEncrypt_VerificationMethodFragment(t *testing.T) {
+	did, err := didjwk.Create(didjwk.AlgorithmID(dsa.AlgorithmIDSECP256R1))
+	assert.NoError(t, err)
+
+	// P-256 did:jwk documents do not list the key under keyAgreement
+	_, err = jwe.Encrypt([]byte("hi"), did.URI)
+	assert.Error(t, err)
+
+	compactJWE, err := jwe.Encrypt([]byte("hi"), did.URI+"#0", jwe.Algorithm(jwe.AlgorithmECDHESA256KW))
+	assert.NoError(t, err)
+
+	decrypted, err := jwe.Decrypt(compactJWE, did)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hi"), decrypted)
+}
+
+func TestEncrypt_UnsupportedRecipientKey(t *testing.T) {
+	did, err := didjwk.Create()
+	assert.NoError(t, err)
+
+	_, err = jwe.Encrypt([]byte("hi"), did.URI+"#0")
+	assert.Error(t, err)
+}
+
+func TestEncrypt_UnsupportedOptions(t *testing.T) {
+	did, err := didjwk.Create(didjwk.AlgorithmID(ecdh.X25519AlgorithmID))
+	assert.NoError(t, err)
+
+	_, err = jwe.Encrypt([]byte("hi"), did.URI, jwe.Algorithm("RSA-OAEP"))
+	assert.Error(t, err)
+
+	_, err = jwe.Encrypt([]byte("hi"), did.URI, jwe.Encryption("A128CBC-HS256"))
+	assert.Error(t, err)
+}
+
+func TestDecrypt_WrongRecipient(t *testing.T) {
+	alice, err := didjwk.Create(didjwk.AlgorithmID(ecdh.X25519AlgorithmID))
+	assert.NoError(t, err)
+
+	bob, err := didjwk.Create(didjwk.AlgorithmID(ecdh.X25519AlgorithmID))
+	assert.NoError(t, err)
+
+	compactJWE, err := jwe.Encrypt([]byte("hi"), alice.URI)
+	assert.NoError(t, err)
+
+	_, err = jwe.Decrypt(compactJWE, bob)
+	assert.Error(t, err)
+}
+
+func TestDecrypt_Tampered(t *testing.T) {
+	did, err := didjwk.Create(didjwk.AlgorithmID(ecdh.X25519AlgorithmID))
+	assert.NoError(t, err)
+
+	compactJWE, err := jwe.Encrypt([]byte("hello world"), did.URI)
+	assert.NoError(t, err)
+
+	parts := strings.Split(compactJWE, ".")
+
+	// swapping the header invalidates the AAD
+	otherJWE, err := jwe.Encrypt([]byte("hello world"), did.URI)
+	assert.NoError(t, err)
+
+	parts[0] = strings.Split(otherJWE, ".")[0]
+
+	_, err = jwe.Decrypt(strings.Join(parts, "."), did)
+	assert.Error(t, err)
+}
+
+func TestDecode_Malformed(t *testing.T) {
+	_, err := jwe.Decode("a.b.c")
+	assert.Error(t, err)
+
+	_, err = jwe.Decode("!.b.c.d.e")
+	assert.Error(t, err)
+}
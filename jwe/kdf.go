@@ -0,0 +1,32 @@
+package jwe
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// concatKDF derives a key of keySize bytes from the given shared secret using the Concat KDF
+// described in https://datatracker.ietf.org/doc/html/rfc7518#section-4.6.2 with SHA-256
+func concatKDF(sharedSecret []byte, algorithmID string, apu []byte, apv []byte, keySize int) []byte {
+	otherInfo := lengthPrefixed([]byte(algorithmID))
+	otherInfo = append(otherInfo, lengthPrefixed(apu)...)
+	otherInfo = append(otherInfo, lengthPrefixed(apv)...)
+	otherInfo = binary.BigEndian.AppendUint32(otherInfo, uint32(keySize*8))
+
+	derived := make([]byte, 0, keySize+sha256.Size)
+	for counter := uint32(1); len(derived) < keySize; counter++ {
+		h := sha256.New()
+		_ = binary.Write(h, binary.BigEndian, counter)
+		h.Write(sharedSecret)
+		h.Write(otherInfo)
+
+		derived = h.Sum(derived)
+	}
+
+	return derived[:keySize]
+}
+
+// lengthPrefixed prefixes the given input with its length as a 32 bit big endian integer
+func lengthPrefixed(input []byte) []byte {
+	return append(binary.BigEndian.AppendUint32(nil, uint32(len(input))), input...)
+}
@@ -0,0 +1,45 @@
+package jwe
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/crypto/ecdh"
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+// vector taken from https://datatracker.ietf.org/doc/html/rfc7518#appendix-C
+func TestConcatKDF(t *testing.T) {
+	alice := jwk.JWK{
+		KTY: "EC",
+		CRV: "P-256",
+		X:   "gI0GAILBdu7T53akrFmMyGcsF3n5dO7MmwNBHKW5SV0",
+		Y:   "SLW_xSffzlPWrHEVI30DHM_4egVwt3NQqeUD7nMFpps",
+		D:   "0_NxaRPUMQoAJt50Gz8YiTr8gRTwyEaCumd-MToTmIo",
+	}
+
+	bob := jwk.JWK{
+		KTY: "EC",
+		CRV: "P-256",
+		X:   "weNJy2HscCSM6AEDTDg04biOvhFhyyWvOHQfeF_PxMQ",
+		Y:   "e8lnCO-AlStT-NJVX-crhB7QRYhiix03illJOVAOyck",
+	}
+
+	sharedSecret, err := ecdh.SharedSecret(alice, bob)
+	assert.NoError(t, err)
+
+	derived := concatKDF(sharedSecret, "A128GCM", []byte("Alice"), []byte("Bob"), 16)
+	assert.Equal(t, "VqqN6vgjbSBcIijNcacQGg", base64.RawURLEncoding.EncodeToString(derived))
+}
+
+func TestConcatKDF_MultipleRounds(t *testing.T) {
+	sharedSecret := []byte("shared secret")
+
+	derived := concatKDF(sharedSecret, "A256GCM", nil, nil, 48)
+	assert.Equal(t, 48, len(derived))
+
+	// keydatalen is part of OtherInfo so a shorter key is not a prefix of a longer one
+	shorter := concatKDF(sharedSecret, "A256GCM", nil, nil, 32)
+	assert.NotEqual(t, derived[:32], shorter)
+}
@@ -1,16 +1,36 @@
-// Package jwk implements a subset of the JSON Web Key spec (https://tools.ietf.org/html/rfc7517)
+// Package jwk implements the JSON Web Key spec (https://tools.ietf.org/html/rfc7517)
 package jwk
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+
+	"github.com/tbd54566975/web5-go/jcs"
+)
+
+// Key types as defined in https://www.rfc-editor.org/rfc/rfc7518.html#section-6.1 and
+// https://www.rfc-editor.org/rfc/rfc8037.html#section-2
+const (
+	KeyTypeEC  = "EC"
+	KeyTypeRSA = "RSA"
+	KeyTypeOct = "oct"
+	KeyTypeOKP = "OKP"
 )
 
-// JWK represents a JSON Web Key as per RFC7517 (https://tools.ietf.org/html/rfc7517)
-// Note that this is a subset of the spec. There are a handful of properties that the
-// spec allows for that are not represented here at the moment. This is because we
-// only need a subset of the spec for our purposes.
+// JWK represents a JSON Web Key as per RFC7517 (https://tools.ietf.org/html/rfc7517) along with the
+// key type specific members defined in RFC7518 (https://tools.ietf.org/html/rfc7518#section-6) and
+// RFC8037 (https://tools.ietf.org/html/rfc8037#section-2).
+//
+// Members that are not represented by a field are kept in Extra so that keys from other systems
+// survive a round-trip through this type. Because of Extra and the slice fields, JWK values can't be
+// compared with ==. Use [JWK.Equal] instead.
 type JWK struct {
 	ALG string `json:"alg,omitempty"`
 	KTY string `json:"kty,omitempty"`
@@ -18,21 +38,278 @@ type JWK struct {
 	D   string `json:"d,omitempty"`
 	X   string `json:"x,omitempty"`
 	Y   string `json:"y,omitempty"`
+
+	// KID is the key id (https://tools.ietf.org/html/rfc7517#section-4.5)
+	KID string `json:"kid,omitempty"`
+	// USE is the intended use of the public key, typically "sig" or "enc"
+	// (https://tools.ietf.org/html/rfc7517#section-4.2)
+	USE string `json:"use,omitempty"`
+	// KeyOps are the operations the key is intended for (https://tools.ietf.org/html/rfc7517#section-4.3)
+	KeyOps []string `json:"key_ops,omitempty"`
+
+	X5U     string   `json:"x5u,omitempty"`
+	X5C     []string `json:"x5c,omitempty"`
+	X5T     string   `json:"x5t,omitempty"`
+	X5TS256 string   `json:"x5t#S256,omitempty"`
+
+	// RSA members (https://tools.ietf.org/html/rfc7518#section-6.3)
+	N   string           `json:"n,omitempty"`
+	E   string           `json:"e,omitempty"`
+	P   string           `json:"p,omitempty"`
+	Q   string           `json:"q,omitempty"`
+	DP  string           `json:"dp,omitempty"`
+	DQ  string           `json:"dq,omitempty"`
+	QI  string           `json:"qi,omitempty"`
+	OTH []OtherPrimeInfo `json:"oth,omitempty"`
+
+	// K is the symmetric key value of an "oct" key (https://tools.ietf.org/html/rfc7518#section-6.4)
+	K string `json:"k,omitempty"`
+
+	// Extra holds members that are not represented by any of the fields above
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// OtherPrimeInfo describes a prime beyond the first two of a multi-prime RSA private key
+// (https://tools.ietf.org/html/rfc7518#section-6.3.2.7)
+type OtherPrimeInfo struct {
+	R string `json:"r"`
+	D string `json:"d"`
+	T string `json:"t"`
+}
+
+// knownMembers are the JSON member names of all fields of [JWK]
+var knownMembers = map[string]bool{
+	"alg": true, "kty": true, "crv": true, "d": true, "x": true, "y": true,
+	"kid": true, "use": true, "key_ops": true,
+	"x5u": true, "x5c": true, "x5t": true, "x5t#S256": true,
+	"n": true, "e": true, "p": true, "q": true, "dp": true, "dq": true, "qi": true, "oth": true,
+	"k": true,
+}
+
+// jwkAlias has the same fields as [JWK] without its methods to prevent infinite recursion when (un)marshaling
+type jwkAlias JWK
+
+// MarshalJSON marshals the JWK, including any members held in Extra
+func (j JWK) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(jwkAlias(j))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(j.Extra) == 0 {
+		return data, nil
+	}
+
+	names := make([]string, 0, len(j.Extra))
+	for name := range j.Extra {
+		if !knownMembers[name] {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for i, name := range names {
+		if i > 0 || len(data) > 2 {
+			buf.WriteByte(',')
+		}
+
+		nameJSON, _ := json.Marshal(name)
+		buf.Write(nameJSON)
+		buf.WriteByte(':')
+
+		if err := json.Compact(&buf, j.Extra[name]); err != nil {
+			return nil, fmt.Errorf("invalid value for member %s: %w", name, err)
+		}
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON unmarshals the JWK, keeping members that are not represented by a field in Extra
+func (j *JWK) UnmarshalJSON(data []byte) error {
+	var alias jwkAlias
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	for name := range members {
+		if knownMembers[name] {
+			delete(members, name)
+		}
+	}
+
+	alias.Extra = nil
+	if len(members) > 0 {
+		alias.Extra = members
+	}
+
+	*j = JWK(alias)
+
+	return nil
+}
+
+// Equal reports whether both JWKs have the same members. Nil and empty slices and maps are considered
+// equal, as are members in Extra whose JSON values only differ in insignificant whitespace.
+func (j JWK) Equal(other JWK) bool {
+	if !slices.Equal(j.KeyOps, other.KeyOps) || !slices.Equal(j.X5C, other.X5C) || !slices.Equal(j.OTH, other.OTH) {
+		return false
+	}
+
+	equalJSON := func(a, b json.RawMessage) bool {
+		var compactA, compactB bytes.Buffer
+		if json.Compact(&compactA, a) != nil || json.Compact(&compactB, b) != nil {
+			return bytes.Equal(a, b)
+		}
+
+		return bytes.Equal(compactA.Bytes(), compactB.Bytes())
+	}
+
+	if !maps.EqualFunc(j.Extra, other.Extra, equalJSON) {
+		return false
+	}
+
+	return j.ALG == other.ALG && j.KTY == other.KTY && j.CRV == other.CRV &&
+		j.D == other.D && j.X == other.X && j.Y == other.Y &&
+		j.KID == other.KID && j.USE == other.USE &&
+		j.X5U == other.X5U && j.X5T == other.X5T && j.X5TS256 == other.X5TS256 &&
+		j.N == other.N && j.E == other.E && j.P == other.P && j.Q == other.Q &&
+		j.DP == other.DP && j.DQ == other.DQ && j.QI == other.QI &&
+		j.K == other.K
+}
+
+// IsPrivate informs as to whether the JWK contains private key material
+func (j JWK) IsPrivate() bool {
+	return j.D != "" || j.K != ""
+}
+
+// Validate checks that the JWK contains the members required for its key type and that all
+// base64url encoded members can be decoded.
+func (j JWK) Validate() error {
+	var required []string
+	switch j.KTY {
+	case "":
+		return errors.New("kty is required")
+	case KeyTypeEC:
+		required = []string{"crv", "x", "y"}
+	case KeyTypeOKP:
+		required = []string{"crv", "x"}
+		if j.Y != "" {
+			return errors.New("y is not allowed for OKP keys")
+		}
+	case KeyTypeRSA:
+		required = []string{"n", "e"}
+		if err := j.validateRSAPrivateMembers(); err != nil {
+			return err
+		}
+	case KeyTypeOct:
+		required = []string{"k"}
+	default:
+		return fmt.Errorf("unsupported key type: %s", j.KTY)
+	}
+
+	members := j.members()
+	for _, name := range required {
+		if members[name] == "" {
+			return fmt.Errorf("%s is required for %s keys", name, j.KTY)
+		}
+	}
+
+	for name, value := range members {
+		if name == "kty" || name == "crv" || value == "" {
+			continue
+		}
+
+		if _, err := base64.RawURLEncoding.DecodeString(value); err != nil {
+			return fmt.Errorf("%s is not base64url encoded: %w", name, err)
+		}
+	}
+
+	seen := make(map[string]bool, len(j.KeyOps))
+	for _, op := range j.KeyOps {
+		if seen[op] {
+			return fmt.Errorf("duplicate key_ops value: %s", op)
+		}
+
+		seen[op] = true
+	}
+
+	return nil
+}
+
+// validateRSAPrivateMembers checks that either none or all of the optional RSA private key members are present
+// as required by https://tools.ietf.org/html/rfc7518#section-6.3.2
+func (j JWK) validateRSAPrivateMembers() error {
+	optional := []string{j.P, j.Q, j.DP, j.DQ, j.QI}
+
+	present := 0
+	for _, value := range optional {
+		if value != "" {
+			present++
+		}
+	}
+
+	if present == 0 {
+		return nil
+	}
+
+	if j.D == "" {
+		return errors.New("d is required for RSA private keys")
+	}
+
+	if present != len(optional) {
+		return errors.New("p, q, dp, dq and qi must either all be present or all be absent for RSA private keys")
+	}
+
+	return nil
+}
+
+// members returns the key type specific members that hold key material, keyed by their JSON member name
+func (j JWK) members() map[string]string {
+	return map[string]string{
+		"kty": j.KTY, "crv": j.CRV, "d": j.D, "x": j.X, "y": j.Y,
+		"n": j.N, "e": j.E, "p": j.P, "q": j.Q, "dp": j.DP, "dq": j.DQ, "qi": j.QI,
+		"k": j.K,
+	}
 }
 
 // ComputeThumbprint computes the JWK thumbprint as per RFC7638 (https://tools.ietf.org/html/rfc7638)
+// using the required members of the JWK's key type
 func (j JWK) ComputeThumbprint() (string, error) {
-	thumbprintPayload := map[string]interface{}{
-		"crv": j.CRV,
-		"kty": j.KTY,
-		"x":   j.X,
+	var required []string
+	switch j.KTY {
+	case KeyTypeEC:
+		required = []string{"crv", "kty", "x", "y"}
+	case KeyTypeOKP:
+		required = []string{"crv", "kty", "x"}
+	case KeyTypeRSA:
+		required = []string{"e", "kty", "n"}
+	case KeyTypeOct:
+		required = []string{"k", "kty"}
+	default:
+		return "", fmt.Errorf("unsupported key type: %s", j.KTY)
 	}
 
-	if j.Y != "" {
-		thumbprintPayload["y"] = j.Y
+	members := j.members()
+	thumbprintPayload := make(map[string]string, len(required))
+	for _, name := range required {
+		if members[name] == "" {
+			return "", fmt.Errorf("%s is required to compute the thumbprint of %s keys", name, j.KTY)
+		}
+
+		thumbprintPayload[name] = members[name]
 	}
 
-	bytes, err := json.Marshal(thumbprintPayload)
+	// the thumbprint is computed over the canonical form of the required members, as per https://www.rfc-editor.org/rfc/rfc7638#section-3.3
+	bytes, err := jcs.Marshal(thumbprintPayload)
 	if err != nil {
 		return "", err
 	}
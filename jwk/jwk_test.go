@@ -1 +1,156 @@
 package jwk_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+func TestComputeThumbprint_RSA(t *testing.T) {
+	// https://www.rfc-editor.org/rfc/rfc7638.html#section-3.1
+	key := jwk.JWK{
+		KTY: jwk.KeyTypeRSA,
+		N:   "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+		E:   "AQAB",
+		ALG: "RS256",
+		KID: "2011-04-29",
+	}
+
+	thumbprint, err := key.ComputeThumbprint()
+	assert.NoError(t, err)
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", thumbprint)
+}
+
+func TestComputeThumbprint_OKP(t *testing.T) {
+	// https://www.rfc-editor.org/rfc/rfc8037.html#appendix-A.3
+	key := jwk.JWK{
+		KTY: jwk.KeyTypeOKP,
+		CRV: "Ed25519",
+		X:   "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo",
+	}
+
+	thumbprint, err := key.ComputeThumbprint()
+	assert.NoError(t, err)
+	assert.Equal(t, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", thumbprint)
+}
+
+func TestComputeThumbprint_MissingMember(t *testing.T) {
+	_, err := jwk.JWK{KTY: jwk.KeyTypeEC, CRV: "P-256", X: "AQAB"}.ComputeThumbprint()
+	assert.Error(t, err)
+
+	_, err = jwk.JWK{KTY: "unknown"}.ComputeThumbprint()
+	assert.Error(t, err)
+}
+
+func TestJWK_RoundTrip(t *testing.T) {
+	input := `{"kty":"EC","crv":"P-256","x":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU","y":"x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0","kid":"key-1","use":"sig","key_ops":["verify"],"x5t#S256":"AQAB","custom":{"nested":true},"exp":1700000000}`
+
+	var key jwk.JWK
+	assert.NoError(t, json.Unmarshal([]byte(input), &key))
+
+	assert.Equal(t, "key-1", key.KID)
+	assert.Equal(t, "sig", key.USE)
+	assert.Equal(t, []string{"verify"}, key.KeyOps)
+	assert.Equal(t, "AQAB", key.X5TS256)
+	assert.Equal(t, 2, len(key.Extra))
+
+	output, err := json.Marshal(key)
+	assert.NoError(t, err)
+
+	var expected, actual map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(input), &expected))
+	assert.NoError(t, json.Unmarshal(output, &actual))
+	assert.Equal(t, expected, actual)
+}
+
+func TestJWK_Equal(t *testing.T) {
+	input := `{"kty":"EC","crv":"P-256","x":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU","y":"x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0","key_ops":["verify"],"custom":{"nested":true}}`
+
+	var a, b jwk.JWK
+	assert.NoError(t, json.Unmarshal([]byte(input), &a))
+	assert.NoError(t, json.Unmarshal([]byte(input), &b))
+	assert.True(t, a.Equal(b))
+
+	// insignificant whitespace in extra members is ignored
+	b.Extra["custom"] = json.RawMessage(`{ "nested": true }`)
+	assert.True(t, a.Equal(b))
+
+	b.Extra["custom"] = json.RawMessage(`{"nested":false}`)
+	assert.False(t, a.Equal(b))
+
+	assert.True(t, jwk.JWK{KeyOps: []string{}}.Equal(jwk.JWK{}))
+
+	// every member takes part in the comparison
+	fields := reflect.TypeOf(jwk.JWK{})
+	for i := 0; i < fields.NumField(); i++ {
+		var other jwk.JWK
+		field := reflect.ValueOf(&other).Elem().Field(i)
+
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString("AQAB")
+		case reflect.Slice:
+			field.Set(reflect.MakeSlice(field.Type(), 1, 1))
+		case reflect.Map:
+			field.Set(reflect.ValueOf(map[string]json.RawMessage{"custom": json.RawMessage(`1`)}))
+		default:
+			t.Fatalf("unexpected kind of field %s", fields.Field(i).Name)
+		}
+
+		assert.False(t, jwk.JWK{}.Equal(other), fields.Field(i).Name)
+	}
+}
+
+func TestJWK_MarshalExtraOnly(t *testing.T) {
+	key := jwk.JWK{Extra: map[string]json.RawMessage{"custom": json.RawMessage(`"value"`)}}
+
+	output, err := json.Marshal(key)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"custom":"value"}`, string(output))
+}
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		key   jwk.JWK
+		valid bool
+	}{
+		"ec":               {key: jwk.JWK{KTY: jwk.KeyTypeEC, CRV: "P-256", X: "AQAB", Y: "AQAB"}, valid: true},
+		"ec missing y":     {key: jwk.JWK{KTY: jwk.KeyTypeEC, CRV: "P-256", X: "AQAB"}},
+		"okp":              {key: jwk.JWK{KTY: jwk.KeyTypeOKP, CRV: "Ed25519", X: "AQAB"}, valid: true},
+		"okp with y":       {key: jwk.JWK{KTY: jwk.KeyTypeOKP, CRV: "Ed25519", X: "AQAB", Y: "AQAB"}},
+		"rsa":              {key: jwk.JWK{KTY: jwk.KeyTypeRSA, N: "AQAB", E: "AQAB"}, valid: true},
+		"rsa partial crt":  {key: jwk.JWK{KTY: jwk.KeyTypeRSA, N: "AQAB", E: "AQAB", D: "AQAB", P: "AQAB"}},
+		"oct":              {key: jwk.JWK{KTY: jwk.KeyTypeOct, K: "AQAB"}, valid: true},
+		"missing kty":      {key: jwk.JWK{X: "AQAB"}},
+		"unknown kty":      {key: jwk.JWK{KTY: "unknown"}},
+		"invalid base64":   {key: jwk.JWK{KTY: jwk.KeyTypeOct, K: "not base64!"}},
+		"duplicate key_op": {key: jwk.JWK{KTY: jwk.KeyTypeOct, K: "AQAB", KeyOps: []string{"sign", "sign"}}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.key.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestJWKS_Lookup(t *testing.T) {
+	var set jwk.JWKS
+	err := json.Unmarshal([]byte(`{"keys":[{"kty":"oct","k":"AQAB","kid":"a"},{"kty":"oct","k":"AQAC","kid":"b"}]}`), &set)
+	assert.NoError(t, err)
+
+	key, ok := set.Lookup("b")
+	assert.True(t, ok)
+	assert.Equal(t, "AQAC", key.K)
+
+	_, ok = set.Lookup("c")
+	assert.False(t, ok)
+}
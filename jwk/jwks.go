@@ -0,0 +1,17 @@
+package jwk
+
+// JWKS represents a JSON Web Key Set as per RFC7517 (https://tools.ietf.org/html/rfc7517#section-5)
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// Lookup returns the first key in the set with the given key id
+func (s JWKS) Lookup(kid string) (JWK, bool) {
+	for _, key := range s.Keys {
+		if key.KID == kid {
+			return key, true
+		}
+	}
+
+	return JWK{}, false
+}
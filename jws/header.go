@@ -0,0 +1,212 @@
+package jws
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+// Header represents a JWS (JSON Web Signature) header. See [Specification] for more details.
+// [Specification]: https://datatracker.ietf.org/doc/html/rfc7515#section-4
+type Header struct {
+	// Ide	ntifies the cryptographic algorithm used to secure the JWS. The JWS Signature value is not
+	// valid if the "alg" value does not represent a supported algorithm or if there is not a key for
+	// use with that algorithm associated with the party that digitally signed or MACed the content.
+	//
+	// "alg" values should either be registered in the IANA "JSON Web Signature and Encryption
+	// Algorithms" registry or be a value that contains a Collision-Resistant Name. The "alg" value is
+	// a case-sensitive ASCII string.  This Header Parameter MUST be present and MUST be understood
+	// and processed by implementations.
+	//
+	// [Specification]: https://datatracker.ietf.org/doc/html/rfc7515#section-4.1.1
+	ALG string `json:"alg,omitempty"`
+	// Key ID Header Parameter https://datatracker.ietf.org/doc/html/rfc7515#section-4.1.4
+	KID string `json:"kid,omitempty"`
+	// Type Header Parameter https://datatracker.ietf.org/doc/html/rfc7515#section-4.1.9
+	TYP string `json:"typ,omitempty"`
+	// Content Type Header Parameter https://datatracker.ietf.org/doc/html/rfc7515#section-4.1.10
+	CTY string `json:"cty,omitempty"`
+	// JSON Web Key Header Parameter https://datatracker.ietf.org/doc/html/rfc7515#section-4.1.3.
+	// Must be a public key
+	JWK *jwk.JWK `json:"jwk,omitempty"`
+	// X.509 Certificate Chain Header Parameter https://datatracker.ietf.org/doc/html/rfc7515#section-4.1.6
+	X5C []string `json:"x5c,omitempty"`
+	// Nonce Header Parameter https://datatracker.ietf.org/doc/html/rfc8555#section-6.5.2
+	NONCE string `json:"nonce,omitempty"`
+	// URL Header Parameter https://datatracker.ietf.org/doc/html/rfc8555#section-6.4.1
+	URL string `json:"url,omitempty"`
+	// Base64url-Encode Payload Header Parameter https://datatracker.ietf.org/doc/html/rfc7797#section-3.
+	// nil means true
+	B64 *bool `json:"b64,omitempty"`
+	// Critical Header Parameter https://datatracker.ietf.org/doc/html/rfc7515#section-4.1.11
+	CRIT []string `json:"crit,omitempty"`
+
+	// Extra holds custom header parameters that are not represented by any of the fields above
+	Extra map[string]any `json:"-"`
+}
+
+// knownHeaderParams are the JSON member names of all fields of [Header]
+var knownHeaderParams = map[string]bool{
+	"alg": true, "kid": true, "typ": true, "cty": true, "jwk": true, "x5c": true,
+	"nonce": true, "url": true, "b64": true, "crit": true,
+}
+
+// registeredHeaderParams are the header parameters defined by the JWS and JWA specs, which must not be
+// listed as critical, as per https://datatracker.ietf.org/doc/html/rfc7515#section-4.1.11
+var registeredHeaderParams = map[string]bool{
+	"alg": true, "jku": true, "jwk": true, "kid": true, "x5u": true, "x5c": true, "x5t": true, "x5t#S256": true,
+	"typ": true, "cty": true, "crit": true,
+	"enc": true, "zip": true, "epk": true, "apu": true, "apv": true, "iv": true, "tag": true, "p2s": true, "p2c": true,
+}
+
+// headerAlias has the same fields as [Header] without its methods to prevent infinite recursion when (un)marshaling
+type headerAlias Header
+
+// MarshalJSON marshals the header, including any custom parameters held in Extra
+func (j Header) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(headerAlias(j))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(j.Extra) == 0 {
+		return data, nil
+	}
+
+	names := make([]string, 0, len(j.Extra))
+	for name := range j.Extra {
+		if knownHeaderParams[name] {
+			return nil, fmt.Errorf("custom header parameter %s conflicts with a field of Header", name)
+		}
+
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for i, name := range names {
+		if i > 0 || len(data) > 2 {
+			buf.WriteByte(',')
+		}
+
+		nameJSON, _ := json.Marshal(name)
+		buf.Write(nameJSON)
+		buf.WriteByte(':')
+
+		valueJSON, err := json.Marshal(j.Extra[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for header parameter %s: %w", name, err)
+		}
+		buf.Write(valueJSON)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON unmarshals the header, keeping parameters that are not represented by a field in Extra
+func (j *Header) UnmarshalJSON(data []byte) error {
+	var alias headerAlias
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return err
+	}
+
+	var params map[string]any
+	if err := json.Unmarshal(data, &params); err != nil {
+		return err
+	}
+
+	// an explicit null must not pass for an absent crit header, see [Header.CheckCritical]
+	if _, ok := params["crit"]; ok && alias.CRIT == nil {
+		alias.CRIT = []string{}
+	}
+
+	for name := range params {
+		if knownHeaderParams[name] {
+			delete(params, name)
+		}
+	}
+
+	alias.Extra = nil
+	if len(params) > 0 {
+		alias.Extra = params
+	}
+
+	*j = Header(alias)
+
+	return nil
+}
+
+// Encode returns the base64url encoded header.
+func (j Header) Encode() (string, error) {
+	bytes, err := json.Marshal(j)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// CheckCritical returns an error if the header lists critical parameters that aren't understood, or if the
+// crit header is empty or lists a parameter more than once, as per
+// https://datatracker.ietf.org/doc/html/rfc7515#section-4.1.11. b64 is always understood, any other
+// extension has to be passed in. It is called by [Decode] with the extensions passed to [AllowCritical]
+func (j Header) CheckCritical(understood ...string) error {
+	if j.CRIT != nil && len(j.CRIT) == 0 {
+		return errors.New("crit header must not be empty")
+	}
+
+	for i, name := range j.CRIT {
+		if slices.Contains(j.CRIT[:i], name) {
+			return fmt.Errorf("crit header must not list %s more than once", name)
+		}
+
+		if registeredHeaderParams[name] {
+			return fmt.Errorf("crit header must not list %s, which is defined by the JWS spec", name)
+		}
+
+		if !j.has(name) {
+			return fmt.Errorf("critical header parameter %s is missing", name)
+		}
+
+		if name != "b64" && !slices.Contains(understood, name) {
+			return fmt.Errorf("unsupported critical header parameter %s", name)
+		}
+	}
+
+	// b64 must be critical when present, as per https://datatracker.ietf.org/doc/html/rfc7797#section-6
+	if j.B64 != nil && !slices.Contains(j.CRIT, "b64") {
+		return errors.New("b64 header must be listed in crit header")
+	}
+
+	return nil
+}
+
+// has returns whether the header contains the parameter with the given name
+func (j Header) has(name string) bool {
+	switch name {
+	case "b64":
+		return j.B64 != nil
+	case "nonce":
+		return j.NONCE != ""
+	case "url":
+		return j.URL != ""
+	}
+
+	_, ok := j.Extra[name]
+
+	return ok
+}
+
+// isUnencoded returns whether the payload is signed without base64url encoding it, as per RFC 7797
+func (j Header) isUnencoded() bool {
+	return j.B64 != nil && !*j.B64
+}
@@ -0,0 +1,78 @@
+package jws_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/jws"
+)
+
+func TestHeader_JSON(t *testing.T) {
+	header := jws.Header{
+		ALG:   "EdDSA",
+		KID:   "did:web:example.com#0",
+		CTY:   "application/json",
+		X5C:   []string{"MIIE"},
+		NONCE: "abc",
+		URL:   "https://example.com/acme",
+		CRIT:  []string{"exp"},
+		Extra: map[string]any{"exp": float64(1363284000), "role": "notary"},
+	}
+
+	bytes, err := json.Marshal(header)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"alg":"EdDSA","kid":"did:web:example.com#0","cty":"application/json","x5c":["MIIE"],"nonce":"abc","url":"https://example.com/acme","crit":["exp"],"exp":1363284000,"role":"notary"}`, string(bytes))
+
+	var decoded jws.Header
+	err = json.Unmarshal(bytes, &decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, header, decoded)
+
+	_, err = json.Marshal(jws.Header{Extra: map[string]any{"kid": "nope"}})
+	assert.Error(t, err)
+}
+
+func TestHeader_CheckCritical(t *testing.T) {
+	b64 := false
+
+	vectors := []struct {
+		name       string
+		header     jws.Header
+		understood []string
+		valid      bool
+	}{
+		{name: "no crit", header: jws.Header{}, valid: true},
+		{name: "b64", header: jws.Header{B64: &b64, CRIT: []string{"b64"}}, valid: true},
+		{name: "understood extension", header: jws.Header{CRIT: []string{"exp"}, Extra: map[string]any{"exp": 1}}, understood: []string{"exp"}, valid: true},
+		{name: "unknown extension", header: jws.Header{CRIT: []string{"exp"}, Extra: map[string]any{"exp": 1}}},
+		{name: "missing extension", header: jws.Header{CRIT: []string{"exp"}}, understood: []string{"exp"}},
+		{name: "registered parameter", header: jws.Header{KID: "did:web:example.com#0", CRIT: []string{"kid"}}},
+		{name: "empty crit", header: jws.Header{CRIT: []string{}}},
+		{name: "b64 not critical", header: jws.Header{B64: &b64}},
+		{name: "duplicate b64", header: jws.Header{B64: &b64, CRIT: []string{"b64", "b64"}}},
+		{name: "duplicate extension", header: jws.Header{CRIT: []string{"exp", "exp"}, Extra: map[string]any{"exp": 1}}, understood: []string{"exp"}},
+	}
+
+	for _, v := range vectors {
+		t.Run(v.name, func(t *testing.T) {
+			err := v.header.CheckCritical(v.understood...)
+			if v.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestHeader_CheckCritical_Decoded(t *testing.T) {
+	for _, data := range []string{`{"alg":"EdDSA","crit":[]}`, `{"alg":"EdDSA","crit":null}`} {
+		var header jws.Header
+		err := json.Unmarshal([]byte(data), &header)
+		assert.NoError(t, err)
+
+		err = header.CheckCritical()
+		assert.Error(t, err, data)
+	}
+}
@@ -0,0 +1,264 @@
+package jws
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	_did "github.com/tbd54566975/web5-go/dids/did"
+)
+
+// Signer is one of the signers of a JWS JSON serialization produced by [SignJSON]
+type Signer struct {
+	// BearerDID is the DID to sign with
+	BearerDID _did.BearerDID
+	// Opts select the key to sign with and set protected header values for this signer only, like for [Sign].
+	// They're applied after the options passed to [SignJSON]
+	Opts []SignOpt
+	// Header is the signer's unprotected header. Its parameters must not overlap with the protected header,
+	// which always contains alg and kid
+	Header map[string]any
+}
+
+// Flattened is an option that can be passed to [SignJSON] to produce a [flattened JWS JSON serialization].
+// Only possible with a single signer. Ignored by [Sign]
+//
+// [flattened JWS JSON serialization]: https://datatracker.ietf.org/doc/html/rfc7515#section-7.2.2
+func Flattened(flattened bool) SignOpt {
+	return func(opts *signOpts) {
+		opts.flattened = flattened
+	}
+}
+
+// jsonSignature is a single signature of a JWS JSON serialization
+type jsonSignature struct {
+	Protected string         `json:"protected,omitempty"`
+	Header    map[string]any `json:"header,omitempty"`
+	Signature string         `json:"signature"`
+}
+
+// generalJSON is the general JWS JSON serialization as per https://datatracker.ietf.org/doc/html/rfc7515#section-7.2.1
+type generalJSON struct {
+	Payload    *string         `json:"payload,omitempty"`
+	Signatures []jsonSignature `json:"signatures"`
+}
+
+// flattenedJSON is the flattened JWS JSON serialization as per https://datatracker.ietf.org/doc/html/rfc7515#section-7.2.2
+type flattenedJSON struct {
+	Payload *string `json:"payload,omitempty"`
+	jsonSignature
+}
+
+// SignJSON signs the provided payload with every signer and returns a [general JWS JSON serialization],
+// e.g. to have a payload co-signed by an issuer and a notary. opts apply to all signers, e.g.
+// [DetachedPayload] to omit the payload or [Flattened] to produce a flattened JWS JSON serialization
+//
+// [general JWS JSON serialization]: https://datatracker.ietf.org/doc/html/rfc7515#section-7.2.1
+func SignJSON(payload []byte, signers []Signer, opts ...SignOpt) (string, error) {
+	o := signOpts{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if len(signers) == 0 {
+		return "", errors.New("at least one signer is required")
+	}
+
+	if o.flattened && len(signers) > 1 {
+		return "", fmt.Errorf("flattened JWS JSON serialization requires exactly 1 signer, got %d", len(signers))
+	}
+
+	base64UrlEncodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+
+	signatures := make([]jsonSignature, 0, len(signers))
+	for i, signer := range signers {
+		signerOpts := o
+		for _, opt := range signer.Opts {
+			opt(&signerOpts)
+		}
+
+		base64UrlEncodedHeader, base64UrlEncodedSignature, err := computeSignature(payload, base64UrlEncodedPayload, signer.BearerDID, signerOpts)
+		if err != nil {
+			return "", fmt.Errorf("signer %d: %w", i, err)
+		}
+
+		signature := jsonSignature{Protected: base64UrlEncodedHeader, Header: signer.Header, Signature: base64UrlEncodedSignature}
+		if err := checkDisjointHeaders(signature); err != nil {
+			return "", fmt.Errorf("signer %d: %w", i, err)
+		}
+
+		signatures = append(signatures, signature)
+	}
+
+	// the payload member is omitted for detached content
+	var payloadMember *string
+	if !o.detached {
+		payloadMember = &base64UrlEncodedPayload
+	}
+
+	var serialized any
+	if o.flattened {
+		serialized = flattenedJSON{Payload: payloadMember, jsonSignature: signatures[0]}
+	} else {
+		serialized = generalJSON{Payload: payloadMember, Signatures: signatures}
+	}
+
+	bytes, err := json.Marshal(serialized)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize JWS: %w", err)
+	}
+
+	return string(bytes), nil
+}
+
+// DecodedJSON is a JWS JSON serialization decoded into its signatures
+type DecodedJSON struct {
+	Payload []byte
+	// Signatures are the decoded signatures, in the order they appear in the JWS. Every signature can be
+	// verified on its own with [Decoded.Verify]
+	Signatures []Decoded
+}
+
+// DecodeJSON decodes the given general or flattened JWS JSON serialization into a [DecodedJSON] type.
+// Every signature's protected header must contain alg and kid
+func DecodeJSON(jwsJSON string, opts ...DecodeOption) (DecodedJSON, error) {
+	o := decodeOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var raw struct {
+		Payload    *string         `json:"payload"`
+		Signatures []jsonSignature `json:"signatures"`
+		jsonSignature
+	}
+
+	if err := json.Unmarshal([]byte(jwsJSON), &raw); err != nil {
+		return DecodedJSON{}, fmt.Errorf("malformed JWS JSON serialization: %w", err)
+	}
+
+	signatures := raw.Signatures
+	switch {
+	case signatures != nil && (raw.Protected != "" || raw.Header != nil || raw.Signature != ""):
+		return DecodedJSON{}, errors.New("malformed JWS JSON serialization. signatures must not be combined with flattened members")
+	case signatures == nil:
+		signatures = []jsonSignature{raw.jsonSignature}
+	}
+
+	if len(signatures) == 0 {
+		return DecodedJSON{}, errors.New("malformed JWS JSON serialization. Expected at least 1 signature")
+	}
+
+	// a detached payload is passed on to Decode as such, since unencoded payloads (b64=false) must be detached
+	var payload []byte
+	var base64UrlEncodedPayload string
+	switch {
+	case o.payload != nil:
+		payload = o.payload
+	case raw.Payload != nil:
+		var err error
+		payload, err = base64.RawURLEncoding.DecodeString(*raw.Payload)
+		if err != nil {
+			return DecodedJSON{}, fmt.Errorf("malformed JWS JSON serialization. Failed to decode payload: %w", err)
+		}
+		base64UrlEncodedPayload = *raw.Payload
+	default:
+		return DecodedJSON{}, errors.New("malformed JWS JSON serialization. payload is detached but none was provided")
+	}
+
+	decoded := DecodedJSON{Payload: payload, Signatures: make([]Decoded, 0, len(signatures))}
+	for i, s := range signatures {
+		if err := checkDisjointHeaders(s); err != nil {
+			return DecodedJSON{}, fmt.Errorf("malformed JWS JSON serialization. signature %d: %w", i, err)
+		}
+
+		// reuse compact decoding for the protected header, signature and kid
+		d, err := Decode(s.Protected+"."+base64UrlEncodedPayload+"."+s.Signature, opts...)
+		if err != nil {
+			return DecodedJSON{}, fmt.Errorf("signature %d: %w", i, err)
+		}
+
+		d.UnprotectedHeader = s.Header
+		decoded.Signatures = append(decoded.Signatures, d)
+	}
+
+	return decoded, nil
+}
+
+// VerifySignatures verifies every signature and returns an error for each of them, in the same order as
+// [DecodedJSON.Signatures]. A nil error means that the signature at that index was verified successfully.
+// Only the [Keys] and [Resolver] options apply here. [Keys] takes precedence over [Resolver]
+func (jws DecodedJSON) VerifySignatures(opts ...DecodeOption) []error {
+	return jws.VerifySignaturesWithContext(context.Background(), opts...)
+}
+
+// VerifySignaturesWithContext verifies every signature like [DecodedJSON.VerifySignatures]. The context is
+// passed on to DID resolution
+func (jws DecodedJSON) VerifySignaturesWithContext(ctx context.Context, opts ...DecodeOption) []error {
+	return VerifyAllWithContext(ctx, jws.Signatures, opts...)
+}
+
+// Verify verifies all signatures. An error is returned if any of them fails to verify. Use
+// [DecodedJSON.VerifySignatures] to find out which signatures verified
+func (jws DecodedJSON) Verify(opts ...DecodeOption) error {
+	return jws.VerifyWithContext(context.Background(), opts...)
+}
+
+// VerifyWithContext verifies all signatures like [DecodedJSON.Verify]. The context is passed on to DID resolution
+func (jws DecodedJSON) VerifyWithContext(ctx context.Context, opts ...DecodeOption) error {
+	var errs []error
+	for i, err := range jws.VerifySignaturesWithContext(ctx, opts...) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("signature %d (%s): %w", i, jws.Signatures[i].Header.KID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// VerifyJSON decodes the given JWS JSON serialization and verifies all of its signatures. See [DecodeJSON]
+// and [DecodedJSON.Verify]
+func VerifyJSON(jwsJSON string, opts ...DecodeOption) (DecodedJSON, error) {
+	return VerifyJSONWithContext(context.Background(), jwsJSON, opts...)
+}
+
+// VerifyJSONWithContext decodes the given JWS JSON serialization and verifies all of its signatures like
+// [VerifyJSON]. The context is passed on to DID resolution
+func VerifyJSONWithContext(ctx context.Context, jwsJSON string, opts ...DecodeOption) (DecodedJSON, error) {
+	decodedJWS, err := DecodeJSON(jwsJSON, opts...)
+	if err != nil {
+		return decodedJWS, fmt.Errorf("signature verification failed: %w", err)
+	}
+
+	err = decodedJWS.VerifyWithContext(ctx, opts...)
+
+	return decodedJWS, err
+}
+
+// checkDisjointHeaders ensures that the protected and unprotected header of a signature don't share any
+// parameters, as per https://datatracker.ietf.org/doc/html/rfc7515#section-7.2.1
+func checkDisjointHeaders(s jsonSignature) error {
+	if len(s.Header) == 0 {
+		return nil
+	}
+
+	bytes, err := base64.RawURLEncoding.DecodeString(s.Protected)
+	if err != nil {
+		return fmt.Errorf("failed to decode protected header: %w", err)
+	}
+
+	var protected map[string]any
+	if err := json.Unmarshal(bytes, &protected); err != nil {
+		return fmt.Errorf("failed to decode protected header: %w", err)
+	}
+
+	for name := range s.Header {
+		if _, ok := protected[name]; ok {
+			return fmt.Errorf("header parameter %s is both protected and unprotected", name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package jws_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/dids/didjwk"
+	"github.com/tbd54566975/web5-go/jws"
+)
+
+func TestSignJSON(t *testing.T) {
+	issuer, err := didjwk.Create()
+	assert.NoError(t, err)
+
+	notary, err := didjwk.Create(didjwk.AlgorithmID(dsa.AlgorithmIDSECP256K1))
+	assert.NoError(t, err)
+
+	payload := []byte("hi")
+
+	jwsJSON, err := jws.SignJSON(payload, []jws.Signer{
+		{BearerDID: issuer},
+		{BearerDID: notary, Header: map[string]any{"role": "notary"}},
+	}, jws.Type("JWT"))
+	assert.NoError(t, err)
+
+	var general map[string]any
+	err = json.Unmarshal([]byte(jwsJSON), &general)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(general["signatures"].([]any)))
+
+	decoded, err := jws.VerifyJSON(jwsJSON)
+	assert.NoError(t, err)
+	assert.Equal(t, payload, decoded.Payload)
+	assert.Equal(t, 2, len(decoded.Signatures))
+
+	assert.Equal(t, issuer.URI, decoded.Signatures[0].SignerDID.URI)
+	assert.Equal(t, "JWT", decoded.Signatures[0].Header.TYP)
+	assert.Zero(t, decoded.Signatures[0].UnprotectedHeader)
+
+	assert.Equal(t, notary.URI, decoded.Signatures[1].SignerDID.URI)
+	assert.Equal(t, "ES256K", decoded.Signatures[1].Header.ALG)
+	assert.Equal(t, map[string]any{"role": "notary"}, decoded.Signatures[1].UnprotectedHeader)
+}
+
+func TestSignJSON_Flattened(t *testing.T) {
+	did, err := didjwk.Create()
+	assert.NoError(t, err)
+
+	jwsJSON, err := jws.SignJSON([]byte("hi"), []jws.Signer{{BearerDID: did}}, jws.Flattened(true))
+	assert.NoError(t, err)
+
+	var flattened map[string]any
+	err = json.Unmarshal([]byte(jwsJSON), &flattened)
+	assert.NoError(t, err)
+	assert.NotZero(t, flattened["protected"])
+	assert.NotZero(t, flattened["signature"])
+	assert.Zero(t, flattened["signatures"])
+
+	decoded, err := jws.VerifyJSON(jwsJSON)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(decoded.Signatures))
+	assert.Equal(t, did.URI, decoded.Signatures[0].SignerDID.URI)
+
+	_, err = jws.SignJSON([]byte("hi"), []jws.Signer{{BearerDID: did}, {BearerDID: did}}, jws.Flattened(true))
+	assert.Error(t, err)
+}
+
+func TestSignJSON_Detached(t *testing.T) {
+	did, err := didjwk.Create()
+	assert.NoError(t, err)
+
+	payload := []byte("hi")
+
+	jwsJSON, err := jws.SignJSON(payload, []jws.Signer{{BearerDID: did}}, jws.DetachedPayload(true))
+	assert.NoError(t, err)
+	assert.False(t, strings.Contains(jwsJSON, `"payload"`))
+
+	_, err = jws.DecodeJSON(jwsJSON)
+	assert.Error(t, err)
+
+	decoded, err := jws.VerifyJSON(jwsJSON, jws.Payload(payload))
+	assert.NoError(t, err)
+	assert.Equal(t, payload, decoded.Payload)
+
+	_, err = jws.VerifyJSON(jwsJSON, jws.Payload([]byte("bye")))
+	assert.Error(t, err)
+}
+
+func TestSignJSON_UnencodedPayload(t *testing.T) {
+	did, err := didjwk.Create()
+	assert.NoError(t, err)
+
+	payload := []byte("hi")
+
+	jwsJSON, err := jws.SignJSON(payload, []jws.Signer{{BearerDID: did}}, jws.DetachedPayload(true), jws.UnencodedPayload(true))
+	assert.NoError(t, err)
+
+	decoded, err := jws.VerifyJSON(jwsJSON, jws.Payload(payload))
+	assert.NoError(t, err)
+	assert.Equal(t, false, *decoded.Signatures[0].Header.B64)
+}
+
+func TestSignJSON_OverlappingHeaders(t *testing.T) {
+	did, err := didjwk.Create()
+	assert.NoError(t, err)
+
+	_, err = jws.SignJSON([]byte("hi"), []jws.Signer{{BearerDID: did, Header: map[string]any{"kid": "nope"}}})
+	assert.Error(t, err)
+}
+
+func TestDecodedJSON_VerifySignatures(t *testing.T) {
+	issuer, err := didjwk.Create()
+	assert.NoError(t, err)
+
+	notary, err := didjwk.Create()
+	assert.NoError(t, err)
+
+	jwsJSON, err := jws.SignJSON([]byte("hi"), []jws.Signer{{BearerDID: issuer}, {BearerDID: notary}})
+	assert.NoError(t, err)
+
+	decoded, err := jws.DecodeJSON(jwsJSON)
+	assert.NoError(t, err)
+
+	// tamper with the notary's signature
+	decoded.Signatures[1].Signature[0] ^= 0xff
+
+	errs := decoded.VerifySignatures()
+	assert.Equal(t, 2, len(errs))
+	assert.NoError(t, errs[0])
+	assert.Error(t, errs[1])
+
+	err = decoded.Verify()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "signature 1")
+}
+
+func TestDecodeJSON_Bad(t *testing.T) {
+	did, err := didjwk.Create()
+	assert.NoError(t, err)
+
+	compactJWS, err := jws.Sign([]byte("hi"), did)
+	assert.NoError(t, err)
+
+	_, err = jws.Decode(`{"payload":"aGk","signatures":[]}`)
+	assert.Error(t, err)
+
+	badInput := []string{
+		compactJWS,
+		`{"payload":"aGk","signatures":[]}`,
+		`{"payload":"aGk","signatures":[{"protected":"e30","signature":"aGk"}],"signature":"aGk"}`,
+		`{"payload":"aGk","protected":"e30","signature":"aGk"}`,
+		`{"payload":"!!!","protected":"e30","signature":"aGk"}`,
+	}
+
+	for _, input := range badInput {
+		_, err := jws.DecodeJSON(input)
+		assert.Error(t, err, input)
+	}
+}
@@ -1,23 +1,27 @@
 package jws
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/crypto/dsa/ecdsa"
 	"github.com/tbd54566975/web5-go/dids"
 	_did "github.com/tbd54566975/web5-go/dids/did"
 	"github.com/tbd54566975/web5-go/dids/didcore"
+	"github.com/tbd54566975/web5-go/jwk"
 )
 
 // Decode decodes the given JWS string into a [Decoded] type
 //
 // # Note
 //
-// The given JWS input is assumed to be a [compact JWS]
+// The given JWS input is assumed to be a [compact JWS]. Use [DecodeJSON] for the JWS JSON serialization
 //
 // [compact JWS]: https://datatracker.ietf.org/doc/html/rfc7515#section-7.1
 func Decode(jws string, opts ...DecodeOption) (Decoded, error) {
@@ -27,6 +31,10 @@ func Decode(jws string, opts ...DecodeOption) (Decoded, error) {
 		opt(&o)
 	}
 
+	if strings.HasPrefix(strings.TrimSpace(jws), "{") {
+		return Decoded{}, errors.New("malformed JWS. Expected compact serialization, use DecodeJSON for JSON serialization")
+	}
+
 	parts := strings.Split(jws, ".")
 	if len(parts) != 3 {
 		return Decoded{}, fmt.Errorf("malformed JWS. Expected 3 parts, got %d", len(parts))
@@ -37,13 +45,24 @@ func Decode(jws string, opts ...DecodeOption) (Decoded, error) {
 		return Decoded{}, fmt.Errorf("malformed JWS. Failed to decode header: %w", err)
 	}
 
+	if err := header.CheckCritical(o.critical...); err != nil {
+		return Decoded{}, fmt.Errorf("malformed JWS. %w", err)
+	}
+
 	var payload []byte
-	if o.payload == nil {
+	switch {
+	case header.isUnencoded():
+		// the signing input contains the raw payload, which can't be part of a compact JWS
+		if o.payload == nil || parts[1] != "" {
+			return Decoded{}, errors.New("malformed JWS. Unencoded payloads (b64=false) must be detached and provided using the Payload option")
+		}
+		payload = o.payload
+	case o.payload == nil:
 		payload, err = base64.RawURLEncoding.DecodeString(parts[1])
 		if err != nil {
 			return Decoded{}, fmt.Errorf("malformed JWS. Failed to decode payload: %w", err)
 		}
-	} else {
+	default:
 		payload = o.payload
 		parts[1] = base64.RawURLEncoding.EncodeToString(payload)
 	}
@@ -53,13 +72,19 @@ func Decode(jws string, opts ...DecodeOption) (Decoded, error) {
 		return Decoded{}, fmt.Errorf("malformed JWS. Failed to decode signature: %w", err)
 	}
 
-	if header.KID == "" {
-		return Decoded{}, errors.New("malformed JWS. Expected header to contain kid")
-	}
+	var signerDID _did.DID
+	if o.keys == nil {
+		if header.KID == "" {
+			return Decoded{}, errors.New("malformed JWS. Expected header to contain kid")
+		}
 
-	signerDID, err := _did.Parse(header.KID)
-	if err != nil {
-		return Decoded{}, fmt.Errorf("malformed JWS. Failed to parse kid: %w", err)
+		signerDID, err = _did.Parse(header.KID)
+		if err != nil {
+			return Decoded{}, fmt.Errorf("malformed JWS. Failed to parse kid: %w", err)
+		}
+	} else if parsed, err := _did.Parse(header.KID); err == nil {
+		// keys are looked up by other means, so the kid doesn't have to be a DID URL
+		signerDID = parsed
 	}
 
 	return Decoded{
@@ -72,7 +97,19 @@ func Decode(jws string, opts ...DecodeOption) (Decoded, error) {
 }
 
 type decodeOptions struct {
-	payload []byte
+	payload  []byte
+	critical []string
+	resolver didcore.MethodResolver
+	keys     KeyLookup
+}
+
+// keyLookup returns the [KeyLookup] to verify with, which resolves the kid as a DID URL unless [Keys] was passed
+func (o decodeOptions) keyLookup() KeyLookup {
+	if o.keys != nil {
+		return o.keys
+	}
+
+	return DIDKeys(o.resolver)
 }
 
 // DecodeOption represents an option that can be passed to [Decode] or [Verify].
@@ -88,6 +125,24 @@ func Payload(p []byte) DecodeOption {
 	}
 }
 
+// AllowCritical can be passed to [Decode] or [Verify] to declare critical header parameters (extensions)
+// that the caller understands and processes itself. A JWS whose crit header lists any other extension
+// is rejected, as per https://datatracker.ietf.org/doc/html/rfc7515#section-4.1.11
+func AllowCritical(names ...string) DecodeOption {
+	return func(opts *decodeOptions) {
+		opts.critical = append(opts.critical, names...)
+	}
+}
+
+// Resolver can be passed to [Verify] or [Decoded.Verify] to resolve the signer's DID with the given resolver
+// instead of [dids.Resolve], e.g. to verify against local fixtures, a cache or a private registry without
+// network access. Ignored by [Decode]
+func Resolver(resolver didcore.MethodResolver) DecodeOption {
+	return func(opts *decodeOptions) {
+		opts.resolver = resolver
+	}
+}
+
 // DecodeHeader decodes the base64url encoded JWS header into a [Header]
 func DecodeHeader(base64UrlEncodedHeader string) (Header, error) {
 	bytes, err := base64.RawURLEncoding.DecodeString(base64UrlEncodedHeader)
@@ -116,9 +171,13 @@ func DecodeSignature(base64UrlEncodedSignature string) ([]byte, error) {
 
 // options that sign function can take
 type signOpts struct {
-	selector didcore.VMSelector
-	detached bool
-	typ      string
+	selector    didcore.VMSelector
+	detached    bool
+	typ         string
+	recoverable bool
+	flattened   bool
+	unencoded   bool
+	header      Header
 }
 
 // SignOpt is a type that represents an option that can be passed to [github.com/tbd54566975/web5-go/jws.Sign].
@@ -167,6 +226,37 @@ func Type(typ string) SignOpt {
 	}
 }
 
+// Recoverable is an option that can be passed to [github.com/tbd54566975/web5-go/jws.Sign].
+// It is used to produce an ES256K-R signature from which the signer's public key can be recovered.
+// Only supported for secp256k1 verification methods
+func Recoverable(recoverable bool) SignOpt {
+	return func(opts *signOpts) {
+		opts.recoverable = recoverable
+	}
+}
+
+// ProtectedHeader is an option that can be passed to [github.com/tbd54566975/web5-go/jws.Sign].
+// It is used to set additional protected header parameters, e.g. `cty`, `jwk`, `x5c`, `nonce`, `url`,
+// `crit` or custom parameters using Extra. alg, kid and b64 are always set by Sign and must be left empty.
+// Critical parameters listed in `crit` must be present in the header
+func ProtectedHeader(header Header) SignOpt {
+	return func(opts *signOpts) {
+		opts.header = header
+	}
+}
+
+// UnencodedPayload is an option that can be passed to [github.com/tbd54566975/web5-go/jws.Sign].
+// It is used to sign the raw payload instead of its base64url encoding, as per [RFC 7797], which avoids
+// encoding large payloads. The `b64` and `crit` JWS header values are set accordingly. Only supported
+// together with DetachedPayload(true)
+//
+// [RFC 7797]: https://datatracker.ietf.org/doc/html/rfc7797
+func UnencodedPayload(unencoded bool) SignOpt {
+	return func(opts *signOpts) {
+		opts.unencoded = unencoded
+	}
+}
+
 // Sign signs the provided payload with a key associated to the provided DID.
 // if no purpose is provided, the default is "assertionMethod". Passing Detached(true)
 // will return a compact JWS with detached content
@@ -176,130 +266,345 @@ func Sign(payload []byte, did _did.BearerDID, opts ...SignOpt) (string, error) {
 		opt(&o)
 	}
 
+	base64UrlEncodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+
+	base64UrlEncodedHeader, base64UrlEncodedSignature, err := computeSignature(payload, base64UrlEncodedPayload, did, o)
+	if err != nil {
+		return "", err
+	}
+
+	var compactJWS string
+	if o.detached {
+		compactJWS = base64UrlEncodedHeader + "." + "." + base64UrlEncodedSignature
+	} else {
+		compactJWS = base64UrlEncodedHeader + "." + base64UrlEncodedPayload + "." + base64UrlEncodedSignature
+	}
+
+	return compactJWS, nil
+}
+
+// computeSignature signs the given payload with a key associated to the provided DID and returns the
+// base64url encoded protected header and signature. base64UrlEncodedPayload is passed in to avoid encoding
+// the payload once per signature
+func computeSignature(payload []byte, base64UrlEncodedPayload string, did _did.BearerDID, o signOpts) (string, string, error) {
+	if o.unencoded && !o.detached {
+		return "", "", errors.New("unencoded payloads are only supported together with detached payloads")
+	}
+
+	header := o.header
+	if header.ALG != "" || header.KID != "" || header.B64 != nil {
+		return "", "", errors.New("alg, kid and b64 protected header parameters are set by Sign")
+	}
+
+	if header.JWK != nil && header.JWK.IsPrivate() {
+		return "", "", errors.New("jwk protected header parameter must not contain private key material")
+	}
+
 	sign, verificationMethod, err := did.GetSigner(o.selector)
 	if err != nil {
-		return "", fmt.Errorf("failed to get signer: %w", err)
+		return "", "", fmt.Errorf("failed to get signer: %w", err)
 	}
 
-	jwa, err := dsa.GetJWA(*verificationMethod.PublicKeyJwk)
+	publicKey, err := verificationMethod.PublicKey()
 	if err != nil {
-		return "", fmt.Errorf("failed to determine alg: %w", err)
+		return "", "", err
 	}
 
-	keyID := did.Document.GetAbsoluteResourceID(verificationMethod.ID)
-	header := Header{ALG: jwa, KID: keyID, TYP: o.typ}
-	base64UrlEncodedHeader, err := header.Encode()
+	jwa, err := dsa.GetJWA(publicKey)
 	if err != nil {
-		return "", fmt.Errorf("failed to base64 url encode header: %w", err)
+		return "", "", fmt.Errorf("failed to determine alg: %w", err)
 	}
 
-	base64UrlEncodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	if o.recoverable {
+		if jwa != ecdsa.SECP256K1JWA {
+			return "", "", fmt.Errorf("recoverable signatures not supported for alg: %s", jwa)
+		}
 
-	toSign := base64UrlEncodedHeader + "." + base64UrlEncodedPayload
-	toSignBytes := []byte(toSign)
+		jwa = ecdsa.SECP256K1RecoverableJWA
+	}
 
-	signature, err := sign(toSignBytes)
-	if err != nil {
-		return "", fmt.Errorf("failed to compute signature: %w", err)
+	keyID := did.Document.GetAbsoluteResourceID(verificationMethod.ID)
+	header.ALG = jwa
+	header.KID = keyID
+	if o.typ != "" {
+		header.TYP = o.typ
 	}
 
-	base64UrlEncodedSignature := base64.RawURLEncoding.EncodeToString(signature)
+	if o.unencoded {
+		b64 := false
+		header.B64 = &b64
+		header.CRIT = append(slices.Clone(header.CRIT), "b64")
+	}
 
-	var compactJWS string
-	if o.detached {
-		compactJWS = base64UrlEncodedHeader + "." + "." + base64UrlEncodedSignature
+	// the signer understands all critical parameters it sets, but they still have to be well-formed
+	if err := header.CheckCritical(header.CRIT...); err != nil {
+		return "", "", fmt.Errorf("invalid protected header: %w", err)
+	}
+
+	base64UrlEncodedHeader, err := header.Encode()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to base64 url encode header: %w", err)
+	}
+
+	var toSign []byte
+	if o.unencoded {
+		toSign = append([]byte(base64UrlEncodedHeader+"."), payload...)
 	} else {
-		compactJWS = toSign + "." + base64UrlEncodedSignature
+		toSign = []byte(base64UrlEncodedHeader + "." + base64UrlEncodedPayload)
 	}
 
-	return compactJWS, nil
+	signature, err := sign(toSign)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to compute signature: %w", err)
+	}
+
+	if o.recoverable {
+		signature, err = ecdsa.SECP256K1ToRecoverableSignature(toSign, signature, publicKey)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to compute recoverable signature: %w", err)
+		}
+	}
+
+	return base64UrlEncodedHeader, base64.RawURLEncoding.EncodeToString(signature), nil
 }
 
 // Verify verifies the given compactJWS by resolving the DID Document from the kid header value
 // and using the associated public key found by resolving the DID Document
 func Verify(compactJWS string, opts ...DecodeOption) (Decoded, error) {
+	return VerifyWithContext(context.Background(), compactJWS, opts...)
+}
+
+// VerifyWithContext verifies the given compactJWS like [Verify]. The context is passed on to DID resolution
+func VerifyWithContext(ctx context.Context, compactJWS string, opts ...DecodeOption) (Decoded, error) {
 	decodedJWS, err := Decode(compactJWS, opts...)
 	if err != nil {
 		return decodedJWS, fmt.Errorf("signature verification failed: %w", err)
 	}
 
-	err = decodedJWS.Verify()
+	err = decodedJWS.VerifyWithContext(ctx, opts...)
 
 	return decodedJWS, err
 }
 
-// Decoded is a compact JWS decoded into its parts
+// Decoded is a compact JWS, or a single signature of a JWS JSON serialization, decoded into its parts
 type Decoded struct {
 	Header    Header
 	Payload   []byte
 	Signature []byte
 	Parts     []string
+	// SignerDID is the DID of the kid. It is only empty if the [Keys] option was used and the kid isn't a DID URL
 	SignerDID _did.DID
+	// UnprotectedHeader is the unprotected header of a signature decoded from a JWS JSON serialization. It is
+	// not covered by the signature. Always nil for compact JWS
+	UnprotectedHeader map[string]any
 }
 
 // Verify verifies the given compactJWS by resolving the DID Document from the kid header value
-// and using the associated public key found by resolving the DID Document
-func (jws Decoded) Verify() error {
-	if jws.Header.ALG == "" || jws.Header.KID == "" {
+// and using the associated public key found by resolving the DID Document. The alg header must match the
+// algorithm of the public key. Only the [Keys] and [Resolver] options apply here. [Keys] takes precedence over
+// [Resolver]
+func (jws Decoded) Verify(opts ...DecodeOption) error {
+	return jws.VerifyWithContext(context.Background(), opts...)
+}
+
+// VerifyWithContext verifies the JWS like [Decoded.Verify]. The context is passed on to DID resolution
+func (jws Decoded) VerifyWithContext(ctx context.Context, opts ...DecodeOption) error {
+	o := decodeOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if jws.Header.ALG == "" || (jws.Header.KID == "" && o.keys == nil) {
 		return errors.New("malformed JWS header. alg and kid are required")
 	}
 
-	did, err := _did.Parse(jws.Header.KID)
+	publicKey, err := o.keyLookup().LookupKey(ctx, jws.Header)
 	if err != nil {
-		return errors.New("malformed JWS header. kid must be a DID URL")
+		return err
+	}
+
+	if err := checkAlg(jws.Header.ALG, publicKey); err != nil {
+		return err
+	}
+
+	toVerify := jws.signingInput()
+
+	if jws.Header.ALG == ecdsa.SECP256K1RecoverableJWA {
+		return verifyRecoverable(toVerify, jws.Signature, publicKey)
 	}
 
-	resolutionResult, err := dids.Resolve(did.URI)
+	verified, err := dsa.Verify(toVerify, jws.Signature, publicKey)
 	if err != nil {
-		return fmt.Errorf("failed to resolve DID: %w", err)
+		return fmt.Errorf("failed to verify signature: %w", err)
+	}
+
+	if !verified {
+		return errors.New("invalid signature")
+	}
+
+	return nil
+}
+
+// signingInput returns the JWS Signing Input, which contains the raw payload instead of its base64url
+// encoding if the b64 header is false
+func (jws Decoded) signingInput() []byte {
+	if jws.Header.isUnencoded() {
+		return append([]byte(jws.Parts[0]+"."), jws.Payload...)
+	}
+
+	return []byte(jws.Parts[0] + "." + jws.Parts[1])
+}
+
+// VerifyAll verifies many decoded JWSs at once and returns an error for each of them, in the same order.
+// A nil error means that the JWS at that index was verified successfully. This is considerably faster than
+// calling [Decoded.Verify] on each JWS: every DID is only resolved once and signatures are checked with
+// [dsa.BatchVerify]. Only the [Keys] and [Resolver] options apply here. [Keys] takes precedence over [Resolver]
+func VerifyAll(decoded []Decoded, opts ...DecodeOption) []error {
+	return VerifyAllWithContext(context.Background(), decoded, opts...)
+}
+
+// VerifyAllWithContext verifies many decoded JWSs at once like [VerifyAll]. The context is passed on to DID resolution
+func VerifyAllWithContext(ctx context.Context, decoded []Decoded, opts ...DecodeOption) []error {
+	o := decodeOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	errs := make([]error, len(decoded))
+
+	type resolved struct {
+		publicKey jwk.JWK
+		err       error
+	}
+
+	// keys are looked up by kid and alg
+	type lookup struct {
+		kid string
+		alg string
+	}
+
+	keys := o.keyLookup()
+	resolvedKeys := make(map[lookup]resolved)
+
+	items := make([]dsa.BatchItem, 0, len(decoded))
+	indices := make([]int, 0, len(decoded))
+
+	for i, jws := range decoded {
+		if jws.Header.ALG == "" || (jws.Header.KID == "" && o.keys == nil) {
+			errs[i] = errors.New("malformed JWS header. alg and kid are required")
+			continue
+		}
+
+		l := lookup{kid: jws.Header.KID, alg: jws.Header.ALG}
+		r, ok := resolvedKeys[l]
+		if !ok {
+			r.publicKey, r.err = keys.LookupKey(ctx, jws.Header)
+			if r.err == nil {
+				r.err = checkAlg(jws.Header.ALG, r.publicKey)
+			}
+
+			resolvedKeys[l] = r
+		}
+
+		if r.err != nil {
+			errs[i] = r.err
+			continue
+		}
+
+		toVerify := jws.signingInput()
+
+		if jws.Header.ALG == ecdsa.SECP256K1RecoverableJWA {
+			errs[i] = verifyRecoverable(toVerify, jws.Signature, r.publicKey)
+			continue
+		}
+
+		items = append(items, dsa.BatchItem{Payload: toVerify, Signature: jws.Signature, PublicKey: r.publicKey})
+		indices = append(indices, i)
+	}
+
+	for j, result := range dsa.BatchVerify(items) {
+		switch {
+		case result.Err != nil:
+			errs[indices[j]] = fmt.Errorf("failed to verify signature: %w", result.Err)
+		case !result.Verified:
+			errs[indices[j]] = errors.New("invalid signature")
+		}
+	}
+
+	return errs
+}
+
+// resolvePublicKey resolves the DID of the given kid and returns the public key of the verification method
+// it refers to. The DID is resolved with [dids.ResolveWithContext] if no resolver is provided
+func resolvePublicKey(ctx context.Context, resolver didcore.MethodResolver, kid string) (jwk.JWK, error) {
+	did, err := _did.Parse(kid)
+	if err != nil {
+		return jwk.JWK{}, errors.New("malformed JWS header. kid must be a DID URL")
+	}
+
+	var resolutionResult didcore.ResolutionResult
+	if resolver != nil {
+		resolutionResult, err = resolver.ResolveWithContext(ctx, did.URI)
+	} else {
+		resolutionResult, err = dids.ResolveWithContext(ctx, did.URI)
+	}
+	if err != nil {
+		return jwk.JWK{}, fmt.Errorf("failed to resolve DID: %w", err)
 	}
 
 	vmSelector := didcore.ID(did.URL)
 	verificationMethod, err := resolutionResult.Document.SelectVerificationMethod(vmSelector)
 	if err != nil {
-		return fmt.Errorf("kid does not match any verification method %w", err)
+		return jwk.JWK{}, fmt.Errorf("kid does not match any verification method %w", err)
 	}
 
-	toVerify := jws.Parts[0] + "." + jws.Parts[1]
+	return verificationMethod.PublicKey()
+}
 
-	verified, err := dsa.Verify([]byte(toVerify), jws.Signature, *verificationMethod.PublicKeyJwk)
+// checkAlg ensures that the alg header matches the algorithm of the public key, so that the header can't claim
+// a different algorithm than the one the signature is verified with. ES256K-R is accepted for secp256k1 keys
+func checkAlg(alg string, publicKey jwk.JWK) error {
+	jwa, err := dsa.GetJWA(publicKey)
 	if err != nil {
-		return fmt.Errorf("failed to verify signature: %w", err)
+		return fmt.Errorf("failed to determine alg of public key: %w", err)
 	}
 
-	if !verified {
-		return errors.New("invalid signature")
+	if alg == ecdsa.SECP256K1RecoverableJWA && jwa == ecdsa.SECP256K1JWA {
+		return nil
+	}
+
+	if alg != jwa {
+		return fmt.Errorf("alg %s does not match the public key's alg %s", alg, jwa)
 	}
 
 	return nil
 }
 
-// Header represents a JWS (JSON Web Signature) header. See [Specification] for more details.
-// [Specification]: https://datatracker.ietf.org/doc/html/rfc7515#section-4
-type Header struct {
-	// Ide	ntifies the cryptographic algorithm used to secure the JWS. The JWS Signature value is not
-	// valid if the "alg" value does not represent a supported algorithm or if there is not a key for
-	// use with that algorithm associated with the party that digitally signed or MACed the content.
-	//
-	// "alg" values should either be registered in the IANA "JSON Web Signature and Encryption
-	// Algorithms" registry or be a value that contains a Collision-Resistant Name. The "alg" value is
-	// a case-sensitive ASCII string.  This Header Parameter MUST be present and MUST be understood
-	// and processed by implementations.
-	//
-	// [Specification]: https://datatracker.ietf.org/doc/html/rfc7515#section-4.1.1
-	ALG string `json:"alg,omitempty"`
-	// Key ID Header Parameter https://datatracker.ietf.org/doc/html/rfc7515#section-4.1.4
-	KID string `json:"kid,omitempty"`
-	// Type Header Parameter https://datatracker.ietf.org/doc/html/rfc7515#section-4.1.9
-	TYP string `json:"typ,omitempty"`
-}
+// verifyRecoverable verifies an ES256K-R signature by recovering the signer's public key and comparing it
+// to the expected public key. Only the recovery ids 0 and 1 are accepted so that a signature has a single encoding
+func verifyRecoverable(payload []byte, signature []byte, publicKey jwk.JWK) error {
+	if len(signature) == 65 && signature[64] > 1 {
+		return fmt.Errorf("failed to verify signature: invalid recovery id: %d", signature[64])
+	}
 
-// Encode returns the base64url encoded header.
-func (j Header) Encode() (string, error) {
-	bytes, err := json.Marshal(j)
+	recovered, err := dsa.RecoverPublicKey(payload, signature)
 	if err != nil {
-		return "", err
+		return fmt.Errorf("failed to verify signature: %w", err)
 	}
 
-	return base64.RawURLEncoding.EncodeToString(bytes), nil
+	recoveredThumbprint, err := recovered.ComputeThumbprint()
+	if err != nil {
+		return fmt.Errorf("failed to verify signature: %w", err)
+	}
+
+	expectedThumbprint, err := publicKey.ComputeThumbprint()
+	if err != nil {
+		return fmt.Errorf("failed to verify signature: %w", err)
+	}
+
+	if recoveredThumbprint != expectedThumbprint {
+		return errors.New("invalid signature")
+	}
+
+	return nil
 }
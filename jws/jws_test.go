@@ -1,6 +1,7 @@
 package jws_test
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -8,8 +9,12 @@ import (
 	"testing"
 
 	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/crypto/dsa/ecdsa"
+	"github.com/tbd54566975/web5-go/dids/didcore"
 	"github.com/tbd54566975/web5-go/dids/didjwk"
 	"github.com/tbd54566975/web5-go/dids/didweb"
+	"github.com/tbd54566975/web5-go/jwk"
 	"github.com/tbd54566975/web5-go/jws"
 )
 
@@ -275,7 +280,7 @@ func TestDecoded_Verify_Bad(t *testing.T) {
 	assert.NoError(t, err)
 
 	header, err := jws.Header{
-		ALG: "ES256K",
+		ALG: "EdDSA",
 		KID: did.Document.VerificationMethod[0].ID,
 	}.Encode()
 	assert.NoError(t, err)
@@ -320,3 +325,286 @@ func TestVerify_Detached(t *testing.T) {
 
 	assert.Equal(t, payload, decoded.Payload)
 }
+
+func TestSign_UnencodedPayload(t *testing.T) {
+	did, err := didjwk.Create()
+	assert.NoError(t, err)
+
+	payload := []byte{0x00, 0xff, '.', 0x2e}
+
+	compactJWS, err := jws.Sign(payload, did, jws.DetachedPayload(true), jws.UnencodedPayload(true))
+	assert.NoError(t, err)
+
+	parts := strings.Split(compactJWS, ".")
+	assert.Equal(t, "", parts[1], "expected empty payload")
+
+	header, err := jws.DecodeHeader(parts[0])
+	assert.NoError(t, err)
+	assert.Equal(t, false, *header.B64)
+	assert.Equal(t, []string{"b64"}, header.CRIT)
+
+	// the signing input contains the raw payload
+	signature, err := jws.DecodeSignature(parts[2])
+	assert.NoError(t, err)
+
+	publicKey, err := did.Document.VerificationMethod[0].PublicKey()
+	assert.NoError(t, err)
+
+	verified, err := dsa.Verify(append([]byte(parts[0]+"."), payload...), signature, publicKey)
+	assert.NoError(t, err)
+	assert.True(t, verified)
+
+	decoded, err := jws.Verify(compactJWS, jws.Payload(payload))
+	assert.NoError(t, err)
+	assert.Equal(t, payload, decoded.Payload)
+
+	_, err = jws.Verify(compactJWS, jws.Payload([]byte("hi")))
+	assert.Error(t, err)
+
+	_, err = jws.Decode(compactJWS)
+	assert.Error(t, err)
+}
+
+func TestSign_UnencodedPayload_NotDetached(t *testing.T) {
+	did, err := didjwk.Create()
+	assert.NoError(t, err)
+
+	_, err = jws.Sign([]byte("hi"), did, jws.UnencodedPayload(true))
+	assert.Error(t, err)
+}
+
+func TestDecode_Critical(t *testing.T) {
+	did, err := didjwk.Create()
+	assert.NoError(t, err)
+
+	b64 := false
+	vectors := map[string]jws.Header{
+		"unsupported critical parameter": {ALG: "EdDSA", KID: did.URI + "#0", CRIT: []string{"exp"}},
+		"b64 not critical":               {ALG: "EdDSA", KID: did.URI + "#0", B64: &b64},
+	}
+
+	for name, header := range vectors {
+		t.Run(name, func(t *testing.T) {
+			base64UrlEncodedHeader, err := header.Encode()
+			assert.NoError(t, err)
+
+			_, err = jws.Decode(base64UrlEncodedHeader+"..aGk", jws.Payload([]byte("hi")))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSign_ProtectedHeader(t *testing.T) {
+	did, err := didjwk.Create()
+	assert.NoError(t, err)
+
+	publicKey, err := did.Document.VerificationMethod[0].PublicKey()
+	assert.NoError(t, err)
+
+	header := jws.Header{
+		CTY:   "application/json",
+		JWK:   &publicKey,
+		NONCE: "abc",
+		URL:   "https://example.com/acme",
+		CRIT:  []string{"exp"},
+		Extra: map[string]any{"exp": float64(1363284000)},
+	}
+
+	compactJWS, err := jws.Sign([]byte("hi"), did, jws.ProtectedHeader(header), jws.Type("JOSE"))
+	assert.NoError(t, err)
+
+	// exp is critical, so it has to be understood by the verifier
+	_, err = jws.Verify(compactJWS)
+	assert.Error(t, err)
+
+	decoded, err := jws.Verify(compactJWS, jws.AllowCritical("exp"))
+	assert.NoError(t, err)
+	assert.Equal(t, "JOSE", decoded.Header.TYP)
+	assert.Equal(t, "application/json", decoded.Header.CTY)
+	assert.Equal(t, publicKey, *decoded.Header.JWK)
+	assert.Equal(t, "abc", decoded.Header.NONCE)
+	assert.Equal(t, "https://example.com/acme", decoded.Header.URL)
+	assert.Equal(t, map[string]any{"exp": float64(1363284000)}, decoded.Header.Extra)
+}
+
+func TestSign_ProtectedHeader_Invalid(t *testing.T) {
+	did, err := didjwk.Create()
+	assert.NoError(t, err)
+
+	privateKey := jwk.JWK{KTY: jwk.KeyTypeOKP, CRV: "Ed25519", X: "x", D: "d"}
+
+	vectors := map[string]jws.Header{
+		"alg":                 {ALG: "none"},
+		"kid":                 {KID: "did:web:example.com#0"},
+		"private jwk":         {JWK: &privateKey},
+		"missing critical":    {CRIT: []string{"exp"}},
+		"registered critical": {CTY: "json", CRIT: []string{"cty"}},
+		"conflicting custom":  {Extra: map[string]any{"typ": "JWT"}},
+	}
+
+	for name, header := range vectors {
+		t.Run(name, func(t *testing.T) {
+			_, err := jws.Sign([]byte("hi"), did, jws.ProtectedHeader(header))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSign_Recoverable(t *testing.T) {
+	did, err := didjwk.Create(didjwk.AlgorithmID(dsa.AlgorithmIDSECP256K1))
+	assert.NoError(t, err)
+
+	payload := []byte("hi")
+
+	compactJWS, err := jws.Sign(payload, did, jws.Recoverable(true))
+	assert.NoError(t, err)
+
+	decoded, err := jws.Verify(compactJWS)
+	assert.NoError(t, err)
+	assert.Equal(t, ecdsa.SECP256K1RecoverableJWA, decoded.Header.ALG)
+	assert.Equal(t, 65, len(decoded.Signature))
+
+	recovered, err := dsa.RecoverPublicKey([]byte(decoded.Parts[0]+"."+decoded.Parts[1]), decoded.Signature)
+	assert.NoError(t, err)
+	assert.Equal(t, *did.Document.VerificationMethod[0].PublicKeyJwk, recovered)
+}
+
+func TestVerify_SECP256K1SignatureEncoding(t *testing.T) {
+	did, err := didjwk.Create(didjwk.AlgorithmID(dsa.AlgorithmIDSECP256K1))
+	assert.NoError(t, err)
+
+	compactJWS, err := jws.Sign([]byte("hi"), did)
+	assert.NoError(t, err)
+
+	// an ES256K signature with a trailing byte must not verify, regardless of its value
+	decoded, err := jws.Decode(compactJWS)
+	assert.NoError(t, err)
+
+	decoded.Signature = append(decoded.Signature, 0x01)
+	assert.Error(t, decoded.Verify())
+
+	// an ES256K-R signature must not verify with Ethereum's recovery id offset
+	compactJWS, err = jws.Sign([]byte("hi"), did, jws.Recoverable(true))
+	assert.NoError(t, err)
+
+	decoded, err = jws.Decode(compactJWS)
+	assert.NoError(t, err)
+	assert.NoError(t, decoded.Verify())
+
+	decoded.Signature[64] += 27
+	assert.Error(t, decoded.Verify())
+}
+
+func TestSign_Recoverable_UnsupportedKey(t *testing.T) {
+	did, err := didjwk.Create()
+	assert.NoError(t, err)
+
+	_, err = jws.Sign([]byte("hi"), did, jws.Recoverable(true))
+	assert.Error(t, err)
+}
+
+func TestVerifyAll(t *testing.T) {
+	ed25519DID, err := didjwk.Create()
+	assert.NoError(t, err)
+
+	secp256k1DID, err := didjwk.Create(didjwk.AlgorithmID(dsa.AlgorithmIDSECP256K1))
+	assert.NoError(t, err)
+
+	var compactJWSs []string
+	for i := 0; i < 100; i++ {
+		did := ed25519DID
+		if i%10 == 0 {
+			did = secp256k1DID
+		}
+
+		compactJWS, err := jws.Sign([]byte(fmt.Sprintf("payload %d", i)), did, jws.Recoverable(i%20 == 0))
+		assert.NoError(t, err)
+
+		compactJWSs = append(compactJWSs, compactJWS)
+	}
+
+	decoded := make([]jws.Decoded, len(compactJWSs))
+	for i, compactJWS := range compactJWSs {
+		decoded[i], err = jws.Decode(compactJWS)
+		assert.NoError(t, err)
+	}
+
+	errs := jws.VerifyAll(decoded)
+	assert.Equal(t, len(decoded), len(errs))
+	for i, err := range errs {
+		assert.NoError(t, err, "jws %d", i)
+	}
+
+	// tamper with a few payloads
+	for _, i := range []int{3, 20, 57} {
+		decoded[i].Parts[1] = base64.RawURLEncoding.EncodeToString([]byte("tampered"))
+	}
+
+	errs = jws.VerifyAll(decoded)
+	for i, err := range errs {
+		if i == 3 || i == 20 || i == 57 {
+			assert.Error(t, err, "jws %d", i)
+		} else {
+			assert.NoError(t, err, "jws %d", i)
+		}
+	}
+}
+
+func TestVerify_Resolver(t *testing.T) {
+	// did:web DIDs can't be resolved without network access
+	did, err := didweb.Create("localhost:8080")
+	assert.NoError(t, err)
+
+	compactJWS, err := jws.Sign([]byte("hi"), did)
+	assert.NoError(t, err)
+
+	resolver := fixtureResolver{did.URI: did.Document}
+
+	decoded, err := jws.Verify(compactJWS, jws.Resolver(resolver))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hi"), decoded.Payload)
+
+	errs := jws.VerifyAll([]jws.Decoded{decoded}, jws.Resolver(resolver))
+	assert.NoError(t, errs[0])
+
+	_, err = jws.Verify(compactJWS, jws.Resolver(fixtureResolver{}))
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = jws.VerifyWithContext(ctx, compactJWS, jws.Resolver(resolver))
+	assert.IsError(t, err, context.Canceled)
+
+	errs = jws.VerifyAllWithContext(ctx, []jws.Decoded{decoded}, jws.Resolver(resolver))
+	assert.IsError(t, errs[0], context.Canceled)
+
+	jwsJSON, err := jws.SignJSON([]byte("hi"), []jws.Signer{{BearerDID: did}})
+	assert.NoError(t, err)
+
+	_, err = jws.VerifyJSON(jwsJSON, jws.Resolver(resolver))
+	assert.NoError(t, err)
+
+	_, err = jws.VerifyJSONWithContext(ctx, jwsJSON, jws.Resolver(resolver))
+	assert.IsError(t, err, context.Canceled)
+}
+
+// fixtureResolver resolves DIDs from local fixtures instead of the network
+type fixtureResolver map[string]didcore.Document
+
+func (r fixtureResolver) Resolve(uri string) (didcore.ResolutionResult, error) {
+	return r.ResolveWithContext(context.Background(), uri)
+}
+
+func (r fixtureResolver) ResolveWithContext(ctx context.Context, uri string) (didcore.ResolutionResult, error) {
+	if err := ctx.Err(); err != nil {
+		return didcore.ResolutionResultWithError("internalError"), err
+	}
+
+	document, ok := r[uri]
+	if !ok {
+		return didcore.ResolutionResultWithError("notFound"), didcore.ResolutionError{Code: "notFound"}
+	}
+
+	return didcore.ResolutionResultWithDocument(document), nil
+}
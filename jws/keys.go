@@ -0,0 +1,111 @@
+package jws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/tbd54566975/web5-go/dids/didcore"
+	"github.com/tbd54566975/web5-go/jwk"
+)
+
+// KeyLookup looks up the public key to verify a JWS with, based on its protected header. Resolving the kid
+// as a DID URL ([DIDKeys]) is what [Verify] does by default. [StaticKey] and [KeySet] allow verifying JWSs
+// signed with conventional keys, e.g. by OAuth servers, whose kid isn't a DID URL
+type KeyLookup interface {
+	LookupKey(ctx context.Context, header Header) (jwk.JWK, error)
+}
+
+// KeyLookupFunc is an adapter to allow the use of ordinary functions as [KeyLookup]
+type KeyLookupFunc func(ctx context.Context, header Header) (jwk.JWK, error)
+
+// LookupKey calls f(ctx, header)
+func (f KeyLookupFunc) LookupKey(ctx context.Context, header Header) (jwk.JWK, error) {
+	return f(ctx, header)
+}
+
+// Keys can be passed to [Decode] or [Verify] to look up the key to verify with using the given [KeyLookup]
+// instead of resolving the kid as a DID URL. Decode then doesn't require the kid to be a DID URL, or to be
+// present at all. Takes precedence over [Resolver]
+func Keys(lookup KeyLookup) DecodeOption {
+	return func(opts *decodeOptions) {
+		opts.keys = lookup
+	}
+}
+
+// DIDKeys returns a [KeyLookup] that resolves the kid as a DID URL using the given resolver and returns the
+// public key of the verification method it refers to. [dids.ResolveWithContext] is used if the resolver is nil
+func DIDKeys(resolver didcore.MethodResolver) KeyLookup {
+	return KeyLookupFunc(func(ctx context.Context, header Header) (jwk.JWK, error) {
+		return resolvePublicKey(ctx, resolver, header.KID)
+	})
+}
+
+// StaticKey returns a [KeyLookup] that always returns the given public key. If both the key and the JWS
+// header have a kid, they must match. Malformed keys and keys whose use or key_ops don't allow verifying
+// signatures are rejected
+func StaticKey(key jwk.JWK) KeyLookup {
+	return KeyLookupFunc(func(_ context.Context, header Header) (jwk.JWK, error) {
+		if key.KID != "" && header.KID != "" && key.KID != header.KID {
+			return jwk.JWK{}, fmt.Errorf("kid %s does not match the key's kid %s", header.KID, key.KID)
+		}
+
+		return key, checkKey(key, header)
+	})
+}
+
+// KeySet returns a [KeyLookup] that selects the key with the kid of the JWS header from the given JWKS. The
+// kid may be omitted if the set contains a single key. Malformed keys and keys whose use or key_ops don't allow
+// verifying signatures, e.g. encryption keys that share the kid of a signing key, are never selected
+func KeySet(jwks jwk.JWKS) KeyLookup {
+	return KeyLookupFunc(func(_ context.Context, header Header) (jwk.JWK, error) {
+		if header.KID == "" {
+			if len(jwks.Keys) != 1 {
+				return jwk.JWK{}, errors.New("malformed JWS header. kid is required to select a key from the key set")
+			}
+
+			return jwks.Keys[0], checkKey(jwks.Keys[0], header)
+		}
+
+		var err error
+		for _, key := range jwks.Keys {
+			if key.KID != header.KID {
+				continue
+			}
+
+			if err = checkKey(key, header); err == nil {
+				return key, nil
+			}
+		}
+
+		if err != nil {
+			return jwk.JWK{}, err
+		}
+
+		return jwk.JWK{}, fmt.Errorf("kid %s does not match any key in the key set", header.KID)
+	})
+}
+
+// checkKey ensures that the key is well-formed and may be used to verify signatures as per its use and key_ops parameters
+// (https://datatracker.ietf.org/doc/html/rfc7517#section-4.2) and that the alg header matches the algorithm
+// the key is intended for, if any
+func checkKey(key jwk.JWK, header Header) error {
+	if err := key.Validate(); err != nil {
+		return fmt.Errorf("invalid key: %w", err)
+	}
+
+	if key.USE != "" && key.USE != "sig" {
+		return fmt.Errorf("key with use %s can't be used to verify signatures", key.USE)
+	}
+
+	if len(key.KeyOps) > 0 && !slices.Contains(key.KeyOps, "verify") {
+		return fmt.Errorf("key with key_ops %v can't be used to verify signatures", key.KeyOps)
+	}
+
+	if key.ALG != "" && key.ALG != header.ALG {
+		return fmt.Errorf("alg %s does not match the key's alg %s", header.ALG, key.ALG)
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package jws_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/crypto/dsa/eddsa"
+	"github.com/tbd54566975/web5-go/dids/didjwk"
+	"github.com/tbd54566975/web5-go/jwk"
+	"github.com/tbd54566975/web5-go/jws"
+)
+
+// signConventional signs the payload like an OAuth server would, with a plain kid instead of a DID URL
+func signConventional(t *testing.T, payload []byte, kid string) (string, jwk.JWK) {
+	t.Helper()
+
+	privateKey, err := dsa.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	header, err := jws.Header{ALG: eddsa.JWA, KID: kid}.Encode()
+	assert.NoError(t, err)
+
+	toSign := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature, err := dsa.Sign([]byte(toSign), privateKey)
+	assert.NoError(t, err)
+
+	publicKey := dsa.GetPublicKey(privateKey)
+	publicKey.KID = kid
+
+	return toSign + "." + base64.RawURLEncoding.EncodeToString(signature), publicKey
+}
+
+func TestVerify_StaticKey(t *testing.T) {
+	compactJWS, publicKey := signConventional(t, []byte("hi"), "partner-key-1")
+
+	// plain kids aren't DID URLs
+	_, err := jws.Decode(compactJWS)
+	assert.Error(t, err)
+
+	decoded, err := jws.Verify(compactJWS, jws.Keys(jws.StaticKey(publicKey)))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hi"), decoded.Payload)
+	assert.Zero(t, decoded.SignerDID)
+
+	publicKey.KID = "other-key"
+	_, err = jws.Verify(compactJWS, jws.Keys(jws.StaticKey(publicKey)))
+	assert.Error(t, err)
+
+	otherKey, err := dsa.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	_, err = jws.Verify(compactJWS, jws.Keys(jws.StaticKey(dsa.GetPublicKey(otherKey))))
+	assert.Error(t, err)
+}
+
+func TestVerify_KeySet(t *testing.T) {
+	compactJWS, publicKey := signConventional(t, []byte("hi"), "partner-key-1")
+	otherJWS, otherKey := signConventional(t, []byte("bye"), "partner-key-2")
+
+	keySet := jws.KeySet(jwk.JWKS{Keys: []jwk.JWK{publicKey, otherKey}})
+
+	_, err := jws.Verify(compactJWS, jws.Keys(keySet))
+	assert.NoError(t, err)
+
+	errs := jws.VerifyAll([]jws.Decoded{mustDecode(t, compactJWS, keySet), mustDecode(t, otherJWS, keySet)}, jws.Keys(keySet))
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+
+	unknownJWS, _ := signConventional(t, []byte("hi"), "partner-key-3")
+	_, err = jws.Verify(unknownJWS, jws.Keys(keySet))
+	assert.Error(t, err)
+
+	// the alg of a key must match the alg header
+	publicKey.ALG = "ES256K"
+	_, err = jws.Verify(compactJWS, jws.Keys(jws.KeySet(jwk.JWKS{Keys: []jwk.JWK{publicKey}})))
+	assert.Error(t, err)
+}
+
+func TestVerify_KeyUse(t *testing.T) {
+	compactJWS, publicKey := signConventional(t, []byte("hi"), "partner-key-1")
+
+	sigKey := publicKey
+	sigKey.USE = "sig"
+	sigKey.KeyOps = []string{"verify"}
+
+	_, err := jws.Verify(compactJWS, jws.Keys(jws.StaticKey(sigKey)))
+	assert.NoError(t, err)
+
+	encKey := publicKey
+	encKey.USE = "enc"
+
+	_, err = jws.Verify(compactJWS, jws.Keys(jws.StaticKey(encKey)))
+	assert.EqualError(t, err, "key with use enc can't be used to verify signatures")
+
+	_, err = jws.Verify(compactJWS, jws.Keys(jws.KeySet(jwk.JWKS{Keys: []jwk.JWK{encKey}})))
+	assert.Error(t, err)
+
+	wrapKey := publicKey
+	wrapKey.KeyOps = []string{"wrapKey"}
+
+	_, err = jws.Verify(compactJWS, jws.Keys(jws.StaticKey(wrapKey)))
+	assert.EqualError(t, err, "key with key_ops [wrapKey] can't be used to verify signatures")
+
+	// a signing key that shares its kid with an encryption key is still found
+	_, err = jws.Verify(compactJWS, jws.Keys(jws.KeySet(jwk.JWKS{Keys: []jwk.JWK{encKey, sigKey}})))
+	assert.NoError(t, err)
+}
+
+func TestVerify_AlgMismatch(t *testing.T) {
+	privateKey, err := dsa.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	publicKey := dsa.GetPublicKey(privateKey)
+
+	// a valid Ed25519 signature whose header claims a different algorithm
+	for _, alg := range []string{"ES256", "ES256K-R"} {
+		header, err := jws.Header{ALG: alg, KID: "partner-key-1"}.Encode()
+		assert.NoError(t, err)
+
+		toSign := header + "." + base64.RawURLEncoding.EncodeToString([]byte("hi"))
+		signature, err := dsa.Sign([]byte(toSign), privateKey)
+		assert.NoError(t, err)
+
+		compactJWS := toSign + "." + base64.RawURLEncoding.EncodeToString(signature)
+		keys := jws.Keys(jws.StaticKey(publicKey))
+
+		_, err = jws.Verify(compactJWS, keys)
+		assert.EqualError(t, err, "alg "+alg+" does not match the public key's alg EdDSA")
+
+		errs := jws.VerifyAll([]jws.Decoded{mustDecode(t, compactJWS, jws.StaticKey(publicKey))}, keys)
+		assert.Error(t, errs[0])
+	}
+}
+
+func TestVerify_InvalidKey(t *testing.T) {
+	compactJWS, publicKey := signConventional(t, []byte("hi"), "partner-key-1")
+
+	// the key's x isn't base64url encoded
+	publicKey.X = "not base64url!"
+
+	_, err := jws.Verify(compactJWS, jws.Keys(jws.StaticKey(publicKey)))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid key")
+
+	_, err = jws.Verify(compactJWS, jws.Keys(jws.KeySet(jwk.JWKS{Keys: []jwk.JWK{publicKey}})))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid key")
+}
+
+func TestVerify_KeySet_NoKID(t *testing.T) {
+	compactJWS, publicKey := signConventional(t, []byte("hi"), "")
+	_, otherKey := signConventional(t, []byte("hi"), "")
+
+	_, err := jws.Verify(compactJWS, jws.Keys(jws.KeySet(jwk.JWKS{Keys: []jwk.JWK{publicKey}})))
+	assert.NoError(t, err)
+
+	// the key can't be selected without kid
+	_, err = jws.Verify(compactJWS, jws.Keys(jws.KeySet(jwk.JWKS{Keys: []jwk.JWK{publicKey, otherKey}})))
+	assert.Error(t, err)
+}
+
+func TestVerify_DIDKeys(t *testing.T) {
+	did, err := didjwk.Create()
+	assert.NoError(t, err)
+
+	compactJWS, err := jws.Sign([]byte("hi"), did)
+	assert.NoError(t, err)
+
+	decoded, err := jws.Verify(compactJWS, jws.Keys(jws.DIDKeys(nil)))
+	assert.NoError(t, err)
+	assert.Equal(t, did.URI, decoded.SignerDID.URI)
+}
+
+func mustDecode(t *testing.T, compactJWS string, keys jws.KeyLookup) jws.Decoded {
+	t.Helper()
+
+	decoded, err := jws.Decode(compactJWS, jws.Keys(keys))
+	assert.NoError(t, err)
+
+	return decoded
+}
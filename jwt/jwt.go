@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -13,8 +14,11 @@ import (
 	"github.com/tbd54566975/web5-go/jws"
 )
 
-// Decode decodes the 3-part base64url encoded jwt into it's relevant parts
-func Decode(jwt string) (Decoded, error) {
+// Decode decodes the 3-part base64url encoded jwt into it's relevant parts. The kid header has to be a DID URL
+// unless the [Keys] option is provided
+func Decode(jwt string, opts ...VerifyOpt) (Decoded, error) {
+	o := newVerifyOpts(opts)
+
 	parts := strings.Split(jwt, ".")
 	if len(parts) != 3 {
 		return Decoded{}, fmt.Errorf("malformed JWT. Expected 3 parts, got %d", len(parts))
@@ -25,6 +29,15 @@ func Decode(jwt string) (Decoded, error) {
 		return Decoded{}, fmt.Errorf("malformed JWT. Failed to decode header: %w", err)
 	}
 
+	// JWTs must not use unencoded payloads, as per https://datatracker.ietf.org/doc/html/rfc7797#section-7
+	if header.B64 != nil {
+		return Decoded{}, errors.New("malformed JWT. b64 header is not allowed")
+	}
+
+	if err := header.CheckCritical(); err != nil {
+		return Decoded{}, fmt.Errorf("malformed JWT. %w", err)
+	}
+
 	claimsBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
 	if err != nil {
 		return Decoded{}, fmt.Errorf("malformed JWT. Failed to decode claims: %w", err)
@@ -41,9 +54,15 @@ func Decode(jwt string) (Decoded, error) {
 		return Decoded{}, fmt.Errorf("malformed JWT. Failed to decode signature: %w", err)
 	}
 
-	signerDid, err := did.Parse(header.KID)
-	if err != nil {
-		return Decoded{}, fmt.Errorf("malformed JWT. Failed to parse signer DID: %w", err)
+	var signerDid did.DID
+	if o.keys == nil {
+		signerDid, err = did.Parse(header.KID)
+		if err != nil {
+			return Decoded{}, fmt.Errorf("malformed JWT. Failed to parse signer DID: %w", err)
+		}
+	} else if parsed, err := did.Parse(header.KID); err == nil {
+		// keys are looked up by other means, so the kid doesn't have to be a DID URL
+		signerDid = parsed
 	}
 
 	return Decoded{
@@ -116,16 +135,71 @@ func Sign(claims Claims, did did.BearerDID, opts ...SignOpt) (string, error) {
 	return jws.Sign(payload, did, jwsOpts...)
 }
 
+// options that Verify can take
+type verifyOpts struct {
+	resolver didcore.MethodResolver
+	keys     jws.KeyLookup
+}
+
+// VerifyOpt is a type returned by all individual Verify Options.
+type VerifyOpt func(opts *verifyOpts)
+
+// Resolver is an option that can be provided to Verify to resolve the signer's DID with the given
+// resolver instead of [github.com/tbd54566975/web5-go/dids.Resolve], e.g. to verify against local
+// fixtures, a cache or a private registry without network access
+func Resolver(resolver didcore.MethodResolver) VerifyOpt {
+	return func(opts *verifyOpts) {
+		opts.resolver = resolver
+	}
+}
+
+// Keys is an option that can be provided to Decode and Verify to look up the key to verify with using the
+// given [jws.KeyLookup], e.g. [jws.KeySet], instead of resolving the kid as a DID URL. This allows verifying
+// conventional JWTs, e.g. issued by OAuth servers, whose kid isn't a DID URL. Takes precedence over Resolver
+func Keys(lookup jws.KeyLookup) VerifyOpt {
+	return func(opts *verifyOpts) {
+		opts.keys = lookup
+	}
+}
+
+func newVerifyOpts(opts []VerifyOpt) verifyOpts {
+	o := verifyOpts{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// jwsDecodeOptions converts the Verify Options into their jws counterparts
+func (o verifyOpts) jwsDecodeOptions() []jws.DecodeOption {
+	jwsOpts := make([]jws.DecodeOption, 0)
+	if o.resolver != nil {
+		jwsOpts = append(jwsOpts, jws.Resolver(o.resolver))
+	}
+
+	if o.keys != nil {
+		jwsOpts = append(jwsOpts, jws.Keys(o.keys))
+	}
+
+	return jwsOpts
+}
+
 // Verify verifies a JWT (JSON Web Token) as per the spec https://datatracker.ietf.org/doc/html/rfc7519
 // Successful verification means that the JWT has not expired and the signature's integrity is intact
 // Decoded JWT is returned if verification is successful
-func Verify(jwt string) (Decoded, error) {
-	decodedJWT, err := Decode(jwt)
+func Verify(jwt string, opts ...VerifyOpt) (Decoded, error) {
+	return VerifyWithContext(context.Background(), jwt, opts...)
+}
+
+// VerifyWithContext verifies a JWT like [Verify]. The context is passed on to DID resolution
+func VerifyWithContext(ctx context.Context, jwt string, opts ...VerifyOpt) (Decoded, error) {
+	decodedJWT, err := Decode(jwt, opts...)
 	if err != nil {
 		return Decoded{}, err
 	}
 
-	err = decodedJWT.Verify()
+	err = decodedJWT.VerifyWithContext(ctx, opts...)
 
 	return decodedJWT, err
 }
@@ -144,31 +218,31 @@ type Decoded struct {
 }
 
 // Verify verifies a JWT (JSON Web Token)
-func (jwt Decoded) Verify() error {
+func (jwt Decoded) Verify(opts ...VerifyOpt) error {
+	return jwt.VerifyWithContext(context.Background(), opts...)
+}
+
+// VerifyWithContext verifies a JWT like [Decoded.Verify]. The context is passed on to DID resolution
+func (jwt Decoded) VerifyWithContext(ctx context.Context, opts ...VerifyOpt) error {
+	o := newVerifyOpts(opts)
+
 	if jwt.Claims.Expiration != 0 && time.Now().Unix() > jwt.Claims.Expiration {
 		return errors.New("JWT has expired")
 	}
 
-	claimsBytes, err := base64.RawURLEncoding.DecodeString(jwt.Parts[1])
+	decodedJWS, err := jwt.toJWS()
 	if err != nil {
-		return fmt.Errorf("malformed JWT. Failed to decode claims: %w", err)
-	}
-
-	decodedJWS := jws.Decoded{
-		Header:    jwt.Header,
-		Payload:   claimsBytes,
-		Signature: jwt.Signature,
-		Parts:     jwt.Parts,
+		return err
 	}
 
-	err = decodedJWS.Verify()
+	err = decodedJWS.VerifyWithContext(ctx, o.jwsDecodeOptions()...)
 	if err != nil {
 		return fmt.Errorf("JWT signature verification failed: %w", err)
 	}
 
 	// check to ensure that issuer has been set and that it matches the did used to sign.
 	// the value of KID should always be ${did}#${verificationMethodID} (aka did url)
-	if jwt.Claims.Issuer == "" || !strings.HasPrefix(jwt.Header.KID, jwt.Claims.Issuer) {
+	if jwt.isDIDSigned(o) && !jwt.issuerMatchesKID() {
 		return errors.New("JWT issuer does not match the did url provided as KID")
 	}
 
@@ -179,6 +253,82 @@ func (jwt Decoded) Verify() error {
 	return nil
 }
 
+// VerifyAll verifies many decoded JWTs at once and returns an error for each of them, in the same order.
+// A nil error means that the JWT at that index was verified successfully. Signatures are verified in bulk
+// using [jws.VerifyAll], which is considerably faster than calling [Decoded.Verify] on each JWT
+func VerifyAll(decoded []Decoded, opts ...VerifyOpt) []error {
+	return VerifyAllWithContext(context.Background(), decoded, opts...)
+}
+
+// VerifyAllWithContext verifies many decoded JWTs at once like [VerifyAll]. The context is passed on to DID resolution
+func VerifyAllWithContext(ctx context.Context, decoded []Decoded, opts ...VerifyOpt) []error {
+	o := newVerifyOpts(opts)
+	errs := make([]error, len(decoded))
+
+	toVerify := make([]jws.Decoded, 0, len(decoded))
+	indices := make([]int, 0, len(decoded))
+
+	for i, jwt := range decoded {
+		if jwt.Claims.Expiration != 0 && time.Now().Unix() > jwt.Claims.Expiration {
+			errs[i] = errors.New("JWT has expired")
+			continue
+		}
+
+		// unlike Verify, the issuer is checked first as it's cheap and saves resolving the DID
+		if jwt.isDIDSigned(o) && !jwt.issuerMatchesKID() {
+			errs[i] = errors.New("JWT issuer does not match the did url provided as KID")
+			continue
+		}
+
+		decodedJWS, err := jwt.toJWS()
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		toVerify = append(toVerify, decodedJWS)
+		indices = append(indices, i)
+	}
+
+	for j, err := range jws.VerifyAllWithContext(ctx, toVerify, o.jwsDecodeOptions()...) {
+		if err != nil {
+			errs[indices[j]] = fmt.Errorf("JWT signature verification failed: %w", err)
+		}
+	}
+
+	return errs
+}
+
+// isDIDSigned returns whether the JWT is verified with a DID. Conventional JWTs verified with the Keys option
+// whose kid isn't a DID URL have no DID that the issuer could be checked against
+func (jwt Decoded) isDIDSigned(o verifyOpts) bool {
+	if o.keys == nil {
+		return true
+	}
+
+	_, err := did.Parse(jwt.Header.KID)
+
+	return err == nil
+}
+
+func (jwt Decoded) issuerMatchesKID() bool {
+	return jwt.Claims.Issuer != "" && strings.HasPrefix(jwt.Header.KID, jwt.Claims.Issuer)
+}
+
+func (jwt Decoded) toJWS() (jws.Decoded, error) {
+	claimsBytes, err := base64.RawURLEncoding.DecodeString(jwt.Parts[1])
+	if err != nil {
+		return jws.Decoded{}, fmt.Errorf("malformed JWT. Failed to decode claims: %w", err)
+	}
+
+	return jws.Decoded{
+		Header:    jwt.Header,
+		Payload:   claimsBytes,
+		Signature: jwt.Signature,
+		Parts:     jwt.Parts,
+	}, nil
+}
+
 // Claims represents JWT (JSON Web Token) Claims
 //
 // Spec: https://datatracker.ietf.org/doc/html/rfc7519#section-4
@@ -1,12 +1,20 @@
 package jwt_test
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/alecthomas/assert/v2"
+	"github.com/tbd54566975/web5-go/crypto/dsa"
+	"github.com/tbd54566975/web5-go/crypto/dsa/eddsa"
+	"github.com/tbd54566975/web5-go/dids/didcore"
 	"github.com/tbd54566975/web5-go/dids/didjwk"
+	"github.com/tbd54566975/web5-go/dids/didweb"
+	"github.com/tbd54566975/web5-go/jwk"
 	"github.com/tbd54566975/web5-go/jws"
 	"github.com/tbd54566975/web5-go/jwt"
 )
@@ -98,6 +106,44 @@ func TestVerify(t *testing.T) {
 	assert.NotEqual(t, decoded, jwt.Decoded{}, "expected decoded to not be empty")
 }
 
+func TestVerifyAll(t *testing.T) {
+	did, err := didjwk.Create()
+	assert.NoError(t, err)
+
+	other, err := didjwk.Create()
+	assert.NoError(t, err)
+
+	legit, err := jwt.Sign(jwt.Claims{Issuer: did.URI}, did)
+	assert.NoError(t, err)
+
+	expired, err := jwt.Sign(jwt.Claims{Issuer: did.URI, Expiration: time.Now().Add(-time.Hour).Unix()}, did)
+	assert.NoError(t, err)
+
+	forged, err := jwt.Sign(jwt.Claims{Issuer: other.URI}, other)
+	assert.NoError(t, err)
+
+	var decoded []jwt.Decoded
+	for _, input := range []string{legit, expired, legit, forged, legit} {
+		d, err := jwt.Decode(input)
+		assert.NoError(t, err)
+
+		decoded = append(decoded, d)
+	}
+
+	// issuer doesn't match kid
+	decoded[2].Claims.Issuer = other.URI
+	// signature by a different key
+	decoded[3].Signature = decoded[0].Signature
+
+	errs := jwt.VerifyAll(decoded)
+	assert.Equal(t, 5, len(errs))
+	assert.NoError(t, errs[0])
+	assert.Error(t, errs[1])
+	assert.Error(t, errs[2])
+	assert.Error(t, errs[3])
+	assert.NoError(t, errs[4])
+}
+
 func TestVerify_BadClaims(t *testing.T) {
 	okHeader, err := jws.Header{ALG: "ES256K", KID: "did:web:abc#key-1"}.Encode()
 	assert.NoError(t, err)
@@ -109,6 +155,21 @@ func TestVerify_BadClaims(t *testing.T) {
 	assert.Equal(t, jwt.Decoded{}, decoded)
 }
 
+func Test_Decode_UnsupportedCritical(t *testing.T) {
+	did, err := didjwk.Create()
+	assert.NoError(t, err)
+
+	payload, err := json.Marshal(jwt.Claims{Issuer: did.URI})
+	assert.NoError(t, err)
+
+	header := jws.Header{CRIT: []string{"exp"}, Extra: map[string]any{"exp": 1363284000}}
+	signed, err := jws.Sign(payload, did, jws.ProtectedHeader(header))
+	assert.NoError(t, err)
+
+	_, err = jwt.Decode(signed)
+	assert.Error(t, err)
+}
+
 func Test_Decode_Empty(t *testing.T) {
 	decoded, err := jwt.Decode("")
 	assert.Error(t, err)
@@ -140,4 +201,83 @@ func Test_Decode_HeaderKID_InvalidDID(t *testing.T) {
 	vcJwt := `eyJhbGciOiJFZERTQSIsImtpZCI6Imtha2EiLCJ0eXAiOiJKV1QifQ.eyJleHAiOjE3MjQ1MzQwNTAsImlzcyI6ImRpZDpqd2s6ZXlKcmRIa2lPaUpQUzFBaUxDSmpjbllpT2lKRlpESTFOVEU1SWl3aWVDSTZJbkY0VjFGS2F6RTJSbWhCZWtOQlRsRktaR1F5UTFkRldrcE9lbXBSYjNGSmRYWk5SbUpVWjFKTVNFRWlmUSIsImp0aSI6InVybjp2Yzp1dWlkOjlkMzdmMzY3LWE4ZDctNDY4Zi05NGYwLTk1NzAxNzBkNzZhNCIsIm5iZiI6MTcyMTk0MjA1MCwidmMiOnsiQGNvbnRleHQiOlsiaHR0cHM6Ly93d3cudzMub3JnLzIwMTgvY3JlZGVudGlhbHMvdjEiXSwidHlwZSI6WyJWZXJpZmlhYmxlQ3JlZGVudGlhbCJdLCJpc3N1ZXIiOiJkaWQ6andrOmV5SnJkSGtpT2lKUFMxQWlMQ0pqY25ZaU9pSkZaREkxTlRFNUlpd2llQ0k2SW5GNFYxRkthekUyUm1oQmVrTkJUbEZLWkdReVExZEZXa3BPZW1wUmIzRkpkWFpOUm1KVVoxSk1TRUVpZlEiLCJjcmVkZW50aWFsU3ViamVjdCI6eyJpc3N1ZXIiOiJkaWQ6andrOmV5SnJkSGtpT2lKUFMxQWlMQ0pqY25ZaU9pSkZaREkxTlRFNUlpd2llQ0k2SW5GNFYxRkthekUyUm1oQmVrTkJUbEZLWkdReVExZEZXa3BPZW1wUmIzRkpkWFpOUm1KVVoxSk1TRUVpZlEifSwiaWQiOiJ1cm46dmM6dXVpZDo5ZDM3ZjM2Ny1hOGQ3LTQ2OGYtOTRmMC05NTcwMTcwZDc2YTQiLCJpc3N1YW5jZURhdGUiOiIyMDI0LTA3LTI1VDIxOjE0OjEwWiIsImV4cGlyYXRpb25EYXRlIjoiMjAyNC0wOC0yNFQyMToxNDoxMFoiLCJjcmVkZW50aWFsU2NoZW1hIjpbeyJ0eXBlIjoiSnNvblNjaGVtYSIsImlkIjoiaHR0cHM6Ly92Yy5zY2hlbWFzLmhvc3Qva2JjLnNjaGVtYS5qc29uIn1dfX0.VwvrU5Lmv3rn9rzXB0OCxe-MtE5R0876pXsXNLRuQjoqSNB5tBv_12NqrobwA-LkMzFwzdQ5-LWJni6grGdXCQ`
 	_, err := jwt.Decode(vcJwt)
 	assert.Error(t, err)
-}
\ No newline at end of file
+}
+
+func TestVerify_Resolver(t *testing.T) {
+	// did:web DIDs can't be resolved without network access
+	did, err := didweb.Create("localhost:8080")
+	assert.NoError(t, err)
+
+	signedJWT, err := jwt.Sign(jwt.Claims{}, did)
+	assert.NoError(t, err)
+
+	resolver := fixtureResolver{did.URI: did.Document}
+
+	decoded, err := jwt.Verify(signedJWT, jwt.Resolver(resolver))
+	assert.NoError(t, err)
+	assert.Equal(t, did.URI, decoded.Claims.Issuer)
+
+	errs := jwt.VerifyAll([]jwt.Decoded{decoded}, jwt.Resolver(resolver))
+	assert.NoError(t, errs[0])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = jwt.VerifyWithContext(ctx, signedJWT, jwt.Resolver(resolver))
+	assert.IsError(t, err, context.Canceled)
+
+	errs = jwt.VerifyAllWithContext(ctx, []jwt.Decoded{decoded}, jwt.Resolver(resolver))
+	assert.IsError(t, errs[0], context.Canceled)
+}
+
+func TestVerify_Keys(t *testing.T) {
+	// a conventional JWT, e.g. issued by an OAuth server, with a plain kid
+	privateKey, err := dsa.GeneratePrivateKey(dsa.AlgorithmIDED25519)
+	assert.NoError(t, err)
+
+	header, err := jws.Header{ALG: eddsa.JWA, KID: "auth-key-1", TYP: "JWT"}.Encode()
+	assert.NoError(t, err)
+
+	claims, err := json.Marshal(jwt.Claims{Issuer: "https://auth.example.com", Subject: "alice"})
+	assert.NoError(t, err)
+
+	toSign := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	signature, err := dsa.Sign([]byte(toSign), privateKey)
+	assert.NoError(t, err)
+
+	signedJWT := toSign + "." + base64.RawURLEncoding.EncodeToString(signature)
+
+	publicKey := dsa.GetPublicKey(privateKey)
+	publicKey.KID = "auth-key-1"
+	keys := jwt.Keys(jws.KeySet(jwk.JWKS{Keys: []jwk.JWK{publicKey}}))
+
+	_, err = jwt.Verify(signedJWT)
+	assert.Error(t, err)
+
+	decoded, err := jwt.Verify(signedJWT, keys)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", decoded.Claims.Subject)
+
+	errs := jwt.VerifyAll([]jwt.Decoded{decoded}, keys)
+	assert.NoError(t, errs[0])
+}
+
+// fixtureResolver resolves DIDs from local fixtures instead of the network
+type fixtureResolver map[string]didcore.Document
+
+func (r fixtureResolver) Resolve(uri string) (didcore.ResolutionResult, error) {
+	return r.ResolveWithContext(context.Background(), uri)
+}
+
+func (r fixtureResolver) ResolveWithContext(ctx context.Context, uri string) (didcore.ResolutionResult, error) {
+	if err := ctx.Err(); err != nil {
+		return didcore.ResolutionResultWithError("internalError"), err
+	}
+
+	document, ok := r[uri]
+	if !ok {
+		return didcore.ResolutionResultWithError("notFound"), didcore.ResolutionError{Code: "notFound"}
+	}
+
+	return didcore.ResolutionResultWithDocument(document), nil
+}
@@ -1,6 +1,7 @@
 package vc
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,14 +12,60 @@ import (
 )
 
 // Verify decodes and verifies the vc-jwt. It checks for the presence of required fields and verifies the jwt.
-// It returns the decoded vc-jwt and the verification result.
-func Verify[T CredentialSubject](vcJWT string) (DecodedVCJWT[T], error) {
+// It returns the decoded vc-jwt and the verification result. Options such as [jwt.Resolver] are passed on to
+// jwt verification.
+func Verify[T CredentialSubject](vcJWT string, opts ...jwt.VerifyOpt) (DecodedVCJWT[T], error) {
+	return VerifyWithContext[T](context.Background(), vcJWT, opts...)
+}
+
+// VerifyWithContext decodes and verifies the vc-jwt like [Verify]. The context is passed on to DID resolution.
+func VerifyWithContext[T CredentialSubject](ctx context.Context, vcJWT string, opts ...jwt.VerifyOpt) (DecodedVCJWT[T], error) {
 	decoded, err := Decode[T](vcJWT)
 	if err != nil {
 		return decoded, err
 	}
 
-	return decoded, decoded.Verify()
+	return decoded, decoded.VerifyWithContext(ctx, opts...)
+}
+
+// VerifyAll decodes and verifies many vc-jwts at once. It returns the decoded vc-jwts and an error for each of
+// them, in the same order. A nil error means that the vc-jwt at that index was verified successfully.
+// Signatures are verified in bulk using [jwt.VerifyAll], which is considerably faster than calling [Verify]
+// for each vc-jwt
+func VerifyAll[T CredentialSubject](vcJWTs []string, opts ...jwt.VerifyOpt) ([]DecodedVCJWT[T], []error) {
+	return VerifyAllWithContext[T](context.Background(), vcJWTs, opts...)
+}
+
+// VerifyAllWithContext decodes and verifies many vc-jwts at once like [VerifyAll]. The context is passed on to
+// DID resolution.
+func VerifyAllWithContext[T CredentialSubject](ctx context.Context, vcJWTs []string, opts ...jwt.VerifyOpt) ([]DecodedVCJWT[T], []error) {
+	decoded := make([]DecodedVCJWT[T], len(vcJWTs))
+	errs := make([]error, len(vcJWTs))
+
+	toVerify := make([]jwt.Decoded, 0, len(vcJWTs))
+	indices := make([]int, 0, len(vcJWTs))
+
+	for i, vcJWT := range vcJWTs {
+		decoded[i], errs[i] = Decode[T](vcJWT)
+		if errs[i] != nil {
+			continue
+		}
+
+		if errs[i] = decoded[i].validate(); errs[i] != nil {
+			continue
+		}
+
+		toVerify = append(toVerify, decoded[i].JWT)
+		indices = append(indices, i)
+	}
+
+	for j, err := range jwt.VerifyAllWithContext(ctx, toVerify, opts...) {
+		if err != nil {
+			errs[indices[j]] = fmt.Errorf("integrity check mismatch: %w", err)
+		}
+	}
+
+	return decoded, errs
 }
 
 // Decode decodes a vc-jwt as per the [spec] and returns [DecodedVCJWT].
@@ -95,7 +142,27 @@ type DecodedVCJWT[T CredentialSubject] struct {
 }
 
 // Verify verifies the decoded vc-jwt. It checks for the presence of required fields and verifies the jwt.
-func (vcjwt DecodedVCJWT[T]) Verify() error {
+func (vcjwt DecodedVCJWT[T]) Verify(opts ...jwt.VerifyOpt) error {
+	return vcjwt.VerifyWithContext(context.Background(), opts...)
+}
+
+// VerifyWithContext verifies the decoded vc-jwt like [DecodedVCJWT.Verify]. The context is passed on to DID
+// resolution.
+func (vcjwt DecodedVCJWT[T]) VerifyWithContext(ctx context.Context, opts ...jwt.VerifyOpt) error {
+	if err := vcjwt.validate(); err != nil {
+		return err
+	}
+
+	err := vcjwt.JWT.VerifyWithContext(ctx, opts...)
+	if err != nil {
+		return fmt.Errorf("integrity check mismatch: %w", err)
+	}
+
+	return nil
+}
+
+// validate checks for the presence of required fields and the validity period of the vc
+func (vcjwt DecodedVCJWT[T]) validate() error {
 	if vcjwt.JWT.Header.TYP != "JWT" {
 		return errors.New("invalid typ")
 	}
@@ -148,10 +215,5 @@ func (vcjwt DecodedVCJWT[T]) Verify() error {
 		return fmt.Errorf("missing base @context: %s", BaseContext)
 	}
 
-	err = vcjwt.JWT.Verify()
-	if err != nil {
-		return fmt.Errorf("integrity check mismatch: %w", err)
-	}
-
 	return nil
 }
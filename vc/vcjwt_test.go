@@ -1,13 +1,16 @@
 package vc_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/alecthomas/assert/v2"
 	"github.com/tbd54566975/web5-go"
+	"github.com/tbd54566975/web5-go/dids/didcore"
 	"github.com/tbd54566975/web5-go/dids/didjwk"
+	"github.com/tbd54566975/web5-go/dids/didweb"
 	"github.com/tbd54566975/web5-go/jwt"
 	"github.com/tbd54566975/web5-go/vc"
 )
@@ -166,6 +169,26 @@ func TestVerify(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("VerifyAll", func(t *testing.T) {
+		inputs := make([]string, len(vectors))
+		for i, tt := range vectors {
+			inputs[i] = tt.input
+		}
+
+		decoded, errs := vc.VerifyAll[vc.Claims](inputs)
+		assert.Equal(t, len(vectors), len(decoded))
+		assert.Equal(t, len(vectors), len(errs))
+
+		for i, tt := range vectors {
+			if tt.errors == true {
+				assert.Error(t, errs[i], tt.description)
+			} else {
+				assert.NoError(t, errs[i], tt.description)
+				assert.NotZero(t, decoded[i].VC.ID, tt.description)
+			}
+		}
+	})
 }
 
 func TestVector_Decode(t *testing.T) {
@@ -209,3 +232,52 @@ func TestVector_Verify(t *testing.T) {
 		})
 	}
 }
+
+func TestVerify_Resolver(t *testing.T) {
+	// did:web DIDs can't be resolved without network access
+	issuer, err := didweb.Create("localhost:8080")
+	assert.NoError(t, err)
+
+	credential := vc.Create(vc.Claims{"id": "did:example:subject"})
+
+	vcJWT, err := credential.Sign(issuer)
+	assert.NoError(t, err)
+
+	resolver := fixtureResolver{issuer.URI: issuer.Document}
+
+	decoded, err := vc.Verify[vc.Claims](vcJWT, jwt.Resolver(resolver))
+	assert.NoError(t, err)
+	assert.Equal(t, issuer.URI, decoded.VC.Issuer)
+
+	_, errs := vc.VerifyAll[vc.Claims]([]string{vcJWT}, jwt.Resolver(resolver))
+	assert.NoError(t, errs[0])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = vc.VerifyWithContext[vc.Claims](ctx, vcJWT, jwt.Resolver(resolver))
+	assert.IsError(t, err, context.Canceled)
+
+	_, errs = vc.VerifyAllWithContext[vc.Claims](ctx, []string{vcJWT}, jwt.Resolver(resolver))
+	assert.IsError(t, errs[0], context.Canceled)
+}
+
+// fixtureResolver resolves DIDs from local fixtures instead of the network
+type fixtureResolver map[string]didcore.Document
+
+func (r fixtureResolver) Resolve(uri string) (didcore.ResolutionResult, error) {
+	return r.ResolveWithContext(context.Background(), uri)
+}
+
+func (r fixtureResolver) ResolveWithContext(ctx context.Context, uri string) (didcore.ResolutionResult, error) {
+	if err := ctx.Err(); err != nil {
+		return didcore.ResolutionResultWithError("internalError"), err
+	}
+
+	document, ok := r[uri]
+	if !ok {
+		return didcore.ResolutionResultWithError("notFound"), didcore.ResolutionError{Code: "notFound"}
+	}
+
+	return didcore.ResolutionResultWithDocument(document), nil
+}